@@ -4,3 +4,6 @@ import "embed"
 
 //go:embed ships/*.json
 var Ships embed.FS
+
+//go:embed content/*.json
+var Content embed.FS
@@ -0,0 +1,310 @@
+// Command spacehole-sim runs the simulation headlessly for balance testing.
+// It builds a Sim per seed, drives it with an input policy for a fixed number
+// of ticks and writes a JSON summary. No window or GPU is required.
+//
+// With -replay it instead re-runs a replay recorded by `spacehole -record`
+// and checks that the final state hash matches.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/spacehole-rogue/spacehole_rogue/assets"
+	"github.com/spacehole-rogue/spacehole_rogue/internal/game"
+	"github.com/spacehole-rogue/spacehole_rogue/internal/world"
+)
+
+// runSummary is the outcome of a single seeded run.
+type runSummary struct {
+	Seed           int64          `json:"seed"`
+	Ticks          uint64         `json:"ticks"`
+	Goal           string         `json:"goal"`
+	Victory        bool           `json:"victory"`
+	Dead           bool           `json:"dead"`
+	DeathCause     string         `json:"death_cause,omitempty"` // "starvation", "dehydration", "injury", "air" or "other"
+	DeathReason    string         `json:"death_reason,omitempty"`
+	Score          int            `json:"score"` // as the hall of fame would score it
+	Credits        int            `json:"credits"`
+	SystemsVisited int            `json:"systems_visited"`
+	Skills         map[string]int `json:"skills"` // skill name → level
+	Hunger         int            `json:"hunger"`
+	Thirst         int            `json:"thirst"`
+	Fatigue        int            `json:"fatigue"`
+	Health         int            `json:"health"`
+	MaxHealth      int            `json:"max_health"`
+}
+
+// batchSummary aggregates every run in a batch.
+type batchSummary struct {
+	Policy            string       `json:"policy"`
+	TicksPerRun       int          `json:"ticks_per_run"`
+	Runs              int          `json:"runs"`
+	Goal              string       `json:"goal"`
+	Victories         int          `json:"victories"`
+	Deaths            int          `json:"deaths"`
+	StarvationDeaths  int          `json:"starvation_deaths"`
+	DehydrationDeaths int          `json:"dehydration_deaths"`
+	InjuryDeaths      int          `json:"injury_deaths"` // untreated burns, trauma or radiation
+	AirDeaths         int          `json:"air_deaths"`    // suffocation, CO2, cold or heat aboard
+	AvgScore          float64      `json:"avg_score"`
+	AvgCredits        float64      `json:"avg_credits"`
+	AvgSystemsVisited float64      `json:"avg_systems_visited"`
+	AvgDeathTick      float64      `json:"avg_death_tick"` // 0 if nobody died
+	Results           []runSummary `json:"results"`
+}
+
+func main() {
+	seed := flag.Int64("seed", 1, "first seed of the batch")
+	count := flag.Int("runs", 1, "number of consecutive seeds to run")
+	ticks := flag.Int("ticks", 72000, "ticks per run (72000 = one game day)")
+	policyName := flag.String("policy", "survivor", "input policy: idle, random or survivor")
+	prologue := flag.Bool("prologue", false, "start in the prologue instead of skipping it")
+	goalName := flag.String("goal", "survive", "run goal: "+strings.Join(game.GoalKeys(), ", "))
+	workers := flag.Int("workers", runtime.NumCPU(), "runs simulated in parallel")
+	out := flag.String("out", "", "write the JSON summary to this file instead of stdout")
+	replay := flag.String("replay", "", "verify a recorded replay file instead of running a batch")
+	mods := flag.String("mods", "", "directory of content pack mods (must match the run for replays)")
+	flag.Parse()
+
+	content, err := game.LoadContent(*mods)
+	if err != nil {
+		log.Fatalf("content: %v", err)
+	}
+	game.UseContent(content)
+
+	if _, ok := newPolicy(*policyName, 0); !ok {
+		log.Fatalf("unknown policy %q", *policyName)
+	}
+	goal, ok := game.ParseGoal(*goalName)
+	if !ok {
+		log.Fatalf("unknown goal %q", *goalName)
+	}
+
+	data, err := assets.Ships.ReadFile("ships/shuttle.json")
+	if err != nil {
+		log.Fatalf("load shuttle: %v", err)
+	}
+	layout, err := world.LoadShipLayout(data)
+	if err != nil {
+		log.Fatalf("parse shuttle: %v", err)
+	}
+
+	if *replay != "" {
+		os.Exit(runReplay(layout, *replay, *out))
+	}
+
+	results := make([]runSummary, *count)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < max(*workers, 1); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				s := *seed + int64(i)
+				p, _ := newPolicy(*policyName, s)
+				results[i] = runSeed(layout, s, goal, *ticks, *prologue, p)
+			}
+		}()
+	}
+	for i := 0; i < *count; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	writeJSON(*out, summarize(*policyName, goal, *ticks, results))
+}
+
+// replaySummary is the outcome of verifying a replay file.
+type replaySummary struct {
+	Seed     int64      `json:"seed"`
+	Frames   uint64     `json:"frames"`
+	Actions  int        `json:"actions"`
+	Complete bool       `json:"complete"` // false if the recording was cut short
+	Match    bool       `json:"match"`    // final state hash matches the recording
+	Hash     string     `json:"hash"`
+	Final    runSummary `json:"final"`
+}
+
+// runReplay re-runs a replay file and returns the process exit code:
+// 0 if the state hash matches (or the recording was incomplete), 1 otherwise.
+func runReplay(layout *world.ShipLayout, path, out string) int {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("open replay: %v", err)
+	}
+	rp, err := game.ReadReplay(f)
+	f.Close()
+	if err != nil {
+		log.Fatalf("%s: %v", path, err)
+	}
+
+	sim, err := rp.Run(layout)
+	if err != nil && !errors.Is(err, game.ErrReplayMismatch) {
+		log.Fatalf("%s: %v", path, err)
+	}
+	hash, herr := sim.StateHash()
+	if herr != nil {
+		log.Fatalf("hash state: %v", herr)
+	}
+	writeJSON(out, replaySummary{
+		Seed:     rp.Seed,
+		Frames:   rp.Frames,
+		Actions:  len(rp.Actions),
+		Complete: rp.Complete,
+		Match:    err == nil && rp.Complete,
+		Hash:     hash,
+		Final:    summarizeSim(sim, rp.Seed),
+	})
+	if err != nil {
+		log.Print(err)
+		return 1
+	}
+	return 0
+}
+
+// writeJSON writes v as indented JSON to path, or stdout if path is empty.
+func writeJSON(path string, v any) {
+	var w io.Writer = os.Stdout
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			log.Fatalf("create %s: %v", path, err)
+		}
+		defer f.Close()
+		w = f
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		log.Fatalf("write summary: %v", err)
+	}
+}
+
+// runSeed simulates one run and reports how it ended.
+func runSeed(layout *world.ShipLayout, seed int64, goal game.Goal, ticks int, prologue bool, p policy) runSummary {
+	sim := game.NewSimWithPrologue(layout, seed)
+	sim.SetGoal(goal)
+	if !prologue {
+		sim.CompletePrologue()
+		sim.Sector.EnsureSystemMap(sim.Sector.CurrentSystem)
+	}
+
+	for i := 0; i < ticks && !sim.IsGameOver(); i++ {
+		sim.Tick()
+		p.Act(sim)
+		clearSignals(sim)
+	}
+	return summarizeSim(sim, seed)
+}
+
+// summarizeSim reports the state a run ended in.
+func summarizeSim(sim *game.Sim, seed int64) runSummary {
+	r := runSummary{
+		Seed:           seed,
+		Ticks:          sim.Ticks,
+		Goal:           game.GoalName(sim.Goal),
+		Victory:        sim.Victory,
+		Dead:           sim.PlayerDead,
+		DeathReason:    sim.DeathReason,
+		Score:          game.SummarizeRun(sim).Score,
+		Credits:        sim.Resources.Credits,
+		SystemsVisited: sim.Discovery.TotalSystemsVisited,
+		Skills:         make(map[string]int, game.SkillCount),
+		Hunger:         sim.Needs.Hunger,
+		Thirst:         sim.Needs.Thirst,
+		Fatigue:        sim.Needs.Fatigue,
+		Health:         sim.Needs.Health,
+		MaxHealth:      sim.Needs.MaxHealth,
+	}
+	for id := game.SkillID(0); id < game.SkillCount; id++ {
+		r.Skills[game.SkillName(id)] = sim.Skills.Level(id)
+	}
+	if sim.PlayerDead {
+		switch {
+		case sim.Needs.Thirst >= 100:
+			r.DeathCause = "dehydration"
+		case seriouslyInjured(sim):
+			r.DeathCause = "injury"
+		case sim.Needs.Hunger >= 100:
+			r.DeathCause = "starvation"
+		case sim.AirHazard() != "":
+			r.DeathCause = "air"
+		default:
+			r.DeathCause = "other"
+		}
+	}
+	return r
+}
+
+// seriouslyInjured reports whether an injury needed the med bay.
+func seriouslyInjured(sim *game.Sim) bool {
+	_, ok := sim.Injuries.Serious()
+	return ok
+}
+
+// clearSignals drops UI signals the windowed game would have consumed.
+func clearSignals(s *game.Sim) {
+	s.NavActivated = false
+	s.PilotActivated = false
+	s.DockActivated = false
+	s.CargoActivated = false
+	s.ScanActivated = false
+	s.CommsActivated = false
+	s.JumpCompleted = false
+}
+
+// summarize aggregates per-seed results into a batch summary.
+func summarize(policyName string, goal game.Goal, ticks int, results []runSummary) batchSummary {
+	b := batchSummary{
+		Policy:      policyName,
+		Goal:        game.GoalName(goal),
+		TicksPerRun: ticks,
+		Runs:        len(results),
+		Results:     results,
+	}
+	if len(results) == 0 {
+		return b
+	}
+	var score, credits, systems, deathTicks float64
+	for _, r := range results {
+		score += float64(r.Score)
+		credits += float64(r.Credits)
+		systems += float64(r.SystemsVisited)
+		if r.Victory {
+			b.Victories++
+		}
+		if !r.Dead {
+			continue
+		}
+		b.Deaths++
+		deathTicks += float64(r.Ticks)
+		switch r.DeathCause {
+		case "starvation":
+			b.StarvationDeaths++
+		case "dehydration":
+			b.DehydrationDeaths++
+		case "injury":
+			b.InjuryDeaths++
+		case "air":
+			b.AirDeaths++
+		}
+	}
+	n := float64(len(results))
+	b.AvgScore = score / n
+	b.AvgCredits = credits / n
+	b.AvgSystemsVisited = systems / n
+	if b.Deaths > 0 {
+		b.AvgDeathTick = deathTicks / float64(b.Deaths)
+	}
+	return b
+}
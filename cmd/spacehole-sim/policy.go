@@ -0,0 +1,146 @@
+package main
+
+import (
+	"math/rand/v2"
+
+	"github.com/spacehole-rogue/spacehole_rogue/internal/game"
+	"github.com/spacehole-rogue/spacehole_rogue/internal/world"
+)
+
+// policy decides what the player does. Act is called once per tick,
+// after Sim.Tick, mirroring the order of Game.Update.
+type policy interface {
+	Act(s *game.Sim)
+}
+
+// newPolicy builds the named policy for a seed. ok is false for unknown names.
+func newPolicy(name string, seed int64) (p policy, ok bool) {
+	switch name {
+	case "idle":
+		return idlePolicy{}, true
+	case "random":
+		return &randomPolicy{rng: rand.New(rand.NewPCG(uint64(seed), uint64(seed>>16|13)))}, true
+	case "survivor":
+		return &survivorPolicy{}, true
+	default:
+		return nil, false
+	}
+}
+
+// idlePolicy never acts. Useful as a baseline for pure resource drain.
+type idlePolicy struct{}
+
+func (idlePolicy) Act(*game.Sim) {}
+
+// Random policy action interval (one decision per second of game time).
+const randomActInterval = 60
+
+// randomPolicy mashes buttons: moves, interacts, toggles equipment,
+// answers hails and episodes with random options and occasionally jumps.
+type randomPolicy struct {
+	rng *rand.Rand
+}
+
+func (p *randomPolicy) Act(s *game.Sim) {
+	if s.Ticks%randomActInterval != 0 || s.Asleep() {
+		return
+	}
+
+	if s.PendingHail != nil {
+		s.StartEncounter()
+	}
+	if enc := s.ActiveEncounter; enc != nil {
+		s.ResolveEncounterOption(p.rng.IntN(max(len(enc.Options), 1)))
+		s.EndEncounter()
+		return
+	}
+	if ep := s.ActiveEpisode; ep != nil {
+		s.ResolveEpisodeOption(p.rng.IntN(max(len(ep.Options), 1)))
+		s.EndEpisode()
+		return
+	}
+
+	switch p.rng.IntN(10) {
+	case 0, 1, 2, 3, 4:
+		s.TryMovePlayer(p.rng.IntN(3)-1, p.rng.IntN(3)-1)
+	case 5, 6, 7:
+		s.Interact()
+	case 8:
+		s.ToggleEquipment()
+	case 9:
+		target := p.rng.IntN(len(s.Sector.Systems))
+		if target != s.Sector.CurrentSystem {
+			s.NavigateTo(target)
+		}
+	}
+}
+
+// Survivor policy check interval (every 5 seconds of game time).
+const survivorActInterval = 300
+
+// survivorPolicy looks after the player's needs the way a sensible player
+// would: fix broken equipment, treat serious injuries, then toilet, drink, eat, sleep and shower
+// when the need gets pressing. It teleports to the equipment instead of walking,
+// so walking time is ignored.
+type survivorPolicy struct{}
+
+func (p *survivorPolicy) Act(s *game.Sim) {
+	if s.Ticks%survivorActInterval != 0 || s.Asleep() {
+		return
+	}
+	r := &s.Resources
+	n := &s.Needs
+
+	switch {
+	case repairBroken(s):
+	case seriouslyInjured(s):
+		useEquipment(s, world.EquipMedical)
+	case r.TotalWaste() >= 10:
+		useEquipment(s, world.EquipToilet)
+	case n.Thirst >= 50:
+		useEquipment(s, world.EquipDrinkStation)
+	case n.Hunger >= 50:
+		useEquipment(s, world.EquipFoodStation)
+	case n.Fatigue >= 70:
+		useEquipment(s, world.EquipBed)
+	case n.Hygiene >= 60:
+		useEquipment(s, world.EquipShower)
+	}
+}
+
+// useEquipment moves the player onto the first tile with the given
+// equipment and interacts with it. Returns false if the ship has none.
+func useEquipment(s *game.Sim, kind world.EquipmentKind) bool {
+	g := s.Grid
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			if eq := g.GetEquipment(x, y); eq != nil && eq.Kind == kind {
+				s.SetPlayerPos(x, y)
+				s.Interact()
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// repairBroken moves the player onto the first broken piece of equipment,
+// repairs it and switches it back on. Returns false if nothing was repaired.
+func repairBroken(s *game.Sim) bool {
+	g := s.Grid
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			if eq := g.GetEquipment(x, y); eq != nil && eq.IsBroken() {
+				s.SetPlayerPos(x, y)
+				if !s.RepairEquipment() {
+					continue
+				}
+				if eq.PowerMode == world.PowerConstant {
+					s.ToggleEquipment()
+				}
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+//go:build !term
+
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/spacehole-rogue/spacehole_rogue/internal/render"
+)
+
+// The window backend draws with Ebitengine. Build with -tags term for the
+// terminal one instead.
+
+// Key is a keyboard key.
+type Key = ebiten.Key
+
+// Keys the game reads.
+const (
+	Key0      = ebiten.Key0
+	Key1      = ebiten.Key1
+	Key2      = ebiten.Key2
+	Key3      = ebiten.Key3
+	Key4      = ebiten.Key4
+	Key5      = ebiten.Key5
+	Key6      = ebiten.Key6
+	Key7      = ebiten.Key7
+	Key8      = ebiten.Key8
+	Key9      = ebiten.Key9
+	KeyA      = ebiten.KeyA
+	KeyD      = ebiten.KeyD
+	KeyE      = ebiten.KeyE
+	KeyF      = ebiten.KeyF
+	KeyG      = ebiten.KeyG
+	KeyM      = ebiten.KeyM
+	KeyN      = ebiten.KeyN
+	KeyR      = ebiten.KeyR
+	KeyS      = ebiten.KeyS
+	KeyT      = ebiten.KeyT
+	KeyW      = ebiten.KeyW
+	KeyX      = ebiten.KeyX
+	KeyUp     = ebiten.KeyUp
+	KeyDown   = ebiten.KeyDown
+	KeyLeft   = ebiten.KeyLeft
+	KeyRight  = ebiten.KeyRight
+	KeyEnter  = ebiten.KeyEnter
+	KeyEscape = ebiten.KeyEscape
+	KeySpace  = ebiten.KeySpace
+	KeyTab    = ebiten.KeyTab
+	KeyShift  = ebiten.KeyShift
+	KeyF2     = ebiten.KeyF2
+	KeyF5     = ebiten.KeyF5
+	KeyF9     = ebiten.KeyF9
+)
+
+// errQuit ends the game loop cleanly.
+var errQuit = ebiten.Termination
+
+// backend holds what the window needs to draw a frame.
+type backend struct {
+	atlas    *render.FontAtlas
+	renderer *render.GridRenderer
+}
+
+func newBackend() backend {
+	atlas := render.NewFontAtlas()
+	return backend{atlas: atlas, renderer: render.NewGridRenderer(atlas, cellWidth, cellHeight)}
+}
+
+func keyJustPressed(k Key) bool  { return inpututil.IsKeyJustPressed(k) }
+func keyPressed(k Key) bool      { return ebiten.IsKeyPressed(k) }
+func anyKeyJustPressed() bool    { return len(inpututil.AppendJustPressedKeys(nil)) > 0 }
+func cursorPosition() (int, int) { return ebiten.CursorPosition() }
+func actualFPS() float64         { return ebiten.ActualFPS() }
+func actualTPS() float64         { return ebiten.ActualTPS() }
+
+func (g *Game) Draw(screen *ebiten.Image) {
+	g.renderer.Draw(screen, g.buffer)
+	// Render floating sprites (sub-pixel precision) on top of the cell grid
+	for _, s := range g.sprites {
+		g.renderer.DrawFloating(screen, s.Glyph, s.FG, s.PX, s.PY)
+	}
+	// Render tight-spaced UI text on top
+	for _, t := range g.uiText {
+		g.renderer.DrawText(screen, t.X, t.Y, t.Text, t.FG)
+	}
+}
+
+func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return screenWidth, screenHeight
+}
+
+// run opens the window and plays until the game quits.
+func run(g *Game) error {
+	ebiten.SetWindowSize(screenWidth, screenHeight)
+	ebiten.SetWindowTitle(title)
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	return ebiten.RunGame(g)
+}
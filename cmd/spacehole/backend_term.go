@@ -0,0 +1,318 @@
+//go:build term
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spacehole-rogue/spacehole_rogue/internal/render"
+)
+
+// The terminal backend draws with ANSI escape codes and reads keys from
+// stdin, so the game runs over SSH and on machines without a display.
+// Terminals only report key presses, so a key counts as held for a few
+// frames after it last arrived: long enough to bridge auto-repeat.
+
+// Key is a keyboard key.
+type Key int
+
+// Keys the game reads.
+const (
+	KeyNone Key = iota
+	Key0
+	Key1
+	Key2
+	Key3
+	Key4
+	Key5
+	Key6
+	Key7
+	Key8
+	Key9
+	KeyA
+	KeyD
+	KeyE
+	KeyF
+	KeyG
+	KeyM
+	KeyN
+	KeyR
+	KeyS
+	KeyT
+	KeyW
+	KeyX
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyEnter
+	KeyEscape
+	KeySpace
+	KeyTab
+	KeyShift
+	KeyF2
+	KeyF5
+	KeyF9
+	keyCount // sentinel
+)
+
+const (
+	termTPS    = 60 // frames per second, as in the window
+	holdFrames = 8  // frames a key stays held after it last arrived
+)
+
+const (
+	enterScreen = "\x1b[?1049h\x1b[?25l\x1b[?1003h\x1b[?1006h" // alternate screen, hide cursor, report mouse motion
+	leaveScreen = "\x1b[?1006l\x1b[?1003l\x1b[?25h\x1b[?1049l"
+)
+
+// errQuit ends the game loop cleanly.
+var errQuit = errors.New("quit")
+
+// backend holds what the terminal needs to draw a frame.
+type backend struct {
+	screen *render.TermScreen
+}
+
+func newBackend() backend {
+	s := render.NewTermScreen(gridCols, gridRows, cellHeight)
+	ct := os.Getenv("COLORTERM")
+	s.TrueColor = ct == "truecolor" || ct == "24bit"
+	return backend{screen: s}
+}
+
+// termInput is what has arrived on stdin, as the game loop sees it.
+type termInput struct {
+	frame      int
+	lastSeen   [keyCount]int // frame each key last arrived, 0 for never
+	anyKey     bool          // any key arrived this frame
+	mouseX     int           // pixels, -1 until the mouse moves
+	mouseY     int
+	interrupt  bool // Ctrl-C
+	closed     bool // stdin hit EOF
+	fps        float64
+	frameCount int
+	fpsSince   time.Time
+}
+
+var input = termInput{mouseX: -1, mouseY: -1}
+
+func keyJustPressed(k Key) bool { return input.lastSeen[k] == input.frame }
+func keyPressed(k Key) bool {
+	return input.lastSeen[k] > 0 && input.frame-input.lastSeen[k] < holdFrames
+}
+func anyKeyJustPressed() bool    { return input.anyKey }
+func cursorPosition() (int, int) { return input.mouseX, input.mouseY }
+func actualFPS() float64         { return input.fps }
+func actualTPS() float64         { return input.fps }
+
+// keyBytes maps the bytes a terminal sends for plain keys.
+var keyBytes = map[byte]Key{
+	'0': Key0, '1': Key1, '2': Key2, '3': Key3, '4': Key4,
+	'5': Key5, '6': Key6, '7': Key7, '8': Key8, '9': Key9,
+	'a': KeyA, 'd': KeyD, 'e': KeyE, 'f': KeyF, 'g': KeyG, 'm': KeyM,
+	'n': KeyN, 'r': KeyR, 's': KeyS, 't': KeyT, 'w': KeyW, 'x': KeyX,
+	'\r': KeyEnter, '\n': KeyEnter, ' ': KeySpace, '\t': KeyTab,
+}
+
+// shiftedDigits are the US layout's shifted number keys, for Shift+digit.
+const shiftedDigits = ")!@#$%^&*("
+
+// press records a key arriving this frame.
+func (in *termInput) press(k Key) {
+	in.lastSeen[k] = in.frame
+	in.anyKey = true
+}
+
+// parse reads keys and mouse reports out of bytes from stdin.
+func (in *termInput) parse(b []byte) {
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		switch {
+		case c == 0x03:
+			in.interrupt = true
+		case c == 0x1b:
+			i = in.parseEscape(b, i)
+		case c >= 'A' && c <= 'Z':
+			if k, ok := keyBytes[c-'A'+'a']; ok {
+				in.press(k)
+				in.press(KeyShift)
+			}
+		case strings.IndexByte(shiftedDigits, c) >= 0:
+			in.press(Key0 + Key(strings.IndexByte(shiftedDigits, c)))
+			in.press(KeyShift)
+		default:
+			if k, ok := keyBytes[c]; ok {
+				in.press(k)
+			}
+		}
+	}
+}
+
+// parseEscape reads the escape sequence starting at b[i] and returns the
+// index of its last byte. A lone ESC is the Escape key.
+func (in *termInput) parseEscape(b []byte, i int) int {
+	if i+1 >= len(b) || (b[i+1] != '[' && b[i+1] != 'O') {
+		in.press(KeyEscape)
+		return i
+	}
+	// Collect parameters up to the final byte
+	j := i + 2
+	for j < len(b) && (b[j] < 0x40 || b[j] > 0x7e) {
+		j++
+	}
+	if j >= len(b) {
+		return len(b) - 1 // cut short: drop it
+	}
+	params := string(b[i+2 : j])
+	switch b[j] {
+	case 'A':
+		in.press(KeyUp)
+	case 'B':
+		in.press(KeyDown)
+	case 'C':
+		in.press(KeyRight)
+	case 'D':
+		in.press(KeyLeft)
+	case 'Q':
+		in.press(KeyF2)
+	case '~':
+		switch params {
+		case "12":
+			in.press(KeyF2)
+		case "15":
+			in.press(KeyF5)
+		case "20":
+			in.press(KeyF9)
+		}
+	case 'M', 'm':
+		in.parseMouse(params)
+	}
+	return j
+}
+
+// parseMouse reads an SGR mouse report: button;column;row, 1-based.
+func (in *termInput) parseMouse(params string) {
+	f := strings.Split(strings.TrimPrefix(params, "<"), ";")
+	if len(f) != 3 {
+		return
+	}
+	col, err1 := strconv.Atoi(f[1])
+	row, err2 := strconv.Atoi(f[2])
+	if err1 != nil || err2 != nil {
+		return
+	}
+	in.mouseX = (col - 1) * render.TextCharWidth
+	in.mouseY = (row - 1) * cellHeight
+}
+
+// poll starts a new frame with whatever arrived on stdin since the last.
+func (in *termInput) poll(keys <-chan []byte) {
+	in.frame++
+	in.anyKey = false
+	for {
+		select {
+		case b, ok := <-keys:
+			if !ok {
+				in.closed = true
+				return
+			}
+			in.parse(b)
+		default:
+			return
+		}
+	}
+}
+
+// countFrame keeps the frame rate shown in the corner.
+func (in *termInput) countFrame(now time.Time) bool {
+	in.frameCount++
+	if d := now.Sub(in.fpsSince); d >= time.Second {
+		in.fps = float64(in.frameCount) / d.Seconds()
+		in.frameCount = 0
+		in.fpsSince = now
+		return true
+	}
+	return false
+}
+
+// readKeys sends stdin to the game loop a read at a time.
+func readKeys(keys chan<- []byte) {
+	buf := make([]byte, 256)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if n > 0 {
+			keys <- append([]byte(nil), buf[:n]...)
+		}
+		if err != nil {
+			close(keys)
+			return
+		}
+	}
+}
+
+// run takes over the terminal and plays until the game quits.
+func run(g *Game) error {
+	w, h, err := termSize()
+	if err != nil {
+		return fmt.Errorf("terminal: %w", err)
+	}
+	if w < g.screen.Cols || h < g.screen.Rows {
+		log.Printf("terminal is %dx%d; the game needs %dx%d", w, h, g.screen.Cols, g.screen.Rows)
+	}
+	restore, err := makeRaw()
+	if err != nil {
+		return fmt.Errorf("terminal: %w", err)
+	}
+	defer restore()
+	os.Stdout.WriteString(enterScreen)
+	defer os.Stdout.WriteString(leaveScreen)
+
+	keys := make(chan []byte, 64)
+	go readKeys(keys)
+
+	ticker := time.NewTicker(time.Second / termTPS)
+	defer ticker.Stop()
+	input.fpsSince = time.Now()
+	for now := range ticker.C {
+		input.poll(keys)
+		if input.interrupt || input.closed {
+			return nil
+		}
+		if err := g.Update(); err != nil {
+			if err == errQuit {
+				return nil
+			}
+			return err
+		}
+		if input.countFrame(now) {
+			// Once a second, catch the terminal being resized
+			if nw, nh, err := termSize(); err == nil && (nw != w || nh != h) {
+				w, h = nw, nh
+				g.screen.Invalidate()
+			}
+		}
+		g.draw()
+		if err := g.screen.Flush(os.Stdout); err != nil {
+			return fmt.Errorf("terminal: %w", err)
+		}
+	}
+	return nil
+}
+
+// draw puts the frame on the terminal screen, as Draw does in the window.
+func (g *Game) draw() {
+	g.screen.Draw(g.buffer)
+	for _, s := range g.sprites {
+		g.screen.DrawFloating(s.Glyph, s.FG, s.PX, s.PY)
+	}
+	for _, t := range g.uiText {
+		g.screen.DrawText(t.X, t.Y, t.Text, t.FG)
+	}
+}
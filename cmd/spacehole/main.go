@@ -1,13 +1,14 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
+	"math"
+	"os"
 	"strings"
 	"time"
 
-	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/spacehole-rogue/spacehole_rogue/assets"
 	"github.com/spacehole-rogue/spacehole_rogue/internal/game"
 	"github.com/spacehole-rogue/spacehole_rogue/internal/render"
@@ -37,6 +38,12 @@ const (
 	commsMax = 8  // max visible messages
 )
 
+// savePath is where F5 quicksaves and F9 quickloads the current run.
+const savePath = "spacehole.sav"
+
+// scoresPath is the hall of fame: the best score for each seed and goal.
+const scoresPath = "spacehole.scores"
+
 // ViewMode controls which screen is displayed.
 type ViewMode int
 
@@ -50,6 +57,9 @@ const (
 	ViewEncounter
 	ViewEpisode
 	ViewSurface
+	ViewGalaxy
+	ViewRunOver
+	ViewGoalSelect
 )
 
 // Station submenu states.
@@ -61,6 +71,7 @@ const (
 	stMenuSell     = 4
 	stMenuBar      = 5
 	stMenuFaction  = 6
+	stMenuMissions = 7
 )
 
 // floatingSprite is a glyph drawn at sub-pixel screen coordinates,
@@ -78,13 +89,15 @@ type uiTextCmd struct {
 	FG   uint8
 }
 
-// Game is the Ebitengine game struct. It owns rendering and input.
-// All gameplay state lives in sim.
+// Game owns rendering and input; the backend puts its frames in a window
+// or a terminal. All gameplay state lives in sim.
 type Game struct {
-	atlas    *render.FontAtlas
-	renderer *render.GridRenderer
+	backend
 	buffer   *render.CellBuffer
 	sim      *game.Sim
+	rec      *game.Recorder // non-nil while recording a replay
+	policy   game.TickPolicy
+	recFile  *os.File
 	viewMode ViewMode
 	sprites  []floatingSprite // sub-tile sprites drawn on top of CellBuffer
 	uiText   []uiTextCmd      // tight-spaced UI text drawn after buffer
@@ -92,9 +105,18 @@ type Game struct {
 	// Character sheet
 	prevViewMode ViewMode // view to return to from char sheet
 
+	// Goal select: the goal highlighted before the run starts
+	goalCursor game.Goal
+
 	// Station docking state
 	stationMenu int               // current station submenu (stMenu* constants)
 	stationData *game.StationData // current docked station (nil when not docked)
+
+	// Run over: the final score and the hall of fame it was entered into
+	runSummary *game.RunSummary
+	hallOfFame *game.HallOfFame
+	runRank    int    // place in the hall of fame, 0 if it didn't make it
+	scoreErr   string // why the hall of fame couldn't be saved, if it couldn't
 }
 
 // Text adds a tight-spaced text command using cell coordinates.
@@ -108,9 +130,20 @@ func (g *Game) Text(cellX, cellY int, s string, fg uint8) {
 	})
 }
 
-func NewGame() *Game {
-	atlas := render.NewFontAtlas()
-	renderer := render.NewGridRenderer(atlas, cellWidth, cellHeight)
+// act applies a player action to the sim, recording it if a replay is being made.
+func (g *Game) act(a game.Action) bool {
+	if g.rec != nil {
+		g.rec.Record(a)
+	}
+	if !g.sim.Apply(a) {
+		return false
+	}
+	g.policy.OnAction(a)
+	return true
+}
+
+// NewGame starts a new run from seed.
+func NewGame(seed int64) *Game {
 	buffer := render.NewCellBuffer(gridCols, gridRows)
 
 	// Load shuttle layout from embedded assets
@@ -123,17 +156,15 @@ func NewGame() *Game {
 		log.Fatalf("parse shuttle: %v", err)
 	}
 
-	// Generate a seed from current time for this run
-	seed := time.Now().UnixNano()
 	sim := game.NewSimWithPrologue(layout, seed)
 
 	g := &Game{
-		atlas:    atlas,
-		renderer: renderer,
 		buffer:   buffer,
 		sim:      sim,
-		viewMode: ViewSurface, // Start on surface during prologue
+		policy:   game.RealTime{},
+		viewMode: ViewGoalSelect, // Pick a goal, then start on the surface for the prologue
 	}
+	g.backend = newBackend()
 
 	g.drawScreen()
 	return g
@@ -171,9 +202,18 @@ func (g *Game) drawScreen() {
 		g.drawEpisodeView()
 	case ViewSurface:
 		g.drawSurfaceView()
+	case ViewGalaxy:
+		g.drawGalaxyView()
+	case ViewRunOver:
+		g.drawRunOverView()
+	case ViewGoalSelect:
+		g.drawGoalSelectView()
 	default:
 		g.drawShipView()
 	}
+	if _, ok := g.policy.(*game.TurnBased); ok {
+		g.Text(gridCols/2-4, 0, "[ TURN-BASED ]", render.ColorYellow)
+	}
 }
 
 func (g *Game) drawShipView() {
@@ -188,10 +228,19 @@ func (g *Game) drawShipView() {
 	// --- World layer (camera-relative) ---
 	ox := g.cameraX()
 	oy := g.cameraY()
-	render.RenderTileGrid(buf, g.sim.Grid, ox, oy)
+	render.RenderTileGridLit(buf, g.sim.Grid, ox, oy, g.sim.ShipLit)
+	for _, h := range g.sim.Breaches() {
+		if g.sim.ShipLit(h[0], h[1]) {
+			buf.Set(ox+h[0], oy+h[1], '#', render.ColorLightRed, render.ColorBlack) // holed hull
+		}
+	}
 
 	// Player — always at viewport center
-	buf.Set(viewCenterX, viewCenterY, '@', render.ColorWhite, render.ColorBlack)
+	if g.sim.Asleep() {
+		buf.Set(viewCenterX, viewCenterY, 'z', render.ColorLightBlue, render.ColorBlack)
+	} else {
+		buf.Set(viewCenterX, viewCenterY, '@', render.ColorWhite, render.ColorBlack)
+	}
 
 	// --- Fixed UI layer ---
 
@@ -199,6 +248,9 @@ func (g *Game) drawShipView() {
 	buf.WriteString(2, 0, title, render.ColorWhite, render.ColorBlack)
 	nameLabel := fmt.Sprintf("[ %s ]", g.sim.Layout.Name)
 	buf.WriteString(20, 0, nameLabel, render.ColorLightCyan, render.ColorBlack)
+	if sight := g.sim.SightStatus(); sight != "" {
+		buf.WriteString(22+len(nameLabel), 0, sight, render.ColorLightRed, render.ColorBlack)
+	}
 	// Show current star system
 	curStar := g.sim.Sector.Systems[g.sim.Sector.CurrentSystem]
 	buf.WriteString(gridCols-len(curStar.Name)-4, 0, curStar.Name, render.ColorYellow, render.ColorBlack)
@@ -227,12 +279,13 @@ func (g *Game) drawShipView() {
 	legendItem(177, render.ColorLightMagenta, "Recycler")
 	legendItem(177, render.ColorBrown, "Generator")
 	legendItem('%', render.ColorBrown, "Engine")
+	legendItem(247, render.ColorLightCyan, "Life Support")
 
 	// HUD matter bars (live from sim)
 	r := &g.sim.Resources
 	g.Text(2, hudRow, "--- Matter ---", render.ColorLightCyan)
-	g.drawMatterBar(2, hudRow+1, "Water  ", &r.Water, render.ColorLightCyan, render.ColorBlue)
-	g.drawMatterBar(2, hudRow+2, "Organic", &r.Organic, render.ColorLightGreen, render.ColorGreen)
+	g.drawMatterBar(2, hudRow+1, "Water  ", r.Matter.Pool(game.MatterWater), render.ColorLightCyan, render.ColorBlue)
+	g.drawMatterBar(2, hudRow+2, "Organic", r.Matter.Pool(game.MatterOrganic), render.ColorLightGreen, render.ColorGreen)
 	g.drawEnergyBar(2, hudRow+3, "Energy ", r.Energy, r.MaxEnergy, render.ColorYellow)
 	g.drawSimpleBar(2, hudRow+4, "Hull   ", r.Hull, r.MaxHull, render.ColorLightGray)
 	// Credits and cargo
@@ -261,6 +314,8 @@ func (g *Game) drawShipView() {
 	row++
 	g.drawEquipStatus(panelX, row, "Transporter", g.sim.Grid.AnyEquipmentOn(world.EquipCargoTransporter))
 	row++
+	g.drawEquipStatus(panelX, row, "Life Support", g.sim.Grid.AnyEquipmentOn(world.EquipLifeSupport))
+	row++
 
 	// Player needs (right panel, below equipment)
 	n := &g.sim.Needs
@@ -272,7 +327,11 @@ func (g *Game) drawShipView() {
 	g.drawNeedBar(panelX, row, "Thirst ", n.Thirst)
 	row++
 	g.drawNeedBar(panelX, row, "Hygiene", n.Hygiene)
-	row += 2
+	row++
+	g.drawNeedBar(panelX, row, "Fatigue", n.Fatigue)
+	row++
+	g.drawHealth(panelX, row)
+	row += 3
 
 	// Standing on indicator
 	px, py := g.sim.PlayerPos()
@@ -280,6 +339,12 @@ func (g *Game) drawShipView() {
 	g.Text(panelX, row, "Standing on:", render.ColorDarkGray)
 	row++
 	g.Text(panelX, row, " "+tile.Describe(), render.ColorLightGray)
+	row += 2
+
+	// Air in the player's room
+	if r := g.sim.PlayerRoom(); r >= 0 {
+		g.drawRoomAir(panelX, row, g.sim.Rooms.Rooms[r].Name, g.sim.Air[r])
+	}
 
 	// Message log (live from sim) - tight text for readability
 	// Blinking hail alert when pending hail exists
@@ -298,7 +363,7 @@ func (g *Game) drawShipView() {
 	if g.sim.IsOnSurface() {
 		g.Text(2, gridRows-2, "LANDED - E at door: Exit  Pilot: Lift off", render.ColorLightGreen)
 	}
-	g.Text(2, gridRows-1, "WASD: Move  E: Interact  T: Toggle  Tab: Status  ESC: Quit", render.ColorDarkGray)
+	g.Text(2, gridRows-1, "WASD: Move  E: Interact  T: Toggle  R: Repair  Tab: Status  ESC: Quit", render.ColorDarkGray)
 }
 
 func (g *Game) drawSectorMapView() {
@@ -315,10 +380,23 @@ func (g *Game) drawSectorMapView() {
 	sel := sec.Systems[sec.CursorSystem]
 
 	// Title bar
-	buf.WriteString(2, 0, "--- Sector Map ---", render.ColorLightCyan, render.ColorBlack)
+	buf.WriteString(2, 0, fmt.Sprintf("--- Sector Map %s ---", sec.Coord), render.ColorLightCyan, render.ColorBlack)
 	curLabel := fmt.Sprintf("Location: %s", cur.Name)
 	buf.WriteString(gridCols-len(curLabel)-2, 0, curLabel, render.ColorYellow, render.ColorBlack)
 
+	// Monkey Lion search area, when the clue trail leads into this sector
+	trail, onTrail := g.sim.MonkeyLionTrail()
+	onTrail = onTrail && trail.Sector == sec.Coord
+	if onTrail && trail.Radius > 0 {
+		for y := trail.Y - trail.Radius; y <= trail.Y+trail.Radius; y++ {
+			for x := trail.X - trail.Radius; x <= trail.X+trail.Radius; x++ {
+				if x > 0 && x < panelX && y > 0 && y < commsRow && trail.Contains(x, y) {
+					buf.Set(x, y, 250, render.ColorGreen, render.ColorBlack) // · middle dot
+				}
+			}
+		}
+	}
+
 	// Draw connection line from current to cursor (if different)
 	if sec.CursorSystem != sec.CurrentSystem {
 		drawJumpLine(buf, cur.X, cur.Y, sel.X, sel.Y)
@@ -340,6 +418,16 @@ func (g *Game) drawSectorMapView() {
 			buf.Set(sys.X, sys.Y, glyph, clr, render.ColorBlack)
 		}
 
+		// The Monkey Lion's system, once the trail pins it down
+		if onTrail && i == trail.System {
+			buf.Set(sys.X, sys.Y-1, 'M', render.ColorLightGreen, render.ColorBlack)
+		}
+
+		// Gate arrow pointing at the sector edge it crosses
+		if gx, gy, arrow := gateArrow(sys); arrow != 0 {
+			buf.Set(gx, gy, arrow, render.ColorLightMagenta, render.ColorBlack)
+		}
+
 		// Cursor brackets around selected system
 		if i == sec.CursorSystem {
 			buf.Set(sys.X-1, sys.Y, '[', render.ColorYellow, render.ColorBlack)
@@ -364,24 +452,56 @@ func (g *Game) drawSectorMapView() {
 	} else {
 		buf.WriteString(infoX, 6, "Unexplored", render.ColorLightGreen, render.ColorBlack)
 	}
+	if sel.Gate != game.GateNone {
+		next := sec.Coord.Neighbor(sel.Gate)
+		buf.WriteString(infoX, 7, fmt.Sprintf("%s gate to %s", game.GateDirName(sel.Gate), next), render.ColorLightMagenta, render.ColorBlack)
+	}
 
-	if sec.CursorSystem != sec.CurrentSystem {
-		cost := sec.EnergyCostTo(sec.CursorSystem)
-		costClr := uint8(render.ColorLightGray)
-		if cost > g.sim.Resources.Energy {
-			costClr = render.ColorLightRed
+	r := &g.sim.Resources
+	if jump := g.sim.Jump; jump != nil {
+		// Spool-up progress toward the locked-in destination
+		dest := fmt.Sprintf("To: sector %s", sec.Coord.Neighbor(jump.Gate))
+		if jump.Gate == game.GateNone {
+			dest = "To: " + sec.Systems[jump.Target].Name
+		}
+		buf.WriteString(infoX, 8, "SPOOLING JUMP DRIVE", render.ColorLightRed, render.ColorBlack)
+		buf.WriteString(infoX, 9, dest, render.ColorWhite, render.ColorBlack)
+		g.drawSimpleBar(infoX, 10, "Spool  ", game.JumpSpoolTicks-jump.TicksLeft, game.JumpSpoolTicks, render.ColorLightMagenta)
+	} else if sec.CursorSystem != sec.CurrentSystem {
+		// Need/have for fuel and drive power (constant-draw gear is shed to free it)
+		fuel := sec.FuelCostTo(sec.CursorSystem)
+		fuelClr := uint8(render.ColorLightGray)
+		if fuel > r.JumpFuel {
+			fuelClr = render.ColorLightRed
+		}
+		buf.WriteString(infoX, 8, fmt.Sprintf("Fuel:  %d/%d", fuel, r.JumpFuel), fuelClr, render.ColorBlack)
+		if drive := g.sim.JumpDrive(); drive != nil {
+			powerClr := uint8(render.ColorYellow)
+			if drive.PowerCost > r.Energy {
+				powerClr = render.ColorLightRed
+			}
+			buf.WriteString(infoX, 9, fmt.Sprintf("Power: %d/%d", drive.PowerCost, r.Energy), powerClr, render.ColorBlack)
+		} else {
+			buf.WriteString(infoX, 9, "No jump drive!", render.ColorLightRed, render.ColorBlack)
 		}
-		buf.WriteString(infoX, 8, fmt.Sprintf("Jump cost: %d energy", cost), costClr, render.ColorBlack)
-		buf.WriteString(infoX, 9, fmt.Sprintf("Available: %d", g.sim.Resources.Energy), render.ColorYellow, render.ColorBlack)
+	} else if sel.Gate != game.GateNone {
+		// At a gate: the crossing costs a fixed amount of fuel
+		fuel := game.GateFuelCost()
+		fuelClr := uint8(render.ColorLightGray)
+		if fuel > r.JumpFuel {
+			fuelClr = render.ColorLightRed
+		}
+		buf.WriteString(infoX, 8, "You are here.", render.ColorYellow, render.ColorBlack)
+		buf.WriteString(infoX, 9, fmt.Sprintf("Gate fuel: %d/%d", fuel, r.JumpFuel), fuelClr, render.ColorBlack)
 	} else {
 		buf.WriteString(infoX, 8, "You are here.", render.ColorYellow, render.ColorBlack)
 	}
 
 	// Ship status summary
-	r := &g.sim.Resources
 	buf.WriteString(infoX, 11, "--- Ship ---", render.ColorLightCyan, render.ColorBlack)
 	g.drawEnergyBar(infoX, 12, "Energy ", r.Energy, r.MaxEnergy, render.ColorYellow)
 	g.drawSimpleBar(infoX, 13, "Hull   ", r.Hull, r.MaxHull, render.ColorLightGray)
+	g.drawSimpleBar(infoX, 14, "Fuel   ", r.JumpFuel, r.MaxJumpFuel, render.ColorLightMagenta)
 
 	// Explored counter
 	visited := 0
@@ -390,7 +510,22 @@ func (g *Game) drawSectorMapView() {
 			visited++
 		}
 	}
-	buf.WriteString(infoX, 15, fmt.Sprintf("Explored: %d/%d", visited, len(sec.Systems)), render.ColorDarkGray, render.ColorBlack)
+	buf.WriteString(infoX, 16, fmt.Sprintf("Explored: %d/%d", visited, len(sec.Systems)), render.ColorDarkGray, render.ColorBlack)
+
+	// Monkey Lion trail
+	if trail, ok := g.sim.MonkeyLionTrail(); ok && !g.sim.Discovery.MonkeyLionBoarded {
+		buf.WriteString(infoX, 18, "--- Monkey Lion ---", render.ColorLightCyan, render.ColorBlack)
+		switch {
+		case trail.Sector != sec.Coord:
+			buf.WriteString(infoX, 19, fmt.Sprintf("Trail: sector %s", trail.Sector), render.ColorLightGreen, render.ColorBlack)
+		case trail.System >= 0:
+			buf.WriteString(infoX, 19, "At "+trail.Name, render.ColorLightGreen, render.ColorBlack)
+		default:
+			buf.WriteString(infoX, 19, "In the marked area", render.ColorLightGreen, render.ColorBlack)
+		}
+		buf.WriteString(infoX, 20, fmt.Sprintf("Clues: %d/%d", min(g.sim.Discovery.MLCluesFound, game.MLCluesToReveal), game.MLCluesToReveal),
+			render.ColorDarkGray, render.ColorBlack)
+	}
 
 	// Comms log (tight text)
 	g.Text(2, commsRow, "--- Comms ---", render.ColorLightCyan)
@@ -401,7 +536,110 @@ func (g *Game) drawSectorMapView() {
 	}
 
 	// Instructions
-	g.Text(2, gridRows-1, "WASD: Select star  E: Jump  ESC: Back", render.ColorDarkGray)
+	switch {
+	case g.sim.IsJumping():
+		g.Text(2, gridRows-1, "X: Abort jump  ESC: Back", render.ColorDarkGray)
+	case cur.Gate != game.GateNone:
+		g.Text(2, gridRows-1, "WASD: Select star  E: Jump  G: Gate jump  M: Galaxy  ESC: Back", render.ColorDarkGray)
+	default:
+		g.Text(2, gridRows-1, "WASD: Select star  E: Jump  M: Galaxy  ESC: Back", render.ColorDarkGray)
+	}
+}
+
+// gateArrow returns where to draw a gate system's arrow and its glyph,
+// or 0 if the system is not a gate.
+func gateArrow(sys game.StarSystem) (x, y int, glyph byte) {
+	switch sys.Gate {
+	case game.GateNorth:
+		return sys.X, sys.Y - 1, '^'
+	case game.GateEast:
+		return sys.X + 2, sys.Y, '>'
+	case game.GateSouth:
+		return sys.X, sys.Y + 1, 'v'
+	case game.GateWest:
+		return sys.X - 2, sys.Y, '<'
+	default:
+		return 0, 0, 0
+	}
+}
+
+// --- Galaxy view ---
+
+// Galaxy grid cell size on screen.
+const (
+	galaxyCellW = 8
+	galaxyCellH = 4
+)
+
+func (g *Game) drawGalaxyView() {
+	buf := g.buffer
+	buf.Clear()
+
+	buf.FillRect(panelX, 0, gridCols-panelX, gridRows, render.ColorHUDBG) // right panel
+	buf.FillRect(0, 0, gridCols, 1, render.ColorHUDBG)                    // title bar
+
+	gal := g.sim.Galaxy
+	buf.WriteString(2, 0, "--- Galaxy ---", render.ColorLightCyan, render.ColorBlack)
+	curLabel := fmt.Sprintf("Sector: %s", gal.Current)
+	buf.WriteString(gridCols-len(curLabel)-2, 0, curLabel, render.ColorYellow, render.ColorBlack)
+
+	// One cell per sector; charted sectors show how much of them is explored
+	for y := -game.GalaxyRadius; y <= game.GalaxyRadius; y++ {
+		for x := -game.GalaxyRadius; x <= game.GalaxyRadius; x++ {
+			c := game.SectorCoord{X: x, Y: y}
+			cx := 2 + (x+game.GalaxyRadius)*galaxyCellW
+			cy := 3 + (y+game.GalaxyRadius)*galaxyCellH
+			sec := gal.Sectors[c]
+			switch {
+			case c == gal.Current:
+				buf.WriteString(cx, cy, fmt.Sprintf("[%s]", c), render.ColorYellow, render.ColorBlack)
+			case sec != nil:
+				buf.WriteString(cx, cy, fmt.Sprintf(" %s", c), render.ColorLightGray, render.ColorBlack)
+			default:
+				buf.WriteString(cx+2, cy, "?", render.ColorDarkGray, render.ColorBlack)
+			}
+			if sec != nil {
+				visited := 0
+				for _, sys := range sec.Systems {
+					if sys.Visited {
+						visited++
+					}
+				}
+				buf.WriteString(cx+1, cy+1, fmt.Sprintf("%d/%d", visited, len(sec.Systems)), render.ColorDarkGray, render.ColorBlack)
+			}
+		}
+	}
+
+	// Current sector details
+	sec := g.sim.Sector
+	infoX := panelX
+	buf.WriteString(infoX, 3, "--- This Sector ---", render.ColorLightCyan, render.ColorBlack)
+	buf.WriteString(infoX, 4, sec.FactionName(0), render.ColorLightBlue, render.ColorBlack)
+	row := 6
+	buf.WriteString(infoX, row, "Gates:", render.ColorLightGray, render.ColorBlack)
+	row++
+	for _, sys := range sec.Systems {
+		if sys.Gate == game.GateNone {
+			continue
+		}
+		line := fmt.Sprintf("%-5s %s", game.GateDirName(sys.Gate), sys.Name)
+		buf.WriteString(infoX, row, line, render.ColorLightMagenta, render.ColorBlack)
+		row++
+	}
+	buf.WriteString(infoX, row+1, fmt.Sprintf("Charted: %d", len(gal.Sectors)), render.ColorDarkGray, render.ColorBlack)
+	if trail, ok := g.sim.MonkeyLionTrail(); ok && !g.sim.Discovery.MonkeyLionBoarded {
+		buf.WriteString(infoX, row+3, fmt.Sprintf("Monkey Lion: %s", trail.Sector), render.ColorLightGreen, render.ColorBlack)
+	}
+
+	g.Text(2, gridRows-1, "M / ESC: Back to sector map", render.ColorDarkGray)
+}
+
+func (g *Game) updateGalaxy() error {
+	if keyJustPressed(KeyEscape) || keyJustPressed(KeyM) {
+		g.viewMode = ViewSectorMap
+	}
+	g.drawScreen()
+	return nil
 }
 
 func (g *Game) drawSystemMapView() {
@@ -494,14 +732,29 @@ func (g *Game) drawSystemMapView() {
 		}
 	}
 
-	// Draw space objects as floating sprites
+	// Orbit rings of planets and moons, dotted every other tile
+	for i := range sm.Objects {
+		obj := &sm.Objects[i]
+		if obj.Kind != game.ObjPlanet || obj.Orbit.Period <= 0 {
+			continue
+		}
+		px, py := sm.ObjectPos(obj.Orbit.Parent, g.sim.Ticks)
+		step := 2 / obj.Orbit.Radius
+		for a := 0.0; a < 2*math.Pi; a += step {
+			ox, oy := obj.Orbit.Offset(a)
+			addSprite(250, render.ColorBlue, px+ox, py+oy)
+		}
+	}
+
+	// Draw space objects as floating sprites; orbiting ones glide between tiles
 	for i := range sm.Objects {
 		obj := &sm.Objects[i]
 		glyph, fg := spaceObjectAppearance(obj)
 		if obj.Kind == game.ObjStar {
 			fg = starColor(curStar.Type)
 		}
-		addSprite(glyph, fg, float64(obj.X), float64(obj.Y))
+		x, y := sm.ObjectPos(i, g.sim.Ticks)
+		addSprite(glyph, fg, x, y)
 	}
 
 	// Shuttle — drawn at its exact float position (viewport center unless camera clamped at edge)
@@ -559,7 +812,7 @@ func (g *Game) drawSystemMapView() {
 			row++
 			objIdx := g.findObjectIndex(sm, nearObj)
 			if objIdx >= 0 {
-				key := game.ScanKey(g.sim.Sector.CurrentSystem, objIdx)
+				key := game.ScanKey(g.sim.Sector.SystemID(g.sim.Sector.CurrentSystem), objIdx)
 				if scan, ok := g.sim.Discovery.PlanetsScanned[key]; ok {
 					buf.WriteString(infoX, row, "SCANNED", render.ColorLightGreen, render.ColorBlack)
 					row++
@@ -572,6 +825,12 @@ func (g *Game) drawSystemMapView() {
 			buf.WriteString(infoX, row, "Station - press E to dock", render.ColorCyan, render.ColorBlack)
 		case game.ObjDerelict:
 			buf.WriteString(infoX, row, "Derelict - salvageable?", render.ColorDarkGray, render.ColorBlack)
+		case game.ObjMonkeyLion:
+			if g.sim.Discovery.MonkeyLionBoarded {
+				buf.WriteString(infoX, row, "Yours now", render.ColorLightGreen, render.ColorBlack)
+			} else {
+				buf.WriteString(infoX, row, "Press E to board", render.ColorLightGreen, render.ColorBlack)
+			}
 		case game.ObjShip:
 			kind := game.ShipAIKindName(nearObj.AIKind)
 			clr := uint8(render.ColorLightGray)
@@ -586,12 +845,21 @@ func (g *Game) drawSystemMapView() {
 		dy := nearObj.Y - sm.Shuttle.TileY()
 		d2 := dx*dx + dy*dy
 		distLabel := "Approaching"
+		distClr := uint8(render.ColorDarkGray)
 		if d2 <= 4 {
 			distLabel = "Very close"
 		} else if d2 <= 9 {
 			distLabel = "Close"
 		}
-		buf.WriteString(infoX, row, distLabel, render.ColorDarkGray, render.ColorBlack)
+		// Close enough to orbit a planet: say whether we're moving with it
+		if idx := g.findObjectIndex(sm, nearObj); nearObj.Kind == game.ObjPlanet && idx >= 0 && d2 <= game.OrbitRange*game.OrbitRange {
+			if g.sim.OrbitMatch(idx) == "" {
+				distLabel, distClr = "Speed matched - E", render.ColorLightGreen
+			} else {
+				distLabel, distClr = "Too fast to orbit", render.ColorLightRed
+			}
+		}
+		buf.WriteString(infoX, row, distLabel, distClr, render.ColorBlack)
 		row++
 	}
 	row++
@@ -604,7 +872,12 @@ func (g *Game) drawSystemMapView() {
 	row++
 	g.drawSimpleBar(infoX, row, "Hull   ", r.Hull, r.MaxHull, render.ColorLightGray)
 	row += 2
-	buf.WriteString(infoX, row, fmt.Sprintf("Pos: %d, %d", sm.Shuttle.TileX(), sm.Shuttle.TileY()), render.ColorDarkGray, render.ColorBlack)
+	// A pirate on our tail: how far off it is, against how far we need to get
+	if p, d := g.sim.Pursuer(); p != nil {
+		buf.WriteString(infoX, row, fmt.Sprintf("CHASED: %d/%d tiles", int(d), g.sim.ChaseRange()), render.ColorLightRed, render.ColorBlack)
+	} else {
+		buf.WriteString(infoX, row, fmt.Sprintf("Pos: %d, %d", sm.Shuttle.TileX(), sm.Shuttle.TileY()), render.ColorDarkGray, render.ColorBlack)
+	}
 	row++
 	spdPct := sm.Shuttle.SpeedPct()
 	spdClr := uint8(render.ColorDarkGray)
@@ -655,6 +928,23 @@ func (g *Game) drawRadar(buf *render.CellBuffer, sm *game.SystemMap, star game.S
 		}
 	}
 
+	// Planet orbit rings around the star
+	for i := range sm.Objects {
+		obj := &sm.Objects[i]
+		if obj.Kind != game.ObjPlanet || obj.Orbit.Period <= 0 || obj.Orbit.Parent != 0 {
+			continue
+		}
+		star := &sm.Objects[0]
+		for a := 0.0; a < 2*math.Pi; a += radarScale / 2 / obj.Orbit.Radius {
+			ox, oy := obj.Orbit.Offset(a)
+			rx := centerRX + int(math.Floor((float64(star.X)+ox-sm.Shuttle.X)/radarScale+0.5))
+			ry := centerRY + int(math.Floor((float64(star.Y)+oy-sm.Shuttle.Y)/radarScale+0.5))
+			if rx >= 0 && rx < radarW && ry >= 0 && ry < radarH {
+				buf.Set(radarX+1+rx, bodyY+ry, 250, render.ColorBlue, render.ColorBlack)
+			}
+		}
+	}
+
 	// Map objects relative to shuttle position
 	for i := range sm.Objects {
 		obj := &sm.Objects[i]
@@ -686,6 +976,9 @@ func (g *Game) drawRadar(buf *render.CellBuffer, sm *game.SystemMap, star game.S
 		case game.ObjShip:
 			glyph = '.'
 			fg = shipColor(obj.AIKind)
+		case game.ObjMonkeyLion:
+			glyph = 'M'
+			fg = render.ColorLightGreen
 		default:
 			continue
 		}
@@ -754,6 +1047,8 @@ func spaceObjectAppearance(obj *game.SpaceObject) (glyph byte, fg uint8) {
 		return '.', render.ColorLightGray
 	case game.ObjShip:
 		return shipGlyph(obj.AIKind), shipColor(obj.AIKind)
+	case game.ObjMonkeyLion:
+		return 'M', render.ColorLightGreen
 	default:
 		return '?', render.ColorWhite
 	}
@@ -834,7 +1129,7 @@ func msgColor(p game.MsgPriority) uint8 {
 }
 
 // drawMatterBar shows a bar split into clean (solid) and dirty (shaded) segments.
-func (g *Game) drawMatterBar(x, y int, label string, pool *game.MatterPool, cleanClr, dirtyClr uint8) {
+func (g *Game) drawMatterBar(x, y int, label string, pool game.MatterPool, cleanClr, dirtyClr uint8) {
 	buf := g.buffer
 	barW := 20
 	cap := pool.Capacity
@@ -954,7 +1249,59 @@ func (g *Game) drawEquipStatus(x, y int, name string, on bool) {
 	}
 }
 
-// drawNeedBar shows a player need (hunger/thirst/hygiene) as a 10-char bar with label.
+// drawRoomAir shows the room the player is in and its air, red when it hurts.
+func (g *Game) drawRoomAir(x, y int, name string, air game.RoomAir) {
+	g.Text(x, y, name+":", render.ColorDarkGray)
+	clr := uint8(render.ColorLightGray)
+	if g.sim.AirHazard() != "" {
+		clr = render.ColorLightRed
+	}
+	g.Text(x, y+1, fmt.Sprintf(" O2 %.0f%% CO2 %.1f%%", air.O2, air.CO2), clr)
+	g.Text(x, y+2, fmt.Sprintf(" %.0fC", air.Temp), clr)
+	if len(air.Holes) > 0 && (g.sim.Ticks/30)%2 == 0 {
+		g.Text(x+6, y+2, "HULL BREACH", render.ColorLightRed)
+	}
+}
+
+// drawHealth shows the player's health and, on the line below, any injuries.
+func (g *Game) drawHealth(x, y int) {
+	n := &g.sim.Needs
+	clr := uint8(render.ColorLightGreen)
+	switch {
+	case n.Health <= 20:
+		clr = render.ColorLightRed
+	case n.Health < n.MaxHealth/2:
+		clr = render.ColorYellow
+	}
+	g.Text(x, y, fmt.Sprintf("Health %d/%d", n.Health, n.MaxHealth), clr)
+
+	var parts []string
+	for k := range game.InjuryKindCount {
+		if sev := g.sim.Injuries[k]; sev > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", game.InjuryName(k), sev))
+		}
+	}
+	if len(parts) == 0 {
+		return
+	}
+	clr = render.ColorYellow
+	if _, serious := g.sim.Injuries.Serious(); serious {
+		clr = render.ColorLightRed
+	}
+	g.Text(x, y+1, " "+strings.Join(parts, ", "), clr)
+}
+
+// drawSleeping shows a banner over the map while the player sleeps.
+func (g *Game) drawSleeping() {
+	msg := " Sleeping... (any key to wake) "
+	if (g.sim.Ticks/(30*game.SleepSpeed))%2 == 1 {
+		msg = " Sleeping.   (any key to wake) "
+	}
+	x := (panelX - len(msg)) / 2
+	g.buffer.WriteString(x, 2, msg, render.ColorLightBlue, render.ColorHUDBG)
+}
+
+// drawNeedBar shows a player need (hunger/thirst/hygiene/fatigue) as a 10-char bar with label.
 func (g *Game) drawNeedBar(x, y int, label string, val int) {
 	buf := g.buffer
 	barW := 10
@@ -988,8 +1335,77 @@ func (g *Game) drawNeedBar(x, y int, label string, val int) {
 // --- Update dispatch ---
 
 func (g *Game) Update() error {
-	// Always tick simulation — resources drain even while looking at the map
-	g.sim.Tick()
+	// No time passes until the run has a goal
+	if g.viewMode == ViewGoalSelect {
+		return g.updateGoalSelect()
+	}
+
+	// Tick as the policy says: every frame in real time, or the cost of the
+	// last actions when turn-based. Asleep, time runs at SleepSpeed ticks a
+	// frame either way until the player wakes.
+	n := g.policy.Ticks()
+	for i := 0; i < n || i < game.SleepSpeed && g.sim.Asleep(); i++ {
+		g.tick()
+	}
+
+	// Death or victory ends the run: score it, and from then on there's
+	// only the run-over screen
+	if g.sim.IsGameOver() {
+		if g.viewMode != ViewRunOver {
+			g.endRun()
+		}
+		return g.updateRunOver()
+	}
+
+	// Jump drive fired → straight into the arrival episode, or off the nav map
+	if g.sim.JumpCompleted {
+		g.sim.JumpCompleted = false
+		if g.sim.ActiveEpisode != nil {
+			g.viewMode = ViewEpisode
+		} else if g.viewMode == ViewSectorMap || g.viewMode == ViewGalaxy {
+			g.viewMode = ViewSystemMap
+		}
+	}
+
+	// A pirate ran the shuttle down → straight into its encounter
+	if g.sim.Intercepted {
+		g.sim.Intercepted = false
+		if g.viewMode != ViewEncounter {
+			g.prevViewMode = g.viewMode
+			g.viewMode = ViewEncounter
+		}
+	}
+
+	// Quicksave / quickload work from any view
+	if keyJustPressed(KeyF5) {
+		g.quickSave()
+	}
+	if keyJustPressed(KeyF9) {
+		g.quickLoad()
+	}
+
+	// F2 switches between real-time and turn-based play; turn-based, Space
+	// waits a turn, or lets time run while held
+	if keyJustPressed(KeyF2) {
+		g.toggleTurnBased()
+	}
+	if keyJustPressed(KeySpace) {
+		g.policy.Wait(game.WaitTicks)
+	} else if keyPressed(KeySpace) {
+		g.policy.Wait(1)
+	}
+
+	// Asleep: no controls but waking up
+	if g.sim.Asleep() {
+		if anyKeyJustPressed() {
+			g.act(game.Action{Kind: game.ActWake})
+		}
+		if g.sim.Asleep() {
+			g.drawScreen()
+			g.drawSleeping()
+			return nil
+		}
+	}
 
 	switch g.viewMode {
 	case ViewSectorMap:
@@ -1008,88 +1424,215 @@ func (g *Game) Update() error {
 		return g.updateEpisode()
 	case ViewSurface:
 		return g.updateSurface()
+	case ViewGalaxy:
+		return g.updateGalaxy()
 	default:
 		return g.updateShip()
 	}
 }
 
+// toggleTurnBased switches the tick policy between real time and turns.
+func (g *Game) toggleTurnBased() {
+	if _, ok := g.policy.(*game.TurnBased); ok {
+		g.policy = game.RealTime{}
+		g.sim.Log.Add("Real time. The clock is running.", game.MsgInfo)
+	} else {
+		g.policy = &game.TurnBased{}
+		g.sim.Log.Add("Turn-based. Time passes only when you act; Space waits.", game.MsgInfo)
+	}
+}
+
+// tick advances the simulation one step, and the replay one frame with it.
+func (g *Game) tick() {
+	g.sim.Tick()
+	if g.rec != nil {
+		g.rec.Frame()
+	}
+}
+
+// startRecording writes a replay of this run to path.
+// Must be called before the first Update so the replay starts at frame zero.
+func (g *Game) startRecording(path string, seed int64) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	rec, err := game.NewRecorder(f, seed, g.sim.Layout)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	g.rec = rec
+	g.recFile = f
+	return nil
+}
+
+// stopRecording finishes the replay with the final state hash.
+func (g *Game) stopRecording() {
+	if g.rec == nil {
+		return
+	}
+	if err := g.rec.Close(g.sim); err != nil {
+		log.Printf("replay: %v", err)
+	}
+	g.recFile.Close()
+	g.rec = nil
+	g.recFile = nil
+}
+
+// endRun scores the run, enters it into the hall of fame at scoresPath and
+// deletes the quicksave: death is permanent, and a won run is over too.
+func (g *Game) endRun() {
+	sum := game.SummarizeRun(g.sim)
+	sum.Date = time.Now().Format("2006-01-02")
+	g.runSummary = &sum
+	g.viewMode = ViewRunOver
+	g.stopRecording()
+	os.Remove(savePath)
+
+	g.hallOfFame = &game.HallOfFame{}
+	if f, err := os.Open(scoresPath); err == nil {
+		h, err := game.ReadHallOfFame(f)
+		f.Close()
+		if err != nil {
+			// Don't overwrite a file we can't read
+			g.scoreErr = err.Error()
+			return
+		}
+		g.hallOfFame = h
+	}
+	g.runRank = g.hallOfFame.Add(sum)
+	f, err := os.Create(scoresPath)
+	if err != nil {
+		g.scoreErr = err.Error()
+		return
+	}
+	defer f.Close()
+	if err := g.hallOfFame.Write(f); err != nil {
+		g.scoreErr = err.Error()
+	}
+}
+
+// newRun replaces the finished run with a fresh one on a new seed, keeping
+// the tick policy. It starts on the goal select, at the last run's goal.
+func (g *Game) newRun() {
+	ng := NewGame(time.Now().UnixNano())
+	ng.policy = g.policy
+	ng.goalCursor = g.sim.Goal
+	*g = *ng
+}
+
+// chooseGoal sets the run's goal and starts the prologue.
+func (g *Game) chooseGoal(goal game.Goal) {
+	g.act(game.Action{Kind: game.ActChooseGoal, Index: int(goal)})
+	g.viewMode = ViewSurface
+	g.drawScreen()
+}
+
+// quickSave writes the current run to savePath.
+func (g *Game) quickSave() {
+	f, err := os.Create(savePath)
+	if err != nil {
+		g.sim.Log.Add(fmt.Sprintf("Save failed: %v", err), game.MsgCritical)
+		return
+	}
+	defer f.Close()
+	if err := game.SaveSim(f, g.sim); err != nil {
+		g.sim.Log.Add(fmt.Sprintf("Save failed: %v", err), game.MsgCritical)
+		return
+	}
+	g.sim.Log.Add("Game saved.", game.MsgInfo)
+}
+
+// quickLoad replaces the current run with the one stored at savePath.
+// The player resumes inside the shuttle (or on the surface during the prologue).
+func (g *Game) quickLoad() {
+	f, err := os.Open(savePath)
+	if err != nil {
+		g.sim.Log.Add("No saved game found.", game.MsgWarning)
+		return
+	}
+	defer f.Close()
+	sim, err := game.LoadSim(f, g.sim.Layout)
+	if err != nil {
+		g.sim.Log.Add(fmt.Sprintf("Load failed: %v", err), game.MsgCritical)
+		return
+	}
+	if g.rec != nil {
+		// A replay can only reproduce a run from its seed
+		g.stopRecording()
+		sim.Log.Add("Replay recording stopped.", game.MsgWarning)
+	}
+	g.sim = sim
+	g.stationData = nil
+	g.stationMenu = stMenuMain
+	g.viewMode = ViewShip
+	if sim.Outside {
+		g.viewMode = ViewSurface
+	}
+	g.sim.Log.Add("Game loaded.", game.MsgInfo)
+}
+
 func (g *Game) updateShip() error {
-	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+	if keyJustPressed(KeyEscape) {
 		if g.sim.IsOrbiting() {
 			// ESC while orbiting → leave orbit, return to system map
-			g.sim.LeaveOrbit()
+			g.act(game.Action{Kind: game.ActLeaveOrbit})
 			g.viewMode = ViewSystemMap
 			return nil
 		}
-		return ebiten.Termination
+		return errQuit
 	}
 
 	// Player movement
 	dx, dy := 0, 0
-	if inpututil.IsKeyJustPressed(ebiten.KeyW) || inpututil.IsKeyJustPressed(ebiten.KeyUp) {
+	if keyJustPressed(KeyW) || keyJustPressed(KeyUp) {
 		dy = -1
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyS) || inpututil.IsKeyJustPressed(ebiten.KeyDown) {
+	if keyJustPressed(KeyS) || keyJustPressed(KeyDown) {
 		dy = 1
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyA) || inpututil.IsKeyJustPressed(ebiten.KeyLeft) {
+	if keyJustPressed(KeyA) || keyJustPressed(KeyLeft) {
 		dx = -1
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyD) || inpututil.IsKeyJustPressed(ebiten.KeyRight) {
+	if keyJustPressed(KeyD) || keyJustPressed(KeyRight) {
 		dx = 1
 	}
 	if dx != 0 || dy != 0 {
-		g.sim.TryMovePlayer(dx, dy)
+		g.act(game.Action{Kind: game.ActMove, DX: dx, DY: dy})
 	}
 
 	// Interact / Toggle
-	if inpututil.IsKeyJustPressed(ebiten.KeyE) {
+	if keyJustPressed(KeyE) {
 		// Check if on airlock - if landed, exit to surface
 		px, py := g.sim.PlayerPos()
 		eq := g.sim.Grid.GetEquipment(px, py)
 		if eq != nil && eq.Kind == world.EquipAirlock && g.sim.IsOnSurface() {
-			if g.sim.ExitShuttle() {
+			if g.act(game.Action{Kind: game.ActExitShuttle}) {
 				g.viewMode = ViewSurface
 			}
 		} else {
-			g.sim.Interact()
+			g.act(game.Action{Kind: game.ActInteract})
 		}
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyT) {
-		g.sim.ToggleEquipment()
+	if keyJustPressed(KeyT) {
+		g.act(game.Action{Kind: game.ActToggle})
+	}
+	if keyJustPressed(KeyR) {
+		g.act(game.Action{Kind: game.ActRepairEquipment})
 	}
 
 	// Nav console → sector map (pick a star to jump to)
 	if g.sim.NavActivated {
 		g.sim.NavActivated = false
-		g.sim.Sector.CursorSystem = g.sim.Sector.CurrentSystem
+		g.act(game.Action{Kind: game.ActSelectSystem, Index: g.sim.Sector.CurrentSystem})
 		g.viewMode = ViewSectorMap
 	}
 
-	// Pilot console handling when landed on surface
+	// Pilot console → system map (lifting off or breaking orbit first if needed)
 	if g.sim.PilotActivated {
 		g.sim.PilotActivated = false
-		if g.sim.IsOnSurface() {
-			// Shuttle is landed - use pilot to lift off
-			if g.sim.InPrologue() {
-				if g.sim.PrologueSurface.CheckPrologueComplete() {
-					g.sim.CompletePrologue()
-					g.sim.Sector.EnsureSystemMap(g.sim.Sector.CurrentSystem)
-					g.viewMode = ViewSystemMap
-				} else {
-					g.sim.Log.Add("Shuttle not ready. "+g.sim.PrologueSurface.ObjectiveStatus(), game.MsgWarning)
-				}
-			} else {
-				g.sim.LiftOff()
-				g.sim.Sector.EnsureSystemMap(g.sim.Sector.CurrentSystem)
-				g.viewMode = ViewSystemMap
-			}
-		} else if g.sim.IsOrbiting() {
-			g.sim.LeaveOrbit()
-			g.sim.Sector.EnsureSystemMap(g.sim.Sector.CurrentSystem)
-			g.viewMode = ViewSystemMap
-		} else {
-			g.sim.Sector.EnsureSystemMap(g.sim.Sector.CurrentSystem)
+		if g.act(game.Action{Kind: game.ActLaunch}) {
 			g.viewMode = ViewSystemMap
 		}
 	}
@@ -1104,7 +1647,7 @@ func (g *Game) updateShip() error {
 	if g.sim.ScanActivated {
 		g.sim.ScanActivated = false
 		if g.sim.IsOrbiting() {
-			g.sim.ScanPlanet(g.sim.OrbitPlanetIdx)
+			g.act(game.Action{Kind: game.ActScan, Index: g.sim.OrbitPlanetIdx})
 		} else {
 			g.prevViewMode = ViewShip
 			g.viewMode = ViewCharSheet
@@ -1114,15 +1657,14 @@ func (g *Game) updateShip() error {
 	// Comms station (viewscreen) → encounter
 	if g.sim.CommsActivated {
 		g.sim.CommsActivated = false
-		g.sim.StartEncounter()
-		if g.sim.ActiveEncounter != nil {
+		if g.act(game.Action{Kind: game.ActStartEncounter}) {
 			g.prevViewMode = ViewShip
 			g.viewMode = ViewEncounter
 		}
 	}
 
 	// Tab → character sheet
-	if inpututil.IsKeyJustPressed(ebiten.KeyTab) {
+	if keyJustPressed(KeyTab) {
 		g.prevViewMode = ViewShip
 		g.viewMode = ViewCharSheet
 	}
@@ -1131,13 +1673,13 @@ func (g *Game) updateShip() error {
 	g.drawScreen()
 
 	// Mouse hover: convert screen pixel to grid cell to ship tile
-	mx, my := ebiten.CursorPosition()
+	mx, my := cursorPosition()
 	cellX := mx / cellWidth
 	cellY := my / cellHeight
 	g.updateHoverInfo(cellX, cellY)
 
 	// Update FPS counter
-	fps := fmt.Sprintf("FPS: %.0f  TPS: %.0f", ebiten.ActualFPS(), ebiten.ActualTPS())
+	fps := fmt.Sprintf("FPS: %.0f  TPS: %.0f", actualFPS(), actualTPS())
 	g.buffer.WriteString(gridCols-20, gridRows-1, fps, render.ColorDarkGray, render.ColorBlack)
 
 	return nil
@@ -1145,7 +1687,7 @@ func (g *Game) updateShip() error {
 
 func (g *Game) updateSectorMap() error {
 	// ESC → back to system map (not ship interior)
-	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+	if keyJustPressed(KeyEscape) {
 		g.viewMode = ViewSystemMap
 		g.drawScreen()
 		return nil
@@ -1153,44 +1695,55 @@ func (g *Game) updateSectorMap() error {
 
 	// Cursor movement — snap to nearest star in direction
 	dx, dy := 0, 0
-	if inpututil.IsKeyJustPressed(ebiten.KeyW) || inpututil.IsKeyJustPressed(ebiten.KeyUp) {
+	if keyJustPressed(KeyW) || keyJustPressed(KeyUp) {
 		dy = -1
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyS) || inpututil.IsKeyJustPressed(ebiten.KeyDown) {
+	if keyJustPressed(KeyS) || keyJustPressed(KeyDown) {
 		dy = 1
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyA) || inpututil.IsKeyJustPressed(ebiten.KeyLeft) {
+	if keyJustPressed(KeyA) || keyJustPressed(KeyLeft) {
 		dx = -1
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyD) || inpututil.IsKeyJustPressed(ebiten.KeyRight) {
+	if keyJustPressed(KeyD) || keyJustPressed(KeyRight) {
 		dx = 1
 	}
 	if dx != 0 || dy != 0 {
 		next := g.sim.Sector.NearestInDirection(dx, dy)
 		if next >= 0 {
-			g.sim.Sector.CursorSystem = next
+			g.act(game.Action{Kind: game.ActSelectSystem, Index: next})
 		}
 	}
 
-	// Jump to selected system
-	if inpututil.IsKeyJustPressed(ebiten.KeyE) {
+	// Spool the jump drive for the selected system (arrival is handled in Update)
+	if keyJustPressed(KeyE) {
 		target := g.sim.Sector.CursorSystem
 		if target != g.sim.Sector.CurrentSystem {
-			if g.sim.NavigateTo(target) {
-				if g.sim.ActiveEpisode != nil {
-					g.viewMode = ViewEpisode
-				} else {
-					g.viewMode = ViewSystemMap
-				}
-			}
+			g.act(game.Action{Kind: game.ActNavigate, Index: target})
 		}
 	}
 
+	// G → spool for the sector gate in the current system
+	if keyJustPressed(KeyG) && !g.sim.IsJumping() {
+		g.act(game.Action{Kind: game.ActGateJump})
+	}
+
+	// X → abort a spooling jump
+	if keyJustPressed(KeyX) && g.sim.IsJumping() {
+		g.act(game.Action{Kind: game.ActAbortJump})
+	}
+
+	// M → galaxy overview
+	if keyJustPressed(KeyM) {
+		g.viewMode = ViewGalaxy
+		g.drawScreen()
+		return nil
+	}
+
 	// Redraw
 	g.drawScreen()
 
 	// Update FPS counter
-	fps := fmt.Sprintf("FPS: %.0f  TPS: %.0f", ebiten.ActualFPS(), ebiten.ActualTPS())
+	fps := fmt.Sprintf("FPS: %.0f  TPS: %.0f", actualFPS(), actualTPS())
 	g.buffer.WriteString(gridCols-20, gridRows-1, fps, render.ColorDarkGray, render.ColorBlack)
 
 	return nil
@@ -1198,20 +1751,20 @@ func (g *Game) updateSectorMap() error {
 
 func (g *Game) updateSystemMap() error {
 	// ESC → back to ship interior
-	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+	if keyJustPressed(KeyEscape) {
 		g.viewMode = ViewShip
 		return nil
 	}
 
 	// N → open sector nav map for interstellar jumps
-	if inpututil.IsKeyJustPressed(ebiten.KeyN) {
-		g.sim.Sector.CursorSystem = g.sim.Sector.CurrentSystem
+	if keyJustPressed(KeyN) {
+		g.act(game.Action{Kind: game.ActSelectSystem, Index: g.sim.Sector.CurrentSystem})
 		g.viewMode = ViewSectorMap
 		return nil
 	}
 
 	// Tab → character sheet
-	if inpututil.IsKeyJustPressed(ebiten.KeyTab) {
+	if keyJustPressed(KeyTab) {
 		g.prevViewMode = ViewSystemMap
 		g.viewMode = ViewCharSheet
 		return nil
@@ -1220,46 +1773,46 @@ func (g *Game) updateSystemMap() error {
 	// Thrust-based WASD flight (physics handles acceleration, drag, speed cap)
 	{
 		dx, dy := 0, 0
-		if ebiten.IsKeyPressed(ebiten.KeyW) || ebiten.IsKeyPressed(ebiten.KeyUp) {
+		if keyPressed(KeyW) || keyPressed(KeyUp) {
 			dy = -1
 		}
-		if ebiten.IsKeyPressed(ebiten.KeyS) || ebiten.IsKeyPressed(ebiten.KeyDown) {
+		if keyPressed(KeyS) || keyPressed(KeyDown) {
 			dy = 1
 		}
-		if ebiten.IsKeyPressed(ebiten.KeyA) || ebiten.IsKeyPressed(ebiten.KeyLeft) {
+		if keyPressed(KeyA) || keyPressed(KeyLeft) {
 			dx = -1
 		}
-		if ebiten.IsKeyPressed(ebiten.KeyD) || ebiten.IsKeyPressed(ebiten.KeyRight) {
+		if keyPressed(KeyD) || keyPressed(KeyRight) {
 			dx = 1
 		}
 		if dx != 0 || dy != 0 {
-			sm := g.sim.Sector.CurrentSystemMap()
-			sm.Shuttle.ApplyThrust(dx, dy)
-			g.sim.Skills.AddXP(game.SkillPiloting, 0.1)
+			g.act(game.Action{Kind: game.ActThrust, DX: dx, DY: dy})
 		}
 	}
 
 	// E near object → approach interaction, dock, or scan
-	if inpututil.IsKeyJustPressed(ebiten.KeyE) {
+	if keyJustPressed(KeyE) {
 		sm := g.sim.Sector.CurrentSystemMap()
 		obj := sm.NearestObject(sm.Shuttle.TileX(), sm.Shuttle.TileY(), 3)
 		if obj != nil {
 			switch obj.Kind {
 			case game.ObjStation:
 				// Dock at station
-				sd := g.sim.DockAtStation()
-				if sd != nil {
-					g.stationData = sd
+				if g.act(game.Action{Kind: game.ActDock}) {
+					g.stationData = sm.Station
 					g.stationMenu = stMenuMain
 					g.viewMode = ViewStation
 				}
 			case game.ObjPlanet:
 				// Enter orbit around planet → transitions to ship interior
 				objIdx := g.findObjectIndex(sm, obj)
-				if objIdx >= 0 {
-					g.sim.EnterOrbit(objIdx)
+				if objIdx >= 0 && g.act(game.Action{Kind: game.ActEnterOrbit, Index: objIdx}) {
 					g.viewMode = ViewShip
 				}
+			case game.ObjMonkeyLion:
+				if g.act(game.Action{Kind: game.ActBoardMonkeyLion}) {
+					g.viewMode = ViewEpisode
+				}
 			default:
 				g.logApproachInfo(obj)
 			}
@@ -1272,7 +1825,7 @@ func (g *Game) updateSystemMap() error {
 	g.drawScreen()
 
 	// Update FPS counter
-	fps := fmt.Sprintf("FPS: %.0f  TPS: %.0f", ebiten.ActualFPS(), ebiten.ActualTPS())
+	fps := fmt.Sprintf("FPS: %.0f  TPS: %.0f", actualFPS(), actualTPS())
 	g.buffer.WriteString(gridCols-20, gridRows-1, fps, render.ColorDarkGray, render.ColorBlack)
 
 	return nil
@@ -1323,6 +1876,8 @@ func (g *Game) drawStationView() {
 		g.drawStationBar(buf)
 	case stMenuFaction:
 		g.drawStationFaction(buf)
+	case stMenuMissions:
+		g.drawStationMissions(buf)
 	default:
 		g.drawStationMain(buf)
 	}
@@ -1350,8 +1905,9 @@ func (g *Game) drawStationMain(buf *render.CellBuffer) {
 	buf.WriteString(cx+2, 7, "1. Repairs & Maintenance", render.ColorLightGray, render.ColorBlack)
 	buf.WriteString(cx+2, 8, "2. Trade Goods", render.ColorLightGray, render.ColorBlack)
 	buf.WriteString(cx+2, 9, "3. Bar", render.ColorLightGray, render.ColorBlack)
-	buf.WriteString(cx+2, 10, fmt.Sprintf("4. %s Office", sd.Faction), render.ColorLightGray, render.ColorBlack)
-	buf.WriteString(cx+2, 12, "5. Undock", render.ColorYellow, render.ColorBlack)
+	buf.WriteString(cx+2, 10, fmt.Sprintf("4. %s Office", g.sim.Sector.FactionName(sd.FactionID)), render.ColorLightGray, render.ColorBlack)
+	buf.WriteString(cx+2, 11, fmt.Sprintf("5. Mission Board (%d)", len(sd.Board)), render.ColorLightGray, render.ColorBlack)
+	buf.WriteString(cx+2, 12, "6. Undock", render.ColorYellow, render.ColorBlack)
 
 	// Footer
 	r := &g.sim.Resources
@@ -1364,10 +1920,10 @@ func (g *Game) drawStationMain(buf *render.CellBuffer) {
 	buf.WriteString(infoX, 2, "--- Ship Status ---", render.ColorLightCyan, render.ColorBlack)
 	g.drawEnergyBar(infoX, 3, "Energy ", r.Energy, r.MaxEnergy, render.ColorYellow)
 	g.drawSimpleBar(infoX, 4, "Hull   ", r.Hull, r.MaxHull, render.ColorLightGray)
-	g.drawMatterBar(infoX, 6, "Water  ", &r.Water, render.ColorLightCyan, render.ColorBlue)
-	g.drawMatterBar(infoX, 7, "Organic", &r.Organic, render.ColorLightGreen, render.ColorGreen)
+	g.drawMatterBar(infoX, 6, "Water  ", r.Matter.Pool(game.MatterWater), render.ColorLightCyan, render.ColorBlue)
+	g.drawMatterBar(infoX, 7, "Organic", r.Matter.Pool(game.MatterOrganic), render.ColorLightGreen, render.ColorGreen)
 
-	buf.WriteString(2, gridRows-1, "1-5: Select  ESC: Undock", render.ColorDarkGray, render.ColorBlack)
+	buf.WriteString(2, gridRows-1, "1-6: Select  ESC: Undock", render.ColorDarkGray, render.ColorBlack)
 }
 
 func (g *Game) drawStationRepairs(buf *render.CellBuffer) {
@@ -1422,7 +1978,7 @@ func (g *Game) drawStationBuy(buf *render.CellBuffer) {
 	stocked := sd.StockedList()
 	row := 4
 	for i, k := range stocked {
-		price := sd.SellPrices[k]
+		price := g.sim.StationPrice(sd, k)
 		stock := sd.Stock[k]
 		clr := uint8(render.ColorLightGray)
 		if stock == 0 {
@@ -1458,9 +2014,16 @@ func (g *Game) drawStationSell(buf *render.CellBuffer) {
 			continue
 		}
 		anyItems = true
-		price := sd.BuyPrices[pad.Kind]
+		price := g.sim.StationOffer(sd, pad.Kind)
 		label := fmt.Sprintf("%d. %-18s %3dcr  (x%d)", i+1, game.CargoName(pad.Kind), price, pad.Count)
-		buf.WriteString(cx, row, label, render.ColorLightGray, render.ColorBlack)
+		clr := uint8(render.ColorLightGray)
+		if held := g.sim.ContractCargo(pad.Kind); held > 0 {
+			label += fmt.Sprintf("  %d contract", min(held, pad.Count))
+			if held >= r.CargoOf(pad.Kind) {
+				clr = render.ColorDarkGray
+			}
+		}
+		buf.WriteString(cx, row, label, clr, render.ColorBlack)
 		row++
 	}
 
@@ -1508,29 +2071,108 @@ func (g *Game) drawStationBar(buf *render.CellBuffer) {
 
 func (g *Game) drawStationFaction(buf *render.CellBuffer) {
 	sd := g.stationData
+	sector := g.sim.Sector
 	cx := 4
+	name := sector.FactionName(sd.FactionID)
 
-	buf.WriteString(cx, 2, fmt.Sprintf("--- %s OFFICE ---", sd.Faction), render.ColorLightCyan, render.ColorBlack)
+	buf.WriteString(cx, 2, fmt.Sprintf("--- %s OFFICE ---", strings.ToUpper(name)), render.ColorLightCyan, render.ColorBlack)
 
-	buf.WriteString(cx, 4, "A recruiter sits behind a battered desk covered in", render.ColorLightGray, render.ColorBlack)
-	buf.WriteString(cx, 5, "pamphlets. A poster reads:", render.ColorLightGray, render.ColorBlack)
-	buf.WriteString(cx, 7, fmt.Sprintf("\"JOIN THE %s.", sd.Faction), render.ColorWhite, render.ColorBlack)
-	buf.WriteString(cx, 8, " See the galaxy. Die heroically.", render.ColorWhite, render.ColorBlack)
-	buf.WriteString(cx, 9, " Pension not included.\"", render.ColorWhite, render.ColorBlack)
-
-	buf.WriteString(cx, 11, "The USS Monkey Lion emblem hangs on the wall.", render.ColorDarkGray, render.ColorBlack)
-	buf.WriteString(cx, 12, "You get the feeling this is where legends begin.", render.ColorDarkGray, render.ColorBlack)
-	buf.WriteString(cx, 13, "Or at least where the paperwork does.", render.ColorDarkGray, render.ColorBlack)
+	row := 4
+	if sd.FactionID >= 0 && sd.FactionID < len(sector.Factions) {
+		f := sector.Factions[sd.FactionID]
+		buf.WriteString(cx, row, game.FactionKindName(f.Kind), render.ColorLightGray, render.ColorBlack)
+		buf.WriteString(cx, row+2, "A recruiter sits behind a battered desk covered in", render.ColorLightGray, render.ColorBlack)
+		buf.WriteString(cx, row+3, "pamphlets. A poster reads:", render.ColorLightGray, render.ColorBlack)
+		buf.WriteString(cx, row+5, fmt.Sprintf("\"%s\"", f.Tagline), render.ColorWhite, render.ColorBlack)
+		row += 7
+		if f.Kind == game.FactionAuthority {
+			buf.WriteString(cx, row, "The USS Monkey Lion emblem hangs on the wall.", render.ColorDarkGray, render.ColorBlack)
+			buf.WriteString(cx, row+1, "You get the feeling this is where legends begin.", render.ColorDarkGray, render.ColorBlack)
+			buf.WriteString(cx, row+2, "Or at least where the paperwork does.", render.ColorDarkGray, render.ColorBlack)
+			row += 4
+		}
+	}
+
+	rep := g.sim.Rep(sd.FactionID)
+	buf.WriteString(cx, row, fmt.Sprintf("Your standing here: %+d (%s)", rep, game.ReputationTier(rep)), repColor(rep), render.ColorBlack)
+	row += 2
 
-	buf.WriteString(cx, 15, "\"Nothing available right now, but check back.\"", render.ColorLightGray, render.ColorBlack)
+	buf.WriteString(cx, row, "--- Known Factions ---", render.ColorLightCyan, render.ColorBlack)
+	row++
+	for i, f := range sector.Factions {
+		r := g.sim.Rep(i)
+		line := fmt.Sprintf("%-28s %-16s %+4d %s", f.Name, game.FactionKindName(f.Kind), r, game.ReputationTier(r))
+		buf.WriteString(cx, row, line, repColor(r), render.ColorBlack)
+		row++
+	}
 
-	buf.WriteString(cx, 17, "0. Back", render.ColorYellow, render.ColorBlack)
+	row++
+	buf.WriteString(cx, row, "0. Back", render.ColorYellow, render.ColorBlack)
 	buf.WriteString(2, gridRows-1, "0: Back", render.ColorDarkGray, render.ColorBlack)
 }
 
+// repColor colors a reputation score by tier.
+func repColor(rep int) uint8 {
+	switch {
+	case rep <= game.RepHostile:
+		return render.ColorLightRed
+	case rep <= game.RepUnfriendly:
+		return render.ColorYellow
+	case rep >= game.RepFriendly:
+		return render.ColorLightGreen
+	default:
+		return render.ColorLightGray
+	}
+}
+
+func (g *Game) drawStationMissions(buf *render.CellBuffer) {
+	sd := g.stationData
+	cx := 4
+	now := g.sim.Ticks
+
+	buf.WriteString(cx, 2, "--- MISSION BOARD ---", render.ColorLightCyan, render.ColorBlack)
+	row := 4
+	if len(sd.Board) == 0 {
+		buf.WriteString(cx, row, "\"Nothing today. Check back tomorrow.\"", render.ColorDarkGray, render.ColorBlack)
+		row++
+	}
+	for i := range sd.Board {
+		m := &sd.Board[i]
+		buf.WriteString(cx, row, fmt.Sprintf("%d. %s", i+1, m.Title), render.ColorLightGray, render.ColorBlack)
+		buf.WriteString(cx+3, row+1, fmt.Sprintf("%dcr  %dh", m.Reward, m.HoursLeft(now)), render.ColorYellow, render.ColorBlack)
+		row += 2
+	}
+
+	row++
+	buf.WriteString(cx, row, "--- ACTIVE CONTRACTS ---", render.ColorLightCyan, render.ColorBlack)
+	row++
+	if len(g.sim.Missions) == 0 {
+		buf.WriteString(cx, row, "None.", render.ColorDarkGray, render.ColorBlack)
+		row++
+	}
+	for i := range g.sim.Missions {
+		m := &g.sim.Missions[i]
+		clr := uint8(render.ColorLightGray)
+		if m.HoursLeft(now) < 4 {
+			clr = render.ColorLightRed
+		}
+		buf.WriteString(cx, row, fmt.Sprintf("%d. %s", i+7, m.Title), clr, render.ColorBlack)
+		status := fmt.Sprintf("%dcr  %dh left", m.Reward, m.HoursLeft(now))
+		if m.Retrieved {
+			status += "  (return to " + m.Giver + ")"
+		}
+		buf.WriteString(cx+3, row+1, status, render.ColorYellow, render.ColorBlack)
+		row += 2
+	}
+
+	row++
+	buf.WriteString(cx, row, "0. Back", render.ColorYellow, render.ColorBlack)
+	buf.WriteString(2, gridRows-1, "1-4: Accept  7-9: Abandon  0: Back", render.ColorDarkGray, render.ColorBlack)
+}
+
 func (g *Game) updateStation() error {
 	// ESC always undocks
-	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+	if keyJustPressed(KeyEscape) {
 		g.sim.Log.Add("Undocked.", game.MsgInfo)
 		g.stationData = nil
 		g.viewMode = ViewSystemMap
@@ -1549,6 +2191,8 @@ func (g *Game) updateStation() error {
 		g.updateStationBuy()
 	case stMenuSell:
 		g.updateStationSell()
+	case stMenuMissions:
+		g.updateStationMissions()
 	case stMenuBar, stMenuFaction:
 		if pressedDigit(0) {
 			g.stationMenu = stMenuMain
@@ -1557,7 +2201,7 @@ func (g *Game) updateStation() error {
 
 	g.drawScreen()
 
-	fps := fmt.Sprintf("FPS: %.0f  TPS: %.0f", ebiten.ActualFPS(), ebiten.ActualTPS())
+	fps := fmt.Sprintf("FPS: %.0f  TPS: %.0f", actualFPS(), actualTPS())
 	g.buffer.WriteString(gridCols-20, gridRows-1, fps, render.ColorDarkGray, render.ColorBlack)
 
 	return nil
@@ -1570,21 +2214,41 @@ func (g *Game) updateStationMain() {
 		g.stationMenu = stMenuTrade
 	} else if pressedDigit(3) {
 		g.stationMenu = stMenuBar
-		g.sim.Skills.AddXP(game.SkillDiplomacy, 1.0)
+		g.act(game.Action{Kind: game.ActVisitBar})
 	} else if pressedDigit(4) {
 		g.stationMenu = stMenuFaction
 	} else if pressedDigit(5) {
+		g.stationMenu = stMenuMissions
+	} else if pressedDigit(6) {
 		g.sim.Log.Add("Undocked.", game.MsgInfo)
 		g.stationData = nil
 		g.viewMode = ViewSystemMap
 	}
 }
 
+func (g *Game) updateStationMissions() {
+	for i := range g.stationData.Board {
+		if pressedDigit(i + 1) {
+			g.act(game.Action{Kind: game.ActAcceptMission, Index: i})
+			return
+		}
+	}
+	for i := range g.sim.Missions {
+		if pressedDigit(i + 7) {
+			g.act(game.Action{Kind: game.ActAbandonMission, Index: i})
+			return
+		}
+	}
+	if pressedDigit(0) {
+		g.stationMenu = stMenuMain
+	}
+}
+
 func (g *Game) updateStationRepairs() {
 	if pressedDigit(1) {
-		g.sim.RepairHull(0) // 0 = full repair
+		g.act(game.Action{Kind: game.ActRepairHull, Index: 0}) // 0 = full repair
 	} else if pressedDigit(2) {
-		g.sim.RepairHull(10)
+		g.act(game.Action{Kind: game.ActRepairHull, Index: 10})
 	} else if pressedDigit(0) {
 		g.stationMenu = stMenuMain
 	}
@@ -1605,7 +2269,7 @@ func (g *Game) updateStationBuy() {
 	stocked := sd.StockedList()
 	for i, k := range stocked {
 		if pressedDigit(i + 1) {
-			g.sim.BuyCargo(sd, k)
+			g.act(game.Action{Kind: game.ActBuyCargo, Index: int(k)})
 			break
 		}
 	}
@@ -1615,11 +2279,10 @@ func (g *Game) updateStationBuy() {
 }
 
 func (g *Game) updateStationSell() {
-	sd := g.stationData
 	r := &g.sim.Resources
 	for i := range r.CargoPads {
 		if pressedDigit(i + 1) {
-			g.sim.SellCargo(sd, i)
+			g.act(game.Action{Kind: game.ActSellCargo, Index: i})
 			break
 		}
 	}
@@ -1632,25 +2295,25 @@ func (g *Game) updateStationSell() {
 func pressedDigit(n int) bool {
 	switch n {
 	case 0:
-		return inpututil.IsKeyJustPressed(ebiten.Key0)
+		return keyJustPressed(Key0)
 	case 1:
-		return inpututil.IsKeyJustPressed(ebiten.Key1)
+		return keyJustPressed(Key1)
 	case 2:
-		return inpututil.IsKeyJustPressed(ebiten.Key2)
+		return keyJustPressed(Key2)
 	case 3:
-		return inpututil.IsKeyJustPressed(ebiten.Key3)
+		return keyJustPressed(Key3)
 	case 4:
-		return inpututil.IsKeyJustPressed(ebiten.Key4)
+		return keyJustPressed(Key4)
 	case 5:
-		return inpututil.IsKeyJustPressed(ebiten.Key5)
+		return keyJustPressed(Key5)
 	case 6:
-		return inpututil.IsKeyJustPressed(ebiten.Key6)
+		return keyJustPressed(Key6)
 	case 7:
-		return inpututil.IsKeyJustPressed(ebiten.Key7)
+		return keyJustPressed(Key7)
 	case 8:
-		return inpututil.IsKeyJustPressed(ebiten.Key8)
+		return keyJustPressed(Key8)
 	case 9:
-		return inpututil.IsKeyJustPressed(ebiten.Key9)
+		return keyJustPressed(Key9)
 	}
 	return false
 }
@@ -1717,23 +2380,23 @@ func (g *Game) drawCargoView() {
 }
 
 func (g *Game) updateCargo() error {
-	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+	if keyJustPressed(KeyEscape) {
 		g.viewMode = ViewShip
 		g.drawScreen()
 		return nil
 	}
 
 	r := &g.sim.Resources
-	shift := ebiten.IsKeyPressed(ebiten.KeyShift)
+	shift := keyPressed(KeyShift)
 
 	for i := range r.CargoPads {
 		if pressedDigit(i + 1) {
 			if shift {
 				// Jettison (throw away)
-				g.sim.JettisonCargo(i)
+				g.act(game.Action{Kind: game.ActJettison, Index: i})
 			} else {
 				// Incinerate (convert to fuel)
-				g.sim.IncinerateCargo(i)
+				g.act(game.Action{Kind: game.ActIncinerate, Index: i})
 			}
 			break
 		}
@@ -1741,7 +2404,7 @@ func (g *Game) updateCargo() error {
 
 	g.drawScreen()
 
-	fps := fmt.Sprintf("FPS: %.0f  TPS: %.0f", ebiten.ActualFPS(), ebiten.ActualTPS())
+	fps := fmt.Sprintf("FPS: %.0f  TPS: %.0f", actualFPS(), actualTPS())
 	g.buffer.WriteString(gridCols-20, gridRows-1, fps, render.ColorDarkGray, render.ColorBlack)
 
 	return nil
@@ -1784,6 +2447,11 @@ func (g *Game) drawEncounterView() {
 		clr = render.ColorLightRed
 	}
 	buf.WriteString(cx+1, 4, shipLine, clr, render.ColorBlack)
+	if enc.ShipObj != nil {
+		rep := g.sim.Rep(enc.ShipObj.Faction)
+		faction := fmt.Sprintf("%s (%s)", g.sim.Sector.FactionName(enc.ShipObj.Faction), game.ReputationTier(rep))
+		buf.WriteString(cx+2+len(shipLine), 4, faction, repColor(rep), render.ColorBlack)
+	}
 	buf.WriteString(cx, 5, "-----------------------------------------", render.ColorCyan, render.ColorBlack)
 
 	// Greeting
@@ -1792,6 +2460,9 @@ func (g *Game) drawEncounterView() {
 
 	// Options
 	row := 10
+	if c := enc.Combat; c != nil {
+		row = g.drawCombatStatus(cx, row, c)
+	}
 	for i, opt := range enc.Options {
 		label := fmt.Sprintf(" %d. %s", i+1, opt.Label)
 		optClr := uint8(render.ColorLightGray)
@@ -1839,6 +2510,8 @@ func (g *Game) drawEncounterView() {
 
 	if enc.Resolved {
 		g.Text(2, gridRows-1, "ESC: End transmission", render.ColorDarkGray)
+	} else if enc.Combat != nil {
+		g.Text(2, gridRows-1, "1-6: Choose action  (flee or bribe to disengage)", render.ColorDarkGray)
 	} else {
 		g.Text(2, gridRows-1, "1-9: Choose option  ESC: End transmission", render.ColorDarkGray)
 	}
@@ -1847,9 +2520,10 @@ func (g *Game) drawEncounterView() {
 func (g *Game) updateEncounter() error {
 	enc := g.sim.ActiveEncounter
 
-	// ESC ends the encounter
-	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
-		g.sim.EndEncounter()
+	// ESC ends the encounter (not mid-fight: flee or bribe instead)
+	inCombat := enc != nil && enc.Combat != nil && enc.Combat.Active()
+	if keyJustPressed(KeyEscape) && !inCombat {
+		g.act(game.Action{Kind: game.ActEndEncounter})
 		g.viewMode = g.prevViewMode
 		g.drawScreen()
 		return nil
@@ -1864,15 +2538,7 @@ func (g *Game) updateEncounter() error {
 					g.sim.Log.Add(opt.DisableText, game.MsgWarning)
 					break
 				}
-				result := g.sim.ResolveEncounterOption(i)
-				if result == "TRADE" {
-					// Special case: trader wants to trade — open station trade
-					// For now, just log it since we need a proper trade interface
-					enc.ResultText = "The trader opens a trade channel.\n(Space trading not yet available outside stations.)"
-					enc.Resolved = true
-				} else {
-					enc.ResultText = result
-				}
+				g.act(game.Action{Kind: game.ActResolveEncounter, Index: i})
 				break
 			}
 		}
@@ -1880,12 +2546,56 @@ func (g *Game) updateEncounter() error {
 
 	g.drawScreen()
 
-	fps := fmt.Sprintf("FPS: %.0f  TPS: %.0f", ebiten.ActualFPS(), ebiten.ActualTPS())
+	fps := fmt.Sprintf("FPS: %.0f  TPS: %.0f", actualFPS(), actualTPS())
 	g.buffer.WriteString(gridCols-20, gridRows-1, fps, render.ColorDarkGray, render.ColorBlack)
 
 	return nil
 }
 
+// drawCombatStatus draws both ships' state during a pirate fight and
+// returns the row below it.
+func (g *Game) drawCombatStatus(x, row int, c *game.CombatState) int {
+	buf := g.buffer
+	r := &g.sim.Resources
+
+	shields := "DOWN"
+	shieldClr := uint8(render.ColorDarkGray)
+	if g.sim.ShieldsUp() {
+		shields, shieldClr = "UP", render.ColorLightBlue
+	}
+	buf.WriteString(x+1, row, fmt.Sprintf("Your hull:   %3d/%d", r.Hull, r.MaxHull), hullColor(r.Hull, r.MaxHull), render.ColorBlack)
+	buf.WriteString(x+24, row, "Shields: "+shields, shieldClr, render.ColorBlack)
+	row++
+	buf.WriteString(x+1, row, fmt.Sprintf("Enemy hull:  %3d/%d", max(c.EnemyHull, 0), c.EnemyMaxHull), hullColor(c.EnemyHull, c.EnemyMaxHull), render.ColorBlack)
+	row++
+	sx := x + 1
+	for sub := game.SubsysWeapons; sub < game.SubsysCount; sub++ {
+		label := fmt.Sprintf("%s %d", game.SubsystemName(sub), c.Subsystems[sub])
+		clr := uint8(render.ColorLightGray)
+		if !c.SubsystemUp(sub) {
+			label = game.SubsystemName(sub) + " OUT"
+			clr = render.ColorDarkGray
+		}
+		buf.WriteString(sx, row, label, clr, render.ColorBlack)
+		sx += len(label) + 3
+	}
+	row++
+	buf.WriteString(x, row, fmt.Sprintf("--- Round %d ---", c.Round+1), render.ColorCyan, render.ColorBlack)
+	return row + 2
+}
+
+// hullColor shades a hull readout by how much is left.
+func hullColor(hull, maxHull int) uint8 {
+	switch {
+	case hull*4 <= maxHull:
+		return render.ColorLightRed
+	case hull*2 <= maxHull:
+		return render.ColorYellow
+	default:
+		return render.ColorLightGreen
+	}
+}
+
 // --- Episode view ---
 
 func (g *Game) drawEpisodeView() {
@@ -1989,15 +2699,15 @@ func (g *Game) updateEpisode() error {
 	ep := g.sim.ActiveEpisode
 
 	// ESC exits after resolution (or skips the episode)
-	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+	if keyJustPressed(KeyEscape) {
 		if ep != nil && ep.Resolved {
-			g.sim.EndEpisode()
+			g.act(game.Action{Kind: game.ActEndEpisode})
 			g.viewMode = ViewSystemMap
 			g.drawScreen()
 			return nil
 		}
 		// If not resolved, ESC still lets you leave (choosing to ignore)
-		g.sim.EndEpisode()
+		g.act(game.Action{Kind: game.ActEndEpisode})
 		g.viewMode = ViewSystemMap
 		g.drawScreen()
 		return nil
@@ -2011,8 +2721,7 @@ func (g *Game) updateEpisode() error {
 					g.sim.Log.Add(opt.DisableText, game.MsgWarning)
 					break
 				}
-				result := g.sim.ResolveEpisodeOption(i)
-				ep.ResultText = result
+				g.act(game.Action{Kind: game.ActResolveEpisode, Index: i})
 				break
 			}
 		}
@@ -2020,7 +2729,7 @@ func (g *Game) updateEpisode() error {
 
 	g.drawScreen()
 
-	fps := fmt.Sprintf("FPS: %.0f  TPS: %.0f", ebiten.ActualFPS(), ebiten.ActualTPS())
+	fps := fmt.Sprintf("FPS: %.0f  TPS: %.0f", actualFPS(), actualTPS())
 	g.buffer.WriteString(gridCols-20, gridRows-1, fps, render.ColorDarkGray, render.ColorBlack)
 
 	return nil
@@ -2044,24 +2753,45 @@ func (g *Game) drawSurfaceView() {
 		mapVpW = panelX        // 60 columns
 		mapVpH = commsRow      // 33 rows
 	)
-	// Player centered in map viewport
-	mapCenterX := mapVpX + mapVpW/2 // 30
-	mapCenterY := mapVpY + mapVpH/2 // 16
-
 	// Fill HUD areas with very subtle dark background
 	buf.FillRect(panelX, 0, gridCols-panelX, gridRows, render.ColorHUDBG)       // right panel
 	buf.FillRect(0, commsRow, panelX, gridRows-commsRow, render.ColorHUDBG)     // comms area
 
-	// Camera position: world coords of top-left of viewport
+	// Camera follows the player, scrolling until it meets the map edge.
+	// Surfaces smaller than the viewport stay centred on the player.
 	camX := surf.PlayerX - mapVpW/2
 	camY := surf.PlayerY - mapVpH/2
+	if surf.Width > mapVpW {
+		camX = max(0, min(camX, surf.Width-mapVpW))
+	}
+	if surf.Height > mapVpH {
+		camY = max(0, min(camY, surf.Height-mapVpH))
+	}
 
 	// Render terrain grid clipped to map viewport with fog of war
 	render.RenderSurfaceGridWithFog(buf, surf.Grid, surf.TerrainType, mapVpX, mapVpY, mapVpW, mapVpH, camX, camY,
 		surf.IsVisible, surf.IsSeen)
 
-	// Draw player at viewport center
-	buf.Set(mapCenterX, mapCenterY, '@', render.ColorWhite, render.ColorBlack)
+	// Draw the creatures the player can see
+	hostiles := 0
+	g.sim.Creatures(func(pos game.Position, c *game.Creature) {
+		sx, sy := mapVpX+pos.X-camX, mapVpY+pos.Y-camY
+		if sx < mapVpX || sx >= mapVpX+mapVpW || sy < mapVpY || sy >= mapVpY+mapVpH || !surf.IsVisible(pos.X, pos.Y) {
+			return
+		}
+		clr := uint8(render.ColorBrown)
+		switch c.State {
+		case game.CreatureHunt:
+			clr = render.ColorLightRed
+			hostiles++
+		case game.CreatureFlee:
+			clr = render.ColorYellow
+		}
+		buf.Set(sx, sy, game.SpeciesOf(c).Glyph, clr, render.ColorBlack)
+	})
+
+	// Draw player
+	buf.Set(mapVpX+surf.PlayerX-camX, mapVpY+surf.PlayerY-camY, '@', render.ColorWhite, render.ColorBlack)
 
 	// Draw shuttle marker if visible within map viewport and not in fog
 	shuttleScreenX := mapVpX + (surf.ShuttleX - camX)
@@ -2157,6 +2887,51 @@ func (g *Game) drawSurfaceView() {
 		cy += 2
 	}
 
+	// Light and weather
+	sightClr := uint8(render.ColorLightGray)
+	if surf.Night || surf.Storm {
+		sightClr = render.ColorYellow
+	}
+	g.Text(cx, cy, g.sim.SightStatus(), sightClr)
+	cy += 2
+
+	// Health, and whatever is after it
+	hpClr := uint8(render.ColorLightGray)
+	if hostiles > 0 {
+		hpClr = render.ColorLightRed
+	}
+	g.Text(cx, cy, fmt.Sprintf("Health %d/%d", g.sim.Needs.Health, g.sim.Needs.MaxHealth), hpClr)
+	cy++
+	if hostiles > 0 {
+		g.Text(cx, cy, fmt.Sprintf("Hostiles in sight: %d", hostiles), render.ColorLightRed)
+	}
+	cy += 2
+
+	// Suit, and what the surface is doing to it
+	if exp := surf.Exposure(); exp != game.ExposeNone {
+		g.Text(cx, cy, "Exposure: "+game.ExposureName(exp), render.ColorYellow)
+		cy++
+	}
+	o2Clr := uint8(render.ColorLightGray)
+	if g.sim.Resources.SuitO2 <= 25 {
+		o2Clr = render.ColorLightRed
+	}
+	g.Text(cx, cy, fmt.Sprintf("Suit O2 %d%%  +%d cans", g.sim.Resources.SuitO2, g.sim.Resources.CargoOf(game.CargoO2Canisters)), o2Clr)
+	cy++
+	if temp := g.sim.Needs.Temp; temp != 0 {
+		tempClr := uint8(render.ColorLightCyan)
+		if temp > 0 {
+			tempClr = render.ColorYellow
+		}
+		g.Text(cx, cy, fmt.Sprintf("Body temp %+d", temp), tempClr)
+		cy++
+	}
+	if dose := g.sim.Injuries[game.InjuryRadiation]; dose > 0 {
+		g.Text(cx, cy, fmt.Sprintf("Rad dose %d", dose), render.ColorLightGreen)
+		cy++
+	}
+	cy++
+
 	// Standing on indicator
 	tile := surf.GetTile(surf.PlayerX, surf.PlayerY)
 	g.Text(cx, cy, "Standing on:", render.ColorDarkGray)
@@ -2171,6 +2946,8 @@ func (g *Game) drawSurfaceView() {
 	cy++
 	g.Text(cx, cy, "E: Interact/Board", render.ColorDarkGray)
 	cy++
+	g.Text(cx, cy, "F: Fire sidearm", render.ColorDarkGray)
+	cy++
 	g.Text(cx, cy, "Pilot to lift off", render.ColorDarkGray)
 	cy += 2
 
@@ -2212,46 +2989,47 @@ func (g *Game) updateSurface() error {
 
 	// Movement
 	dx, dy := 0, 0
-	if inpututil.IsKeyJustPressed(ebiten.KeyW) || inpututil.IsKeyJustPressed(ebiten.KeyUp) {
+	if keyJustPressed(KeyW) || keyJustPressed(KeyUp) {
 		dy = -1
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyS) || inpututil.IsKeyJustPressed(ebiten.KeyDown) {
+	if keyJustPressed(KeyS) || keyJustPressed(KeyDown) {
 		dy = 1
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyA) || inpututil.IsKeyJustPressed(ebiten.KeyLeft) {
+	if keyJustPressed(KeyA) || keyJustPressed(KeyLeft) {
 		dx = -1
 	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyD) || inpututil.IsKeyJustPressed(ebiten.KeyRight) {
+	if keyJustPressed(KeyD) || keyJustPressed(KeyRight) {
 		dx = 1
 	}
 	if dx != 0 || dy != 0 {
-		g.sim.TrySurfaceMove(dx, dy)
+		g.act(game.Action{Kind: game.ActSurfaceMove, DX: dx, DY: dy})
 	}
 
 	// Interact
-	if inpututil.IsKeyJustPressed(ebiten.KeyE) {
+	if keyJustPressed(KeyE) {
 		if surf.AtShuttle() {
 			// Board the shuttle (doesn't lift off - use pilot console for that)
-			g.sim.BoardShuttle()
+			g.act(game.Action{Kind: game.ActBoardShuttle})
 			g.viewMode = ViewShip
 			return nil
 		} else {
 			// Interact with tile
-			if g.sim.InPrologue() {
-				g.sim.PrologueInteract()
-			} else {
-				g.sim.SurfaceInteract()
-			}
+			g.act(game.Action{Kind: game.ActSurfaceInteract})
 		}
 	}
 
+	// Fire at the nearest creature in sight
+	if keyJustPressed(KeyF) && !g.sim.InPrologue() {
+		g.act(game.Action{Kind: game.ActFireSidearm})
+	}
+
 	// ESC shows reminder
-	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+	if keyJustPressed(KeyEscape) {
 		g.sim.Log.Add("Return to the shuttle (H) to lift off.", game.MsgInfo)
 	}
 
 	// Tab → character sheet
-	if inpututil.IsKeyJustPressed(ebiten.KeyTab) {
+	if keyJustPressed(KeyTab) {
 		g.prevViewMode = ViewSurface
 		g.viewMode = ViewCharSheet
 		return nil
@@ -2259,7 +3037,7 @@ func (g *Game) updateSurface() error {
 
 	g.drawScreen()
 
-	fps := fmt.Sprintf("FPS: %.0f  TPS: %.0f", ebiten.ActualFPS(), ebiten.ActualTPS())
+	fps := fmt.Sprintf("FPS: %.0f  TPS: %.0f", actualFPS(), actualTPS())
 	g.buffer.WriteString(gridCols-20, gridRows-1, fps, render.ColorDarkGray, render.ColorBlack)
 
 	return nil
@@ -2323,6 +3101,7 @@ func (g *Game) drawCharSheetView() {
 	buf.FillRect(0, 0, gridCols, gridRows, render.ColorHUDBG)
 
 	cx := 2
+	perkX := 44 // right panel
 	skills := &g.sim.Skills
 	disc := g.sim.Discovery
 	r := &g.sim.Resources
@@ -2337,6 +3116,12 @@ func (g *Game) drawCharSheetView() {
 	buf.WriteString(cx+30, 3, fmt.Sprintf("Credits: %d", r.Credits), render.ColorYellow, render.ColorBlack)
 	buf.WriteString(cx, 4, "=========================================", render.ColorCyan, render.ColorBlack)
 
+	// Goal (right of the banner)
+	buf.WriteString(perkX, 1, "--- Goal: "+game.GoalName(g.sim.Goal)+" ---", render.ColorLightCyan, render.ColorBlack)
+	for i, line := range g.sim.GoalProgress() {
+		g.Text(perkX+1, 2+i, line, render.ColorLightGray)
+	}
+
 	// Skills section
 	buf.WriteString(cx, 6, "--- Skills ---", render.ColorLightCyan, render.ColorBlack)
 	for i := game.SkillID(0); i < game.SkillCount; i++ {
@@ -2377,7 +3162,6 @@ func (g *Game) drawCharSheetView() {
 	dRow++
 
 	// Perks (right panel)
-	perkX := 44
 	buf.WriteString(perkX, 6, "--- Perks ---", render.ColorLightCyan, render.ColorBlack)
 	perkRow := 7
 	for i := game.SkillID(0); i < game.SkillCount; i++ {
@@ -2415,6 +3199,24 @@ func (g *Game) drawCharSheetView() {
 		}
 	}
 
+	// Active contracts (right panel, below inventory)
+	mRow := invRow + 1
+	buf.WriteString(perkX, mRow, "--- Contracts ---", render.ColorLightCyan, render.ColorBlack)
+	mRow++
+	if len(g.sim.Missions) == 0 {
+		buf.WriteString(perkX+1, mRow, "None. Check station boards.", render.ColorDarkGray, render.ColorBlack)
+	}
+	for i := range g.sim.Missions {
+		m := &g.sim.Missions[i]
+		dest := m.DestName
+		if m.Retrieved {
+			dest = m.Giver
+		}
+		buf.WriteString(perkX+1, mRow, fmt.Sprintf("%s %dh %dcr", dest, m.HoursLeft(g.sim.Ticks), m.Reward),
+			render.ColorWhite, render.ColorBlack)
+		mRow++
+	}
+
 	// Recent scans
 	dRow++
 	buf.WriteString(cx, dRow, "--- Recent Scans ---", render.ColorLightCyan, render.ColorBlack)
@@ -2494,7 +3296,7 @@ func (g *Game) drawSkillBar(buf *render.CellBuffer, x, y int, name string, level
 }
 
 func (g *Game) updateCharSheet() error {
-	if inpututil.IsKeyJustPressed(ebiten.KeyTab) || inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+	if keyJustPressed(KeyTab) || keyJustPressed(KeyEscape) {
 		g.viewMode = g.prevViewMode
 		g.drawScreen()
 		return nil
@@ -2502,11 +3304,145 @@ func (g *Game) updateCharSheet() error {
 
 	g.drawScreen()
 
-	fps := fmt.Sprintf("FPS: %.0f  TPS: %.0f", ebiten.ActualFPS(), ebiten.ActualTPS())
+	fps := fmt.Sprintf("FPS: %.0f  TPS: %.0f", actualFPS(), actualTPS())
 	g.buffer.WriteString(gridCols-20, gridRows-1, fps, render.ColorDarkGray, render.ColorBlack)
 	return nil
 }
 
+// updateRunOver waits on the run-over screen: N starts a new run.
+func (g *Game) updateRunOver() error {
+	if keyJustPressed(KeyEscape) {
+		return errQuit
+	}
+	if keyJustPressed(KeyN) {
+		g.newRun()
+		return nil
+	}
+	g.drawScreen()
+	return nil
+}
+
+// updateGoalSelect picks the run's goal: W/S and Enter, or its number.
+// F9 skips it to continue the quicksaved run instead.
+func (g *Game) updateGoalSelect() error {
+	if keyJustPressed(KeyEscape) {
+		return errQuit
+	}
+	if keyJustPressed(KeyF9) {
+		g.quickLoad()
+		if g.viewMode != ViewGoalSelect {
+			return nil
+		}
+	}
+	if keyJustPressed(KeyW) || keyJustPressed(KeyUp) {
+		g.goalCursor = (g.goalCursor + game.GoalCount - 1) % game.GoalCount
+	}
+	if keyJustPressed(KeyS) || keyJustPressed(KeyDown) {
+		g.goalCursor = (g.goalCursor + 1) % game.GoalCount
+	}
+	for i := range game.GoalCount {
+		if pressedDigit(int(i) + 1) {
+			g.chooseGoal(i)
+			return nil
+		}
+	}
+	if keyJustPressed(KeyEnter) || keyJustPressed(KeyE) {
+		g.chooseGoal(g.goalCursor)
+		return nil
+	}
+	g.drawScreen()
+	return nil
+}
+
+// drawGoalSelectView lists the goals a run can be played for.
+func (g *Game) drawGoalSelectView() {
+	buf := g.buffer
+	buf.Clear()
+	buf.FillRect(0, 0, gridCols, gridRows, render.ColorHUDBG)
+	cx := 2
+
+	buf.WriteString(cx, 0, "--- CHOOSE YOUR GOAL ---", render.ColorLightCyan, render.ColorBlack)
+	buf.WriteString(gridCols-24, 0, "W/S, Enter: Start run", render.ColorDarkGray, render.ColorBlack)
+	buf.WriteString(cx, 2, fmt.Sprintf("Seed %d", g.sim.Galaxy.Seed), render.ColorDarkGray, render.ColorBlack)
+
+	row := 4
+	for i := range game.GoalCount {
+		nameClr, descClr := uint8(render.ColorLightGray), uint8(render.ColorDarkGray)
+		marker := "  "
+		if i == g.goalCursor {
+			nameClr, descClr = render.ColorYellow, render.ColorLightGray
+			marker = "> "
+		}
+		buf.WriteString(cx, row, fmt.Sprintf("%s%d. %s", marker, i+1, game.GoalName(i)), nameClr, render.ColorBlack)
+		g.Text(cx+5, row+1, game.GoalDescription(i), descClr)
+		row += 3
+	}
+
+	row++
+	if _, err := os.Stat(savePath); err == nil {
+		buf.WriteString(cx, row, "F9: Continue the saved run", render.ColorLightGreen, render.ColorBlack)
+		row++
+	}
+	buf.WriteString(cx, row, "ESC: Quit", render.ColorDarkGray, render.ColorBlack)
+}
+
+// drawRunOverView shows how the run ended, its score and the hall of fame.
+func (g *Game) drawRunOverView() {
+	buf := g.buffer
+	buf.Clear()
+	buf.FillRect(0, 0, gridCols, gridRows, render.ColorHUDBG)
+	sum := g.runSummary
+	cx := 2
+
+	buf.WriteString(cx, 0, "--- RUN OVER ---", render.ColorLightCyan, render.ColorBlack)
+	buf.WriteString(gridCols-24, 0, "N: New run  ESC: Quit", render.ColorDarkGray, render.ColorBlack)
+
+	if sum.Victory {
+		buf.WriteString(cx, 2, "VICTORY", render.ColorLightGreen, render.ColorBlack)
+	} else {
+		buf.WriteString(cx, 2, "YOU DIED", render.ColorLightRed, render.ColorBlack)
+	}
+	buf.WriteString(cx, 3, sum.Ending, render.ColorWhite, render.ColorBlack)
+	days := sum.Ticks / game.TicksPerDay
+	hours := sum.Ticks % game.TicksPerDay / game.TicksPerHour
+	buf.WriteString(cx, 4, fmt.Sprintf("%s - %d days %d hours - seed %d", sum.Goal, days, hours, sum.Seed),
+		render.ColorLightGray, render.ColorBlack)
+
+	row := 6
+	buf.WriteString(cx, row, "--- Score ---", render.ColorLightCyan, render.ColorBlack)
+	row++
+	for _, l := range sum.Lines {
+		buf.WriteString(cx+1, row, fmt.Sprintf("%-20s %6d %6d", l.Label, l.Count, l.Points), render.ColorLightGray, render.ColorBlack)
+		row++
+	}
+	buf.WriteString(cx+1, row, fmt.Sprintf("%-20s %13d", "Total", sum.Score), render.ColorYellow, render.ColorBlack)
+	row += 2
+
+	switch {
+	case g.scoreErr != "":
+		buf.WriteString(cx, row, "Hall of fame not saved: "+g.scoreErr, render.ColorLightRed, render.ColorBlack)
+	case g.runRank > 0:
+		buf.WriteString(cx, row, fmt.Sprintf("Entered the hall of fame at #%d!", g.runRank), render.ColorLightGreen, render.ColorBlack)
+	default:
+		buf.WriteString(cx, row, "Not enough for the hall of fame.", render.ColorDarkGray, render.ColorBlack)
+	}
+	row += 2
+
+	buf.WriteString(cx, row, "--- Hall of Fame ---", render.ColorLightCyan, render.ColorBlack)
+	row++
+	for i, run := range g.hallOfFame.Runs {
+		if row >= gridRows-1 {
+			break
+		}
+		clr := uint8(render.ColorLightGray)
+		if i+1 == g.runRank {
+			clr = render.ColorYellow
+		}
+		buf.WriteString(cx+1, row, fmt.Sprintf("%2d. %6d  %-14s %s  seed %d", i+1, run.Score, run.Goal, run.Date, run.Seed), clr, render.ColorBlack)
+		row++
+	}
+}
+
 // findObjectIndex returns the index of a SpaceObject in the system map's Objects slice.
 func (g *Game) findObjectIndex(sm *game.SystemMap, target *game.SpaceObject) int {
 	for i := range sm.Objects {
@@ -2540,6 +3476,11 @@ func (g *Game) updateHoverInfo(cellX, cellY int) {
 		tile := grid.Get(tileX, tileY)
 		if tile.Kind != world.TileVoid {
 			desc := tile.Describe()
+			if eq := tile.Equipment; eq != nil && eq.IsBroken() {
+				desc += " - BROKEN"
+			} else if eq != nil && eq.Condition < 100 {
+				desc += fmt.Sprintf(" - %d%%", eq.Condition)
+			}
 			coordInfo := fmt.Sprintf("  [%d,%d]", tileX, tileY)
 			g.buffer.WriteString(2, infoY, desc+coordInfo, render.ColorYellow, render.ColorBlack)
 			return
@@ -2549,29 +3490,47 @@ func (g *Game) updateHoverInfo(cellX, cellY int) {
 	g.buffer.WriteString(2, infoY, "Hover over the ship to inspect", render.ColorDarkGray, render.ColorBlack)
 }
 
-func (g *Game) Draw(screen *ebiten.Image) {
-	g.renderer.Draw(screen, g.buffer)
-	// Render floating sprites (sub-pixel precision) on top of the cell grid
-	for _, s := range g.sprites {
-		g.renderer.DrawFloating(screen, s.Glyph, s.FG, s.PX, s.PY)
-	}
-	// Render tight-spaced UI text on top
-	for _, t := range g.uiText {
-		g.renderer.DrawText(screen, t.X, t.Y, t.Text, t.FG)
-	}
-}
+func main() {
+	seed := flag.Int64("seed", 0, "run seed (0 = random)")
+	record := flag.String("record", "", "record a replay of this run to the given file")
+	mods := flag.String("mods", "mods", "directory of content pack mods (skipped if missing)")
+	turns := flag.Bool("turns", false, "start in turn-based mode (F2 toggles)")
+	goalName := flag.String("goal", "", "run goal, skipping the goal select: "+strings.Join(game.GoalKeys(), ", "))
+	flag.Parse()
 
-func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
-	return screenWidth, screenHeight
-}
+	goal, ok := game.ParseGoal(*goalName)
+	if *goalName != "" && !ok {
+		log.Fatalf("unknown goal %q", *goalName)
+	}
 
-func main() {
-	ebiten.SetWindowSize(screenWidth, screenHeight)
-	ebiten.SetWindowTitle(title)
-	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	content, err := game.LoadContent(*mods)
+	if err != nil {
+		log.Fatalf("content: %v", err)
+	}
+	game.UseContent(content)
+	if packs := game.ContentPacks(); len(packs) > 1 {
+		log.Printf("content packs: %s", strings.Join(packs, ", "))
+	}
 
-	game := NewGame()
-	if err := ebiten.RunGame(game); err != nil {
+	// Generate a seed from current time for this run
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+	g := NewGame(*seed)
+	if *turns {
+		g.policy = &game.TurnBased{}
+	}
+	if *record != "" {
+		if err := g.startRecording(*record, *seed); err != nil {
+			log.Fatalf("record: %v", err)
+		}
+	}
+	if *goalName != "" {
+		g.chooseGoal(goal)
+	}
+	err = run(g)
+	g.stopRecording()
+	if err != nil {
 		log.Fatal(err)
 	}
 }
@@ -0,0 +1,45 @@
+//go:build term
+
+package main
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// makeRaw switches the console to VT input and output: keys arrive as the
+// escape sequences a Unix terminal sends, unechoed, with Ctrl-C left to
+// the game. It returns a func to put it back.
+func makeRaw() (func(), error) {
+	in, out := windows.Handle(os.Stdin.Fd()), windows.Handle(os.Stdout.Fd())
+	var oldIn, oldOut uint32
+	if err := windows.GetConsoleMode(in, &oldIn); err != nil {
+		return nil, err
+	}
+	if err := windows.GetConsoleMode(out, &oldOut); err != nil {
+		return nil, err
+	}
+	rawIn := oldIn&^(windows.ENABLE_ECHO_INPUT|windows.ENABLE_LINE_INPUT|windows.ENABLE_PROCESSED_INPUT) | windows.ENABLE_VIRTUAL_TERMINAL_INPUT
+	if err := windows.SetConsoleMode(in, rawIn); err != nil {
+		return nil, err
+	}
+	if err := windows.SetConsoleMode(out, oldOut|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING); err != nil {
+		windows.SetConsoleMode(in, oldIn)
+		return nil, err
+	}
+	return func() {
+		windows.SetConsoleMode(in, oldIn)
+		windows.SetConsoleMode(out, oldOut)
+	}, nil
+}
+
+// termSize returns the console's columns and rows.
+func termSize() (int, int, error) {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(os.Stdout.Fd()), &info); err != nil {
+		return 0, 0, err
+	}
+	w := info.Window
+	return int(w.Right-w.Left) + 1, int(w.Bottom-w.Top) + 1, nil
+}
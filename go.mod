@@ -4,7 +4,9 @@ go 1.24.3
 
 require (
 	github.com/hajimehoshi/ebiten/v2 v2.9.7
+	github.com/mlange-42/ark v0.7.1
 	golang.org/x/image v0.35.0
+	golang.org/x/sys v0.36.0
 )
 
 require (
@@ -12,7 +14,5 @@ require (
 	github.com/ebitengine/hideconsole v1.0.0 // indirect
 	github.com/ebitengine/purego v0.9.0 // indirect
 	github.com/jezek/xgb v1.1.1 // indirect
-	github.com/mlange-42/ark v0.7.1 // indirect
 	golang.org/x/sync v0.17.0 // indirect
-	golang.org/x/sys v0.36.0 // indirect
 )
@@ -0,0 +1,180 @@
+package game
+
+// ActionKind identifies a player command that changes simulation state.
+type ActionKind uint8
+
+const (
+	ActMove             ActionKind = iota // walk inside the ship by (DX, DY)
+	ActInteract                           // E on the current ship tile
+	ActToggle                             // T on the current ship tile
+	ActLaunch                             // pilot console: lift off, break orbit or leave the prologue
+	ActScan                               // scan planet Index in the current system
+	ActStartEncounter                     // answer the pending hail
+	ActSelectSystem                       // move the sector map cursor to system Index
+	ActNavigate                           // spool the jump drive for system Index
+	ActThrust                             // fire thrusters on the system map in (DX, DY)
+	ActDock                               // dock at the current system's station
+	ActEnterOrbit                         // orbit planet Index in the current system
+	ActLeaveOrbit                         // break orbit
+	ActRepairHull                         // repair Index hull points (0 = full repair)
+	ActBuyCargo                           // buy one unit of CargoKind Index
+	ActSellCargo                          // sell one unit from cargo pad Index
+	ActVisitBar                           // sit down in the station bar
+	ActJettison                           // jettison one unit from cargo pad Index
+	ActIncinerate                         // incinerate one unit from cargo pad Index
+	ActResolveEncounter                   // pick encounter option Index
+	ActEndEncounter                       // close the encounter
+	ActResolveEpisode                     // pick episode option Index
+	ActEndEpisode                         // close the episode
+	ActSurfaceMove                        // walk on the surface by (DX, DY)
+	ActSurfaceInteract                    // E on the current surface tile
+	ActBoardShuttle                       // step from the surface into the shuttle
+	ActExitShuttle                        // step from the shuttle out onto the surface
+	ActAbortJump                          // cancel a spooling jump
+	ActRepairEquipment                    // R on the current ship tile
+	ActAcceptMission                      // take contract Index from the station mission board
+	ActAbandonMission                     // drop active contract Index
+	ActGateJump                           // spool the jump drive for the current system's sector gate
+	ActWake                               // get up before the player is rested
+	ActChooseGoal                         // pick Goal Index for the run, before the first tick
+	ActBoardMonkeyLion                    // board the Monkey Lion from alongside on the system map
+	ActFireSidearm                        // shoot the nearest creature in sight on the surface
+)
+
+// Action is a single semantic player command.
+// Every state change the UI makes goes through Sim.Apply as an Action,
+// so a run can be recorded and replayed from its seed.
+type Action struct {
+	Tick  uint64     `json:"t"` // frame the action was applied on (set by Recorder)
+	Kind  ActionKind `json:"a"`
+	DX    int        `json:"dx,omitempty"`
+	DY    int        `json:"dy,omitempty"`
+	Index int        `json:"i,omitempty"`
+}
+
+// Apply performs an action against the simulation.
+// Returns false if the action had no effect or was invalid.
+func (s *Sim) Apply(a Action) bool {
+	if s.Sleep != nil && a.Kind != ActWake {
+		return false // asleep: the only thing the player can do is wake up
+	}
+	switch a.Kind {
+	case ActMove:
+		return s.TryMovePlayer(a.DX, a.DY)
+	case ActInteract:
+		s.Interact()
+	case ActToggle:
+		s.ToggleEquipment()
+	case ActLaunch:
+		return s.Launch()
+	case ActScan:
+		if !s.validObject(a.Index) {
+			return false
+		}
+		s.ScanPlanet(a.Index)
+	case ActStartEncounter:
+		s.StartEncounter()
+		return s.ActiveEncounter != nil
+	case ActSelectSystem:
+		if a.Index < 0 || a.Index >= len(s.Sector.Systems) {
+			return false
+		}
+		s.Sector.CursorSystem = a.Index
+	case ActNavigate:
+		if a.Index < 0 || a.Index >= len(s.Sector.Systems) || a.Index == s.Sector.CurrentSystem {
+			return false
+		}
+		return s.NavigateTo(a.Index)
+	case ActThrust:
+		sm := s.Sector.CurrentSystemMap()
+		sm.Shuttle.ApplyThrust(a.DX, a.DY)
+		s.Skills.AddXP(SkillPiloting, 0.1)
+	case ActDock:
+		return s.DockAtStation() != nil
+	case ActEnterOrbit:
+		if !s.validObject(a.Index) {
+			return false
+		}
+		return s.EnterOrbit(a.Index)
+	case ActLeaveOrbit:
+		s.LeaveOrbit()
+	case ActRepairHull:
+		_, repaired := s.RepairHull(a.Index)
+		return repaired > 0
+	case ActBuyCargo:
+		sd := s.Sector.CurrentSystemMap().Station
+		if sd == nil || a.Index <= 0 || a.Index >= NumCargoKinds() {
+			return false
+		}
+		return s.BuyCargo(sd, CargoKind(a.Index))
+	case ActSellCargo:
+		sd := s.Sector.CurrentSystemMap().Station
+		if sd == nil {
+			return false
+		}
+		return s.SellCargo(sd, a.Index)
+	case ActVisitBar:
+		s.Skills.AddXP(SkillDiplomacy, 1.0)
+	case ActJettison:
+		return s.JettisonCargo(a.Index)
+	case ActIncinerate:
+		return s.IncinerateCargo(a.Index)
+	case ActResolveEncounter:
+		return s.ResolveEncounterOption(a.Index) != ""
+	case ActEndEncounter:
+		s.EndEncounter()
+	case ActResolveEpisode:
+		return s.ResolveEpisodeOption(a.Index) != ""
+	case ActEndEpisode:
+		s.EndEpisode()
+	case ActSurfaceMove:
+		return s.TrySurfaceMove(a.DX, a.DY)
+	case ActSurfaceInteract:
+		if s.InPrologue() {
+			s.PrologueInteract()
+		} else {
+			s.SurfaceInteract()
+		}
+	case ActBoardShuttle:
+		if s.ActiveSurface == nil {
+			return false
+		}
+		s.BoardShuttle()
+	case ActExitShuttle:
+		return s.ExitShuttle()
+	case ActAbortJump:
+		return s.AbortJump()
+	case ActRepairEquipment:
+		return s.RepairEquipment()
+	case ActAcceptMission:
+		sd := s.Sector.CurrentSystemMap().Station
+		if sd == nil {
+			return false
+		}
+		return s.AcceptMission(sd, a.Index)
+	case ActAbandonMission:
+		return s.AbandonMission(a.Index)
+	case ActGateJump:
+		return s.NavigateGate()
+	case ActWake:
+		return s.WakeUp()
+	case ActChooseGoal:
+		if a.Index < 0 {
+			return false
+		}
+		return s.SetGoal(Goal(a.Index))
+	case ActBoardMonkeyLion:
+		return s.BoardMonkeyLion()
+	case ActFireSidearm:
+		return s.FireSidearm()
+	default:
+		return false
+	}
+	return true
+}
+
+// validObject reports whether idx is an object in the current system map.
+func (s *Sim) validObject(idx int) bool {
+	sm := s.Sector.CurrentSystemMap()
+	return idx >= 0 && idx < len(sm.Objects)
+}
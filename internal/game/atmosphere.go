@@ -0,0 +1,376 @@
+package game
+
+import (
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/spacehole-rogue/spacehole_rogue/internal/world"
+)
+
+// Life support keeps the air aboard breathable. The ship grid is split into
+// rooms by its walls and doors (world.FindRooms) and every room holds its own
+// O2, CO2 and temperature. The player breathes the air of the room they stand
+// in, open doors mix neighbouring rooms, the hull leaks heat to whatever is
+// outside and a hull breach vents its room. Each running life support unit
+// pulls every room back toward standard air, drawing from the power
+// reservation like any constant-draw equipment.
+
+// Air is stepped once a second.
+const airInterval = 60
+
+// Standard shipboard air.
+var standardAir = RoomAir{O2: 21, CO2: 0.04, Temp: 20}
+
+// Air rates, per step.
+const (
+	breathRate      = 0.25   // O2 burned and CO2 breathed out, in percent of one tile of air
+	lifeSupportRate = 0.2    // share of the gap to standard air a unit closes
+	doorMixRate     = 0.5    // share of the gap between two rooms an open door closes
+	ventRate        = 0.5    // share of the gap to the outside a breach or open outer door closes
+	hullHeatLoss    = 0.0002 // share of the temperature gap lost per hull tile, per room tile
+)
+
+// What the air does to the player. Mild hazards cost 1 health every
+// airHarmInterval ticks, critical ones 1 health every step.
+const (
+	o2Low         = 16.0
+	o2Critical    = 10.0
+	co2High       = 4.0
+	co2Critical   = 8.0
+	tempCold      = -10.0
+	tempFreezing  = -40.0
+	tempHot       = 50.0
+	tempScorching = 80.0
+
+	airHarmInterval = 300
+)
+
+// Breach chance per point of hull damage, in percent.
+const breachChancePerDmg = 3
+
+// RoomAir is the atmosphere in one room.
+type RoomAir struct {
+	O2    float64  // percent of a standard atmosphere
+	CO2   float64  // percent of a standard atmosphere
+	Temp  float64  // degrees C
+	Holes [][2]int // breached hull tiles venting the room
+}
+
+// Air hazards, by cause.
+const (
+	hazardO2   = "suffocation"
+	hazardCO2  = "CO2 poisoning"
+	hazardCold = "hypothermia"
+	hazardHeat = "heatstroke"
+)
+
+var airDeathReasons = map[string]string{
+	hazardO2:   "You suffocated.",
+	hazardCO2:  "You died of CO2 poisoning.",
+	hazardCold: "You froze to death.",
+	hazardHeat: "You died of heatstroke.",
+}
+
+// hazard returns what the air is doing to someone breathing it, worst first,
+// and whether it is critical. cause is "" for breathable air.
+func (a *RoomAir) hazard() (cause string, critical bool) {
+	switch {
+	case a.O2 < o2Critical:
+		return hazardO2, true
+	case a.CO2 > co2Critical:
+		return hazardCO2, true
+	case a.Temp < tempFreezing:
+		return hazardCold, true
+	case a.Temp > tempScorching:
+		return hazardHeat, true
+	case a.O2 < o2Low:
+		return hazardO2, false
+	case a.CO2 > co2High:
+		return hazardCO2, false
+	case a.Temp < tempCold:
+		return hazardCold, false
+	case a.Temp > tempHot:
+		return hazardHeat, false
+	}
+	return "", false
+}
+
+// approach moves the air a share of the way toward other air.
+func (a *RoomAir) approach(to RoomAir, share float64) {
+	a.O2 += (to.O2 - a.O2) * share
+	a.CO2 += (to.CO2 - a.CO2) * share
+	a.Temp += (to.Temp - a.Temp) * share
+}
+
+// mixAir moves two rooms a share of the way toward their common mix,
+// weighted by volume so nothing is created or lost.
+func mixAir(a, b *RoomAir, va, vb, share float64) {
+	mix := func(x, y *float64) {
+		even := (*x*va + *y*vb) / (va + vb)
+		*x += (even - *x) * share
+		*y += (even - *y) * share
+	}
+	mix(&a.O2, &b.O2)
+	mix(&a.CO2, &b.CO2)
+	mix(&a.Temp, &b.Temp)
+}
+
+// initAir finds the ship's rooms and restores their air, or fills them with
+// standard air if the saved air doesn't fit the rooms.
+func (s *Sim) initAir(saved []RoomAir) {
+	s.Rooms = world.FindRooms(s.Grid, s.Layout.Rooms)
+	if len(saved) == len(s.Rooms.Rooms) {
+		s.Air = saved
+		return
+	}
+	s.Air = make([]RoomAir, len(s.Rooms.Rooms))
+	for i := range s.Air {
+		s.Air[i] = standardAir
+	}
+}
+
+// ambient returns the air outside the hull: vacuum in space, the planet's
+// atmosphere when landed.
+func (s *Sim) ambient() RoomAir {
+	if s.ActiveSurface == nil {
+		return RoomAir{Temp: -270}
+	}
+	switch s.ActiveSurface.TerrainType {
+	case world.TerrainIce:
+		return RoomAir{Temp: -80}
+	case world.TerrainVolcanic:
+		return RoomAir{CO2: 30, Temp: 90}
+	case world.TerrainInterior:
+		return standardAir
+	default:
+		return RoomAir{CO2: 1, Temp: -30}
+	}
+}
+
+// PlayerRoom returns the index of the room the player is in, or -1 when
+// they are outside the ship or in no room.
+func (s *Sim) PlayerRoom() int {
+	if s.Outside || s.Rooms == nil {
+		return -1
+	}
+	return s.Rooms.RoomAt(s.PlayerPos())
+}
+
+// AirHazard returns what the air is doing to the player ("suffocation",
+// "hypothermia", ...), or "" if they can breathe easy.
+func (s *Sim) AirHazard() string {
+	r := s.PlayerRoom()
+	if r < 0 {
+		return ""
+	}
+	cause, _ := s.Air[r].hazard()
+	return cause
+}
+
+// doorPassesAir returns true if a door lets air through: it is open, or it
+// is an inner door the player is standing in. Airlocks cycle and never do.
+func (s *Sim) doorPassesAir(d world.RoomDoor) bool {
+	eq := s.Grid.GetEquipment(d.X, d.Y)
+	if eq == nil || eq.Kind != world.EquipDoor {
+		return false
+	}
+	if eq.Open || d.Outside() {
+		return eq.Open
+	}
+	px, py := s.PlayerPos()
+	return !s.Outside && px == d.X && py == d.Y
+}
+
+// isOuterDoor returns true if the door at (x, y) leads out of the ship.
+func (s *Sim) isOuterDoor(x, y int) bool {
+	for _, d := range s.Rooms.Doors {
+		if d.X == x && d.Y == y {
+			return d.Outside()
+		}
+	}
+	return false
+}
+
+// tickAir steps the atmosphere in every room and lets it hurt the player.
+func (s *Sim) tickAir() {
+	if s.Ticks%airInterval != 0 || len(s.Air) == 0 {
+		return
+	}
+	rooms := s.Rooms.Rooms
+	out := s.ambient()
+
+	if r := s.PlayerRoom(); r >= 0 {
+		a := &s.Air[r]
+		vol := float64(rooms[r].Volume())
+		a.O2 = max(a.O2-breathRate/vol, 0)
+		a.CO2 += breathRate / vol
+	}
+
+	// Life support ducts reach every room; more units work faster
+	support := 0.0
+	for i := range s.Grid.Tiles {
+		eq := s.Grid.Tiles[i].Equipment
+		if eq != nil && eq.Kind == world.EquipLifeSupport && eq.On && !eq.IsBroken() {
+			support += lifeSupportRate * eq.Efficiency
+		}
+	}
+	if support > 0 {
+		for i := range s.Air {
+			s.Air[i].approach(standardAir, min(support, 1))
+		}
+	}
+
+	for _, d := range s.Rooms.Doors {
+		if !s.doorPassesAir(d) {
+			continue
+		}
+		if d.Outside() {
+			s.Air[max(d.A, d.B)].approach(out, ventRate)
+			continue
+		}
+		mixAir(&s.Air[d.A], &s.Air[d.B], float64(rooms[d.A].Volume()), float64(rooms[d.B].Volume()), doorMixRate)
+	}
+
+	for i := range rooms {
+		a := &s.Air[i]
+		vol := float64(rooms[i].Volume())
+		a.Temp += (out.Temp - a.Temp) * min(hullHeatLoss*float64(len(rooms[i].Hull))/vol, 1)
+		if n := len(a.Holes); n > 0 {
+			a.approach(out, min(ventRate*float64(n), 1))
+		}
+	}
+
+	s.harmFromAir()
+}
+
+// harmFromAir costs the player health for the air in their room.
+func (s *Sim) harmFromAir() {
+	r := s.PlayerRoom()
+	if r < 0 {
+		return
+	}
+	cause, critical := s.Air[r].hazard()
+	if cause == "" || !critical && s.Ticks%airHarmInterval != 0 {
+		return
+	}
+	s.Needs.Health = max(0, s.Needs.Health-1)
+}
+
+// damageHull takes hull damage. Each point has a chance to hole the hull
+// somewhere, venting the room behind it.
+func (s *Sim) damageHull(dmg int, rng *rand.Rand) {
+	if dmg <= 0 {
+		return
+	}
+	s.Resources.Hull = max(s.Resources.Hull-dmg, 0)
+	s.traumaFromHit(dmg, rng)
+	if s.Rooms == nil || rng.IntN(100) >= dmg*breachChancePerDmg {
+		return
+	}
+	var exposed []int
+	for i, room := range s.Rooms.Rooms {
+		if len(room.Hull) > len(s.Air[i].Holes) {
+			exposed = append(exposed, i)
+		}
+	}
+	if len(exposed) == 0 {
+		return
+	}
+	r := exposed[rng.IntN(len(exposed))]
+	room := &s.Rooms.Rooms[r]
+	for _, i := range rng.Perm(len(room.Hull)) {
+		if !hasHole(s.Air[r].Holes, room.Hull[i]) {
+			s.Air[r].Holes = append(s.Air[r].Holes, room.Hull[i])
+			break
+		}
+	}
+	s.Log.Add(fmt.Sprintf("HULL BREACH in the %s! Patch it (R) or close the doors.", room.Name), MsgCritical)
+}
+
+func hasHole(holes [][2]int, p [2]int) bool {
+	for _, h := range holes {
+		if h == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Breaches returns the hull tiles currently holed, across all rooms.
+func (s *Sim) Breaches() [][2]int {
+	var holes [][2]int
+	for _, a := range s.Air {
+		holes = append(holes, a.Holes...)
+	}
+	return holes
+}
+
+// breachInReach returns the player's room and the index of a hull breach
+// next to them, or -1, -1.
+func (s *Sim) breachInReach() (room, hole int) {
+	r := s.PlayerRoom()
+	if r < 0 {
+		return -1, -1
+	}
+	px, py := s.PlayerPos()
+	for i, h := range s.Air[r].Holes {
+		if max(h[0]-px, px-h[0]) <= 1 && max(h[1]-py, py-h[1]) <= 1 {
+			return r, i
+		}
+	}
+	return -1, -1
+}
+
+// patchBreach patches a hull breach with a tool kit or spare parts.
+// Returns false if there was nothing to patch it with.
+func (s *Sim) patchBreach(r, hole int) bool {
+	a := &s.Air[r]
+	res := &s.Resources
+	var source string
+	switch {
+	case res.Inventory.HasItem(ItemToolKit):
+		res.Inventory.RemoveItem(ItemToolKit, 1)
+		source = ItemName(ItemToolKit)
+	case res.Inventory.HasItem(ItemSpareParts):
+		res.Inventory.RemoveItem(ItemSpareParts, 1)
+		source = ItemName(ItemSpareParts)
+	case res.RemoveCargo(CargoSpareParts, 1) > 0:
+		source = CargoName(CargoSpareParts) + " from cargo"
+	default:
+		s.Log.Add("Need a tool kit or spare parts to patch the breach.", MsgWarning)
+		return false
+	}
+	a.Holes = append(a.Holes[:hole], a.Holes[hole+1:]...)
+	s.Log.Add(fmt.Sprintf("Patched the hull breach in the %s with %s.", s.Rooms.Rooms[r].Name, source), MsgInfo)
+	if s.Skills.AddXP(SkillEngineering, 5.0) {
+		LogLevelUp(s.Log, SkillEngineering, s.Skills.Level(SkillEngineering))
+	}
+	return true
+}
+
+// sealBreaches patches every hull breach, as a station's dock crew does.
+// Returns how many were sealed.
+func (s *Sim) sealBreaches() int {
+	sealed := 0
+	for i := range s.Air {
+		sealed += len(s.Air[i].Holes)
+		s.Air[i].Holes = nil
+	}
+	return sealed
+}
+
+// reportAir logs the air in every room.
+func (s *Sim) reportAir() {
+	for i, room := range s.Rooms.Rooms {
+		a := &s.Air[i]
+		msg := fmt.Sprintf("%s: O2 %.1f%% CO2 %.1f%% %.0fC", room.Name, a.O2, a.CO2, a.Temp)
+		prio := MsgInfo
+		if len(a.Holes) > 0 {
+			msg += " BREACHED"
+			prio = MsgCritical
+		} else if cause, _ := a.hazard(); cause != "" {
+			prio = MsgWarning
+		}
+		s.Log.Add(msg, prio)
+	}
+}
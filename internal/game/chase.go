@@ -0,0 +1,95 @@
+package game
+
+import (
+	"fmt"
+	"math"
+)
+
+// A pirate the player runs from, or ignores, gives chase at full burn.
+// Getting away is down to the flying: the pirate gives up once the shuttle
+// opens enough distance, and if it closes to boarding range it forces the
+// encounter again. Piloting makes the break-away burn harder and the
+// shuttle harder to track.
+
+const (
+	chaseCatchRange  = 2    // tiles: the pirate is alongside
+	chaseLoseRange   = 40   // tiles: the pirate loses the shuttle, less 2 per Piloting level
+	evasiveBurn      = 0.3  // share of the shuttle's top speed from breaking away...
+	evasiveBurnSkill = 0.07 // ...plus this per Piloting level
+)
+
+// startChase sets the pirate after the shuttle. A player fleeing gets an
+// evasive burn directly away from it to start with.
+func (s *Sim) startChase(ship *SpaceObject, flee bool) {
+	ship.Pursuing = true
+	sm := s.Sector.Systems[s.Sector.CurrentSystem].Map
+	if !flee || sm == nil {
+		return
+	}
+	sh := &sm.Shuttle
+	dx, dy := sh.X-float64(ship.X), sh.Y-float64(ship.Y)
+	d := math.Hypot(dx, dy)
+	if d == 0 {
+		dx, dy, d = 1, 0, 1 // right on top of us: any way out will do
+	}
+	burn := sh.MaxSpeed * min(evasiveBurn+evasiveBurnSkill*float64(s.SkillCheck(SkillPiloting)), 1)
+	sh.VX += dx / d * burn
+	sh.VY += dy / d * burn
+}
+
+// ChaseRange returns how far the shuttle has to get from a pursuing pirate
+// to lose it.
+func (s *Sim) ChaseRange() int {
+	return max(chaseLoseRange-2*s.SkillCheck(SkillPiloting), chaseCatchRange+1)
+}
+
+// Pursuer returns the nearest pirate chasing the shuttle in this system, and
+// its distance in tiles, or nil.
+func (s *Sim) Pursuer() (*SpaceObject, float64) {
+	sm := s.Sector.Systems[s.Sector.CurrentSystem].Map
+	if sm == nil {
+		return nil, 0
+	}
+	var best *SpaceObject
+	bestD := math.Inf(1)
+	for i := range sm.Objects {
+		obj := &sm.Objects[i]
+		if obj.Kind != ObjShip || !obj.Pursuing {
+			continue
+		}
+		if d := math.Hypot(sm.Shuttle.X-float64(obj.X), sm.Shuttle.Y-float64(obj.Y)); d < bestD {
+			best, bestD = obj, d
+		}
+	}
+	return best, bestD
+}
+
+// tickChase lets pursuing pirates lose the shuttle or catch it. A caught
+// shuttle is boarded: the pirate's encounter starts without a hail to ignore.
+func (s *Sim) tickChase() {
+	sm := s.Sector.Systems[s.Sector.CurrentSystem].Map
+	if sm == nil {
+		return
+	}
+	for i := range sm.Objects {
+		obj := &sm.Objects[i]
+		if obj.Kind != ObjShip || !obj.Pursuing {
+			continue
+		}
+		d := math.Hypot(sm.Shuttle.X-float64(obj.X), sm.Shuttle.Y-float64(obj.Y))
+		switch {
+		case d > float64(s.ChaseRange()):
+			obj.Pursuing = false
+			if s.Skills.AddXP(SkillPiloting, 5.0) {
+				LogLevelUp(s.Log, SkillPiloting, s.Skills.Level(SkillPiloting))
+			}
+			s.Log.Add(fmt.Sprintf("You lost %s. The chase is over.", obj.Name), MsgDiscovery)
+		case d <= chaseCatchRange && s.PendingHail == nil && s.ActiveEncounter == nil && !s.IsOnSurface():
+			obj.Pursuing = false
+			s.PendingHail = &HailState{Ship: obj, TicksLeft: hailTimeout}
+			s.StartEncounter()
+			s.Intercepted = true
+			s.Log.Add(fmt.Sprintf(">>> %s has run you down! <<<", obj.Name), MsgCritical)
+		}
+	}
+}
@@ -0,0 +1,327 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+
+	"github.com/spacehole-rogue/spacehole_rogue/internal/world"
+)
+
+// Subsystem identifies a targetable system on an enemy ship.
+type Subsystem uint8
+
+const (
+	SubsysHull    Subsystem = iota // aim for the hull (easiest shot)
+	SubsysWeapons                  // knocked out: the enemy can't shoot back
+	SubsysShields                  // knocked out: our shots aren't absorbed
+	SubsysEngines                  // knocked out: the enemy can't run and we escape easily
+	SubsysCount                    // sentinel
+)
+
+// CombatOutcome is how a fight ended.
+type CombatOutcome uint8
+
+const (
+	CombatOngoing   CombatOutcome = iota
+	CombatWon                     // enemy destroyed, loot collected
+	CombatFled                    // we got away
+	CombatEnemyFled               // enemy broke off
+	CombatLost                    // our hull gave out
+	CombatBribed                  // we paid our way out mid-fight
+)
+
+// CombatState is the turn-based fight inside a pirate encounter.
+// Each round the player picks an action, then the enemy answers.
+type CombatState struct {
+	Round        int
+	EnemyHull    int
+	EnemyMaxHull int
+	EnemyShield  int              // damage absorbed per hit while shields are up
+	EnemyDamage  int              // base damage per hit
+	Subsystems   [SubsysCount]int // health of each enemy subsystem (hull unused)
+	Outcome      CombatOutcome
+	LastRound    string // what happened last round, for display
+}
+
+// Combat actions, in the order they appear as encounter options.
+const (
+	combatFireHull = iota
+	combatFireWeapons
+	combatFireShields
+	combatFireEngines
+	combatFlee
+	combatBribe
+)
+
+// Enemy subsystem health and our hit chances.
+const (
+	subsystemHealth  = 12
+	baseHitChance    = 50 // percent, +6 per Combat level
+	subsystemPenalty = 15 // aiming at a subsystem is harder than the hull
+	shieldAbsorb     = 5  // damage our shield emitter absorbs per hit at full efficiency
+)
+
+// SubsystemName returns the display name for an enemy subsystem.
+func SubsystemName(sub Subsystem) string {
+	switch sub {
+	case SubsysHull:
+		return "hull"
+	case SubsysWeapons:
+		return "weapons"
+	case SubsysShields:
+		return "shields"
+	case SubsysEngines:
+		return "engines"
+	default:
+		return "unknown"
+	}
+}
+
+// Active returns true while the fight is still going.
+func (c *CombatState) Active() bool {
+	return c.Outcome == CombatOngoing
+}
+
+// SubsystemUp returns true if an enemy subsystem still works.
+func (c *CombatState) SubsystemUp(sub Subsystem) bool {
+	return c.Subsystems[sub] > 0
+}
+
+// newCombat rolls up a pirate's stats from the encounter seed.
+func newCombat(enc *EncounterState, sectorSeed int64) *CombatState {
+	seed := sectorSeed*1009 + int64(enc.ShipObj.X)*37 + int64(enc.ShipObj.Y)*13
+	rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed>>8|11)))
+	hull := 30 + rng.IntN(21)
+	c := &CombatState{
+		EnemyHull:    hull,
+		EnemyMaxHull: hull,
+		EnemyShield:  2 + rng.IntN(3),
+		EnemyDamage:  4 + rng.IntN(4),
+	}
+	for sub := SubsysWeapons; sub < SubsysCount; sub++ {
+		c.Subsystems[sub] = subsystemHealth
+	}
+	return c
+}
+
+// fightOption builds the pirate encounter's Fight option from the ship's weapons.
+func (s *Sim) fightOption() EncounterOption {
+	opt := EncounterOption{Label: "Fight", SkillReq: SkillCombat}
+	weapon := s.bestWeapon()
+	switch {
+	case s.Grid.CountEquipment(world.EquipWeapon) == 0:
+		opt.DisableText = "No weapons installed"
+	case weapon == nil:
+		opt.DisableText = "Weapons not armed (T at the cannon)"
+	default:
+		opt.Enabled = true
+	}
+	return opt
+}
+
+// bestWeapon returns the armed weapon we fight with, or nil.
+func (s *Sim) bestWeapon() *world.Equipment {
+	return s.bestRunning(world.EquipWeapon)
+}
+
+// ShieldsUp returns true if a shield emitter is running.
+func (s *Sim) ShieldsUp() bool {
+	return s.bestRunning(world.EquipShield) != nil
+}
+
+// startCombat turns a pirate encounter into a fight.
+func (s *Sim) startCombat(enc *EncounterState) string {
+	enc.Combat = newCombat(enc, s.Sector.Seed)
+	enc.Resolved = false
+	enc.Options = s.combatOptions(enc)
+	s.Log.Add(fmt.Sprintf("Engaging %s!", enc.ShipName), MsgCritical)
+	s.AdjustRep(enc.ShipObj.Faction, -5)
+	return "Weapons hot. The pirate powers up its guns."
+}
+
+// combatOptions lists the actions available this round.
+func (s *Sim) combatOptions(enc *EncounterState) []EncounterOption {
+	c := enc.Combat
+	armed := s.bestWeapon() != nil
+	fire := func(label string, sub Subsystem) EncounterOption {
+		opt := EncounterOption{Label: label, Enabled: armed, DisableText: "Weapons offline", SkillReq: SkillCombat}
+		if armed && sub != SubsysHull && !c.SubsystemUp(sub) {
+			opt.Enabled = false
+			opt.DisableText = "Already knocked out"
+		}
+		return opt
+	}
+	return []EncounterOption{
+		fire("Fire at hull", SubsysHull),
+		fire("Target weapons", SubsysWeapons),
+		fire("Target shields", SubsysShields),
+		fire("Target engines", SubsysEngines),
+		{Label: "Flee", Enabled: true, SkillReq: SkillPiloting},
+		s.bribeOption(enc.ShipObj.Faction),
+	}
+}
+
+// combatRng returns the dice for one combat round.
+func (s *Sim) combatRng(c *CombatState) *rand.Rand {
+	seed := s.Sector.Seed*4099 + int64(s.Ticks)*7 + int64(c.Round)
+	return rand.New(rand.NewPCG(uint64(seed), uint64(seed>>4|9)))
+}
+
+// resolveCombat plays one round: the player's action, then the enemy's reply.
+func resolveCombat(sim *Sim, enc *EncounterState, action int) string {
+	c := enc.Combat
+	c.Round++
+	rng := sim.combatRng(c)
+
+	var text string
+	switch action {
+	case combatFireHull, combatFireWeapons, combatFireShields, combatFireEngines:
+		text = sim.playerFire(c, Subsystem(action-combatFireHull), rng)
+		if c.EnemyHull <= 0 {
+			text += "\n" + sim.winCombat(enc, rng)
+			return sim.endCombat(enc, CombatWon, text)
+		}
+	case combatFlee:
+		// Breaking off means taking a parting shot. After that it's a chase,
+		// unless its engines are out
+		text = "You punch the engines and break away.\n" + sim.enemyFire(c, rng)
+		if sim.Resources.Hull <= 0 {
+			return sim.loseCombat(enc, text)
+		}
+		if !c.SubsystemUp(SubsysEngines) {
+			if sim.Skills.AddXP(SkillPiloting, 3.0) {
+				LogLevelUp(sim.Log, SkillPiloting, sim.Skills.Level(SkillPiloting))
+			}
+			sim.Log.Add(fmt.Sprintf("Escaped from %s.", enc.ShipName), MsgWarning)
+			return sim.endCombat(enc, CombatFled, text+"\nWith its engines dead, the pirate can only watch you go.")
+		}
+		sim.startChase(enc.ShipObj, true)
+		sim.Log.Add(fmt.Sprintf("Fleeing! %s gives chase.", enc.ShipName), MsgWarning)
+		return sim.endCombat(enc, CombatFled, text+"\nThe pirate comes after you. Outrun it!")
+	case combatBribe:
+		enc.Resolved = true // resolvePirate clears it if we can't pay
+		result := resolvePirate(sim, enc, 1)
+		if enc.Resolved {
+			return sim.endCombat(enc, CombatBribed, result)
+		}
+		text = result
+	}
+
+	// Enemy turn
+	if c.EnemyHull <= c.EnemyMaxHull/4 && c.SubsystemUp(SubsysEngines) && rng.IntN(2) == 0 {
+		sim.Log.Add(fmt.Sprintf("%s is retreating!", enc.ShipName), MsgDiscovery)
+		enc.ShipObj.DX, enc.ShipObj.DY = -enc.ShipObj.DX, -enc.ShipObj.DY
+		enc.ShipObj.Pursuing = false
+		return sim.endCombat(enc, CombatEnemyFled, text+"\nThe pirate, trailing plasma, turns tail and runs.")
+	}
+	text += "\n" + sim.enemyFire(c, rng)
+	if sim.Resources.Hull <= 0 {
+		return sim.loseCombat(enc, text)
+	}
+
+	c.LastRound = text
+	enc.Options = sim.combatOptions(enc)
+	return text
+}
+
+// playerFire shoots the armed weapon at an enemy subsystem.
+func (s *Sim) playerFire(c *CombatState, target Subsystem, rng *rand.Rand) string {
+	weapon := s.bestWeapon()
+	if weapon == nil {
+		return "Your weapons are offline."
+	}
+	s.useEquipment(weapon)
+
+	chance := baseHitChance + 6*s.SkillCheck(SkillCombat)
+	if target != SubsysHull {
+		chance -= subsystemPenalty
+	}
+	if rng.IntN(100) >= chance {
+		s.Skills.AddXP(SkillCombat, 0.5)
+		return "Your shot goes wide."
+	}
+
+	dmg := int(math.Round(float64(8+rng.IntN(5)) * weapon.Efficiency))
+	if c.SubsystemUp(SubsysShields) {
+		dmg = max(dmg-c.EnemyShield, 1)
+	}
+	if s.Skills.AddXP(SkillCombat, 2.0) {
+		LogLevelUp(s.Log, SkillCombat, s.Skills.Level(SkillCombat))
+	}
+
+	if target == SubsysHull {
+		c.EnemyHull -= dmg
+		return fmt.Sprintf("Direct hit! %d damage to the pirate's hull.", dmg)
+	}
+	// Subsystem shots split damage between the system and the hull
+	c.Subsystems[target] = max(c.Subsystems[target]-dmg, 0)
+	c.EnemyHull -= dmg / 2
+	if !c.SubsystemUp(target) {
+		return fmt.Sprintf("Hit! The pirate's %s are knocked out!", SubsystemName(target))
+	}
+	return fmt.Sprintf("Hit! %d damage to the pirate's %s.", dmg, SubsystemName(target))
+}
+
+// enemyFire is the pirate's reply. Shields soak part of each hit.
+func (s *Sim) enemyFire(c *CombatState, rng *rand.Rand) string {
+	if !c.SubsystemUp(SubsysWeapons) {
+		return "The pirate's guns are silent."
+	}
+	chance := 60 - 3*s.SkillCheck(SkillPiloting)
+	if rng.IntN(100) >= chance {
+		return "The pirate fires and misses."
+	}
+	dmg := c.EnemyDamage + rng.IntN(4)
+	absorbed := 0
+	if shield := s.bestRunning(world.EquipShield); shield != nil {
+		absorbed = min(dmg, int(math.Round(shieldAbsorb*shield.Efficiency)))
+		dmg -= absorbed
+		s.wear(shield, 1)
+	}
+	s.damageHull(dmg, rng)
+	if absorbed > 0 {
+		return fmt.Sprintf("The pirate hits! Shields absorb %d, hull takes %d.", absorbed, dmg)
+	}
+	return fmt.Sprintf("The pirate hits! Hull takes %d damage.", dmg)
+}
+
+// winCombat collects loot and leaves the pirate as a wreck.
+func (s *Sim) winCombat(enc *EncounterState, rng *rand.Rand) string {
+	credits := 20 + rng.IntN(41)
+	s.Resources.Credits += credits
+	loot := []CargoKind{CargoSpareParts, CargoScrapMetal, CargoPowerCells}[rng.IntN(3)]
+	got := s.Resources.AddCargo(loot, 1+rng.IntN(2))
+
+	enc.ShipObj.Kind = ObjDerelict
+	enc.ShipObj.Name = "Wreck of " + enc.ShipName
+
+	if s.Skills.AddXP(SkillCombat, 15.0) {
+		LogLevelUp(s.Log, SkillCombat, s.Skills.Level(SkillCombat))
+	}
+	s.Log.Add(fmt.Sprintf("Destroyed %s! Salvaged %dcr.", enc.ShipName, credits), MsgDiscovery)
+	s.AdjustRep(enc.ShipObj.Faction, -10)
+	s.AdjustRep(s.authority(), 8)
+	if got > 0 {
+		s.Log.Add(fmt.Sprintf("Recovered %dx %s from the wreck.", got, CargoName(loot)), MsgDiscovery)
+		return fmt.Sprintf("The pirate breaks apart! Salvaged %dcr and %dx %s.", credits, got, CargoName(loot))
+	}
+	return fmt.Sprintf("The pirate breaks apart! Salvaged %dcr. (Cargo bay full.)", credits)
+}
+
+// loseCombat ends the fight with the shuttle torn apart.
+func (s *Sim) loseCombat(enc *EncounterState, text string) string {
+	s.Resources.Hull = 0
+	s.PlayerDead = true
+	s.DeathReason = fmt.Sprintf("Your shuttle was torn apart by the %s.", enc.ShipName)
+	s.Log.Add(s.DeathReason, MsgCritical)
+	return s.endCombat(enc, CombatLost, text+"\nThe hull gives way.")
+}
+
+// endCombat records the outcome and closes the encounter.
+func (s *Sim) endCombat(enc *EncounterState, outcome CombatOutcome, text string) string {
+	enc.Combat.Outcome = outcome
+	enc.Combat.LastRound = text
+	enc.Resolved = true
+	return text
+}
@@ -7,3 +7,12 @@ type Position struct {
 
 // PlayerControlled marks the player entity.
 type PlayerControlled struct{}
+
+// Creature is a surface animal. Its Position is on the active surface.
+type Creature struct {
+	Species  SpeciesID
+	ID       int // spawn order on this surface, to keep its dice its own
+	Health   int
+	State    CreatureState
+	Cooldown int // ticks until it acts again
+}
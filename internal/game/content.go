@@ -0,0 +1,396 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spacehole-rogue/spacehole_rogue/assets"
+)
+
+// Content packs hold the game's names, text tables and cargo list as JSON.
+// The base pack is embedded from assets/content; mod packs in a directory
+// are loaded on top of it and add entries to any table. The Go constants
+// for twists and cargo name the base pack's entries, so the base pack must
+// list those first and in order.
+
+// Content is a set of content tables, either one pack or several merged.
+type Content struct {
+	Missions        []MissionDef   `json:"missions,omitempty"`
+	Twists          []TwistDef     `json:"twists,omitempty"`
+	Locations       []string       `json:"locations,omitempty"`
+	Characters      []CharacterDef `json:"characters,omitempty"`
+	StarNames       []string       `json:"star_names,omitempty"`
+	ShipNames       ShipNamePools  `json:"ship_names"`
+	Bar             BarPools       `json:"bar"`
+	StationTaglines []string       `json:"station_taglines,omitempty"`
+	Cargo           []CargoDef     `json:"cargo,omitempty"`
+
+	Packs []string `json:"-"` // packs merged into this content, base first
+}
+
+// MissionDef is an episode scenario.
+type MissionDef struct {
+	Name     string `json:"name"`
+	Category string `json:"category"` // investigate, military, research or support
+	Briefing string `json:"briefing"` // slots: {system}, {location}, {char}
+
+	category MissionCategory // parsed from Category by validate
+}
+
+// TwistDef is an episode complication. Twists past the built-in ones
+// (see TwistType) only add their reveal text to the outcome.
+type TwistDef struct {
+	Name   string `json:"name"`
+	Hint   string `json:"hint"`   // foreshadowing in the briefing
+	Reveal string `json:"reveal"` // what actually happens
+}
+
+// CharacterDef is an episode NPC archetype.
+type CharacterDef struct {
+	Type   string   `json:"type"`
+	Intro  string   `json:"intro"` // slot: {name}
+	Names  []string `json:"names"` // proper names, one is picked per episode
+	MLClue bool     `json:"ml_clue,omitempty"`
+}
+
+// ShipNamePools holds proper names for NPC ships by AI kind.
+type ShipNamePools struct {
+	Trader []string `json:"trader,omitempty"`
+	Patrol []string `json:"patrol,omitempty"`
+	Pirate []string `json:"pirate,omitempty"`
+}
+
+// BarPools holds the pieces station bar scenes are assembled from.
+type BarPools struct {
+	Scenes   []string `json:"scenes,omitempty"`
+	Rumors   []string `json:"rumors,omitempty"`
+	Deborahs []string `json:"deborahs,omitempty"`
+}
+
+// CargoDef is a tradeable cargo type. Its CargoKind is its position in
+// the merged cargo table.
+type CargoDef struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Price int    `json:"price"` // base price in credits
+}
+
+// builtinCargoIDs are the base pack's cargo ids, indexed by CargoKind.
+var builtinCargoIDs = [CargoKindCount]string{
+	"none", "scrap_metal", "water_ice", "ration_packs", "power_cells", "med_kits",
+	"circuitry", "rare_minerals", "alien_artifacts", "shuttle_fuel", "spare_parts", "power_pack",
+	"o2_canisters", "thermal_liners", "rad_shielding", "hazard_gear",
+}
+
+// basePackPath is the base pack inside assets.Content.
+const basePackPath = "content/base.json"
+
+// content is the active content. It starts as the base pack; UseContent
+// swaps in a set with mods merged.
+var content = mustLoadBase()
+
+func mustLoadBase() *Content {
+	c, err := loadBasePack()
+	if err != nil {
+		panic(fmt.Sprintf("base content pack: %v", err))
+	}
+	return c
+}
+
+func loadBasePack() (*Content, error) {
+	data, err := fs.ReadFile(assets.Content, basePackPath)
+	if err != nil {
+		return nil, err
+	}
+	c, err := parsePack(basePackPath, data)
+	if err != nil {
+		return nil, err
+	}
+	// CargoNone is not real cargo, so packs don't list it
+	c.Cargo = append([]CargoDef{{ID: "none", Name: "Empty"}}, c.Cargo...)
+	if err := c.validate(basePackPath, true); err != nil {
+		return nil, err
+	}
+	c.Packs = []string{"base"}
+	return c, nil
+}
+
+// LoadContent loads the base pack and every *.json pack in modDir, in file
+// name order. An empty or missing modDir loads the base pack alone.
+func LoadContent(modDir string) (*Content, error) {
+	c, err := loadBasePack()
+	if err != nil {
+		return nil, err
+	}
+	if modDir == "" {
+		return c, nil
+	}
+	entries, err := os.ReadDir(modDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("mod dir: %w", err)
+	}
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(modDir, e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		pack, err := parsePack(path, data)
+		if err != nil {
+			return nil, err
+		}
+		if err := pack.validate(path, false); err != nil {
+			return nil, err
+		}
+		if err := c.merge(path, pack); err != nil {
+			return nil, err
+		}
+		c.Packs = append(c.Packs, strings.TrimSuffix(e.Name(), ".json"))
+	}
+	return c, nil
+}
+
+// UseContent makes c the active content. Call it before creating a Sim;
+// a run must keep the same content for saves and replays to match.
+func UseContent(c *Content) {
+	content = c
+}
+
+// ContentPacks returns the names of the active content packs, base first.
+func ContentPacks() []string {
+	return content.Packs
+}
+
+// parsePack decodes one pack. Syntax errors and unknown fields are reported
+// with the line and column they were found at.
+func parsePack(path string, data []byte) (*Content, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var c Content
+	if err := dec.Decode(&c); err != nil {
+		offset := dec.InputOffset()
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntaxErr):
+			offset = syntaxErr.Offset
+		case errors.As(err, &typeErr):
+			offset = typeErr.Offset
+		case strings.HasPrefix(err.Error(), "json: unknown field "):
+			// The decoder reports the end of the object; point at the field
+			field := strings.TrimPrefix(err.Error(), "json: unknown field ")
+			if i := bytes.LastIndex(data[:offset], []byte(field)); i >= 0 {
+				offset = int64(i)
+			}
+		}
+		line, col := textPosition(data, offset)
+		return nil, fmt.Errorf("%s:%d:%d: %w", path, line, col, err)
+	}
+	return &c, nil
+}
+
+// textPosition converts a byte offset to a 1-based line and column.
+func textPosition(data []byte, offset int64) (line, col int) {
+	offset = min(max(offset, 0), int64(len(data)))
+	before := data[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	col = len(before) - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
+
+// validate checks every entry of a pack. The base pack must fill every
+// table and define the built-in twists and cargo. All problems are
+// reported, each naming the entry at fault.
+func (c *Content) validate(path string, base bool) error {
+	var errs []error
+	bad := func(entry, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s: %s", path, entry, fmt.Sprintf(format, args...)))
+	}
+	text := func(entry, s string, slots ...string) {
+		if strings.TrimSpace(s) == "" {
+			bad(entry, "is empty")
+			return
+		}
+		if slot := unknownSlot(s, slots); slot != "" {
+			bad(entry, "unknown slot %s (allowed: %s)", slot, slotList(slots))
+		}
+	}
+	texts := func(table string, list []string) {
+		for i, s := range list {
+			text(fmt.Sprintf("%s[%d]", table, i), s)
+		}
+	}
+
+	for i := range c.Missions {
+		m := &c.Missions[i]
+		entry := fmt.Sprintf("missions[%d]", i)
+		text(entry+".name", m.Name)
+		text(entry+".briefing", m.Briefing, "{system}", "{location}", "{char}")
+		cat, ok := parseMissionCategory(m.Category)
+		if !ok {
+			bad(entry+".category", "%q is not investigate, military, research or support", m.Category)
+		}
+		m.category = cat
+	}
+	for i, t := range c.Twists {
+		entry := fmt.Sprintf("twists[%d]", i)
+		text(entry+".name", t.Name)
+		text(entry+".hint", t.Hint)
+		text(entry+".reveal", t.Reveal)
+	}
+	for i, ch := range c.Characters {
+		entry := fmt.Sprintf("characters[%d]", i)
+		text(entry+".type", ch.Type)
+		text(entry+".intro", ch.Intro, "{name}")
+		if len(ch.Names) == 0 {
+			bad(entry+".names", "needs at least one name")
+		}
+		texts(entry+".names", ch.Names)
+	}
+	texts("locations", c.Locations)
+	texts("star_names", c.StarNames)
+	texts("ship_names.trader", c.ShipNames.Trader)
+	texts("ship_names.patrol", c.ShipNames.Patrol)
+	texts("ship_names.pirate", c.ShipNames.Pirate)
+	texts("bar.scenes", c.Bar.Scenes)
+	texts("bar.rumors", c.Bar.Rumors)
+	texts("bar.deborahs", c.Bar.Deborahs)
+	texts("station_taglines", c.StationTaglines)
+
+	first := 0
+	if base {
+		first = 1 // CargoNone
+	}
+	seen := make(map[string]bool)
+	for i := first; i < len(c.Cargo); i++ {
+		cg := c.Cargo[i]
+		entry := fmt.Sprintf("cargo[%d]", i-first)
+		switch {
+		case cg.ID == "":
+			bad(entry+".id", "is empty")
+		case seen[cg.ID]:
+			bad(entry+".id", "%q is listed twice", cg.ID)
+		case base && i < int(CargoKindCount) && cg.ID != builtinCargoIDs[i]:
+			bad(entry+".id", "is %q, want %q (built-in cargo must come first, in order)", cg.ID, builtinCargoIDs[i])
+		}
+		seen[cg.ID] = true
+		text(entry+".name", cg.Name)
+		if cg.Price <= 0 {
+			bad(entry+".price", "must be positive, got %d", cg.Price)
+		}
+	}
+
+	if base {
+		for table, n := range map[string]int{
+			"missions": len(c.Missions), "characters": len(c.Characters), "locations": len(c.Locations),
+			"star_names": len(c.StarNames), "ship_names.trader": len(c.ShipNames.Trader),
+			"ship_names.patrol": len(c.ShipNames.Patrol), "ship_names.pirate": len(c.ShipNames.Pirate),
+			"bar.scenes": len(c.Bar.Scenes), "bar.rumors": len(c.Bar.Rumors), "bar.deborahs": len(c.Bar.Deborahs),
+			"station_taglines": len(c.StationTaglines),
+		} {
+			if n == 0 {
+				bad(table, "the base pack must have at least one entry")
+			}
+		}
+		if len(c.Twists) < int(TwistCount) {
+			bad("twists", "the base pack must define the %d built-in twists, has %d", TwistCount, len(c.Twists))
+		}
+		if len(c.Cargo) < int(CargoKindCount) {
+			bad("cargo", "the base pack must define the %d built-in cargo types, has %d", CargoKindCount-1, len(c.Cargo)-1)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// merge appends a validated pack's entries to the content.
+func (c *Content) merge(path string, pack *Content) error {
+	for i, cg := range pack.Cargo {
+		for _, have := range c.Cargo {
+			if have.ID == cg.ID {
+				return fmt.Errorf("%s: cargo[%d].id: %q is already defined by another pack", path, i, cg.ID)
+			}
+		}
+	}
+	// Entries are indexed by uint8 kinds, so no table may pass 256
+	for table, n := range map[string]int{
+		"missions": len(c.Missions) + len(pack.Missions), "twists": len(c.Twists) + len(pack.Twists),
+		"locations": len(c.Locations) + len(pack.Locations), "characters": len(c.Characters) + len(pack.Characters),
+		"cargo": len(c.Cargo) + len(pack.Cargo),
+	} {
+		if n > 256 {
+			return fmt.Errorf("%s: %s: too many entries across packs (max 256)", path, table)
+		}
+	}
+	c.Missions = append(c.Missions, pack.Missions...)
+	c.Twists = append(c.Twists, pack.Twists...)
+	c.Locations = append(c.Locations, pack.Locations...)
+	c.Characters = append(c.Characters, pack.Characters...)
+	c.StarNames = append(c.StarNames, pack.StarNames...)
+	c.ShipNames.Trader = append(c.ShipNames.Trader, pack.ShipNames.Trader...)
+	c.ShipNames.Patrol = append(c.ShipNames.Patrol, pack.ShipNames.Patrol...)
+	c.ShipNames.Pirate = append(c.ShipNames.Pirate, pack.ShipNames.Pirate...)
+	c.Bar.Scenes = append(c.Bar.Scenes, pack.Bar.Scenes...)
+	c.Bar.Rumors = append(c.Bar.Rumors, pack.Bar.Rumors...)
+	c.Bar.Deborahs = append(c.Bar.Deborahs, pack.Bar.Deborahs...)
+	c.StationTaglines = append(c.StationTaglines, pack.StationTaglines...)
+	c.Cargo = append(c.Cargo, pack.Cargo...)
+	return nil
+}
+
+// unknownSlot returns the first {slot} in s that isn't allowed, or "".
+func unknownSlot(s string, allowed []string) string {
+	for {
+		start := strings.IndexByte(s, '{')
+		if start < 0 {
+			return ""
+		}
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			return s[start:]
+		}
+		slot := s[start : start+end+1]
+		ok := false
+		for _, a := range allowed {
+			ok = ok || slot == a
+		}
+		if !ok {
+			return slot
+		}
+		s = s[start+end+1:]
+	}
+}
+
+func slotList(slots []string) string {
+	if len(slots) == 0 {
+		return "none"
+	}
+	return strings.Join(slots, ", ")
+}
+
+// parseMissionCategory converts a pack's category name.
+func parseMissionCategory(name string) (MissionCategory, bool) {
+	switch name {
+	case "investigate":
+		return CatInvestigate, true
+	case "military":
+		return CatMilitary, true
+	case "research":
+		return CatResearch, true
+	case "support":
+		return CatSupport, true
+	default:
+		return 0, false
+	}
+}
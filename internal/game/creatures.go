@@ -0,0 +1,399 @@
+package game
+
+import (
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/mlange-42/ark/ecs"
+	"github.com/spacehole-rogue/spacehole_rogue/internal/world"
+)
+
+// Nothing lands alone. Every surface has its wildlife, drawn from the
+// bestiary for the planet's kind, and how much of it there is follows the
+// hazard the scan warned of: the harsher the world, the more of what lives
+// on it wants you dead. Creatures wander until they notice the player, hunt
+// them down, and run when badly hurt. Noticing works like sight: a creature
+// the player can't see can't see them either, so the dark and the weather
+// cut both ways.
+//
+// Creatures are ECS entities with a Position on the surface and a Creature
+// component. Their dice are rolled from the surface seed, the tick and their
+// spawn order, so a replay plays them out the same way.
+
+// CreatureState is what a creature is doing.
+type CreatureState uint8
+
+const (
+	CreatureWander CreatureState = iota // roaming, hasn't noticed the player
+	CreatureHunt                        // closing in to attack
+	CreatureFlee                        // badly hurt and running
+)
+
+// SpeciesID identifies a creature species in the bestiary.
+type SpeciesID uint8
+
+// Species describes a kind of creature.
+type Species struct {
+	Name   string
+	Glyph  byte
+	Health int
+	Damage int     // per hit
+	Range  int     // 1 bites; longer spits at a player it can see that close
+	Speed  int     // ticks between actions while hunting; wandering takes twice as long
+	Sight  int     // tiles it notices the player from
+	FleeAt int     // runs below this percent of its health, 0 never
+	XP     float64 // Combat XP for the kill
+	Attack string  // what a hit looks like: "bites", "spits acid at"
+}
+
+const (
+	SpeciesRockCrawler SpeciesID = iota
+	SpeciesDustStalker
+	SpeciesGritSpitter
+	SpeciesFrostMite
+	SpeciesIceWyrm
+	SpeciesRimeSpitter
+	SpeciesMagmaSlug
+	SpeciesAshHound
+	SpeciesCinderWasp
+	SpeciesDriftJelly
+	SpeciesStormRay
+	SpeciesCount // sentinel
+)
+
+var bestiary = [SpeciesCount]Species{
+	SpeciesRockCrawler: {"rock crawler", 'c', 8, 4, 1, 24, 7, 25, 3, "bites"},
+	SpeciesDustStalker: {"dust stalker", 's', 12, 6, 1, 16, 10, 0, 5, "rakes"},
+	SpeciesGritSpitter: {"grit spitter", 'p', 6, 3, 5, 30, 9, 50, 4, "spits grit at"},
+	SpeciesFrostMite:   {"frost mite", 'm', 5, 2, 1, 14, 6, 0, 2, "nips"},
+	SpeciesIceWyrm:     {"ice wyrm", 'W', 20, 8, 1, 28, 8, 20, 8, "crushes"},
+	SpeciesRimeSpitter: {"rime spitter", 'q', 7, 3, 5, 30, 9, 50, 4, "spits ice at"},
+	SpeciesMagmaSlug:   {"magma slug", 'g', 14, 5, 1, 36, 6, 0, 4, "sears"},
+	SpeciesAshHound:    {"ash hound", 'h', 10, 5, 1, 14, 11, 30, 5, "bites"},
+	SpeciesCinderWasp:  {"cinder wasp", 'w', 4, 3, 4, 12, 10, 50, 3, "stings"},
+	SpeciesDriftJelly:  {"drift jelly", 'j', 6, 4, 3, 40, 5, 0, 3, "lashes"},
+	SpeciesStormRay:    {"storm ray", 'r', 10, 6, 4, 18, 10, 30, 6, "arcs lightning at"},
+}
+
+// bestiaries lists what lives on each kind of planet.
+var bestiaries = map[PlanetKind][]SpeciesID{
+	PlanetRocky:    {SpeciesRockCrawler, SpeciesDustStalker, SpeciesGritSpitter},
+	PlanetIce:      {SpeciesFrostMite, SpeciesIceWyrm, SpeciesRimeSpitter},
+	PlanetVolcanic: {SpeciesMagmaSlug, SpeciesAshHound, SpeciesCinderWasp},
+	PlanetGas:      {SpeciesDriftJelly, SpeciesStormRay},
+}
+
+// hazardThreat is how much wildlife a scan hazard brings, 1 to 3.
+var hazardThreat = map[string]int{
+	"Unstable tectonic activity":       2,
+	"No breathable atmosphere":         1,
+	"Micrometeorite storm risk":        2,
+	"Crushing atmospheric pressure":    3,
+	"Severe electrical storms":         2,
+	"Intense radiation belts":          3,
+	"Extreme cold (-220C surface)":     2,
+	"Methane geyser eruptions":         2,
+	"Unstable ice shelf collapse risk": 1,
+	"Surface temperature 800C+":        3,
+	"Toxic gas emissions":              2,
+	"Unpredictable lava eruptions":     3,
+}
+
+const (
+	creatureSalt      = 0x6372656174757265 // keeps creature dice apart from the weather's
+	creatureClearance = 8                  // tiles around the shuttle pad nothing spawns in
+	creatureForget    = 2                  // a hunter gives up past this many times its sight
+	creatureHitChance = 70                 // percent, less 4 per Combat level
+	creatureArea      = 2000               // tiles of surface per 3×threat creatures
+
+	fistDamage     = 4  // plus 1 per 2 Combat levels
+	meleeHitBonus  = 20 // percent over baseHitChance: it's hard to miss up close
+	sidearmDamage  = 5  // plus 1 per 2 Combat levels
+	sidearmRange   = 8  // tiles
+	sidearmFalloff = 6  // percent less hit chance per tile
+)
+
+// SpeciesOf returns a creature's species.
+func SpeciesOf(c *Creature) *Species {
+	return &bestiary[c.Species]
+}
+
+// CreatureThreat returns how much wildlife a scan hazard brings, 1 to 3.
+func CreatureThreat(hazard string) int {
+	return max(hazardThreat[hazard], 1)
+}
+
+// creatureRoll returns a creature's dice roll for this tick. n tells
+// rolls made in the same tick apart.
+func (s *Sim) creatureRoll(c *Creature, n uint64) uint64 {
+	return surfaceRoll(s.ActiveSurface.Seed^creatureSalt, s.Ticks<<8|uint64(c.ID&0x3f)<<2|n&3)
+}
+
+// attackRoll returns the dice roll for the player's attack on a creature.
+// The creature and a count of the attacks made this tick tell rolls in the
+// same tick apart.
+func (s *Sim) attackRoll(c *Creature) uint64 {
+	s.attacks++
+	return surfaceRoll(s.ActiveSurface.Seed^creatureSalt^0xff, s.Ticks<<16|uint64(c.ID&0xff)<<8|s.attacks&0xff)
+}
+
+// spawnCreatures stocks the active surface with wildlife from the planet's
+// bestiary, more the worse its hazard and the larger the surface. Returns
+// how many were spawned.
+func (s *Sim) spawnCreatures(kind PlanetKind) int {
+	surf := s.ActiveSurface
+	species := bestiaries[kind]
+	if surf == nil || len(species) == 0 {
+		return 0
+	}
+	threat := CreatureThreat(surf.Hazard)
+	rng := rand.New(rand.NewPCG(uint64(surf.Seed), uint64(surf.Seed>>16|17)))
+	n := (3*threat + rng.IntN(threat+1)) * surf.Width * surf.Height / creatureArea
+
+	spawned := 0
+	for tries := 0; spawned < n && tries < 50*n; tries++ {
+		x, y := rng.IntN(surf.Width), rng.IntN(surf.Height)
+		if abs(x-surf.ShuttleX) < creatureClearance && abs(y-surf.ShuttleY) < creatureClearance {
+			continue
+		}
+		if !s.creatureCanStand(x, y) {
+			continue
+		}
+		id := species[rng.IntN(len(species))]
+		s.creatureMap.NewEntity(
+			&Position{X: x, Y: y},
+			&Creature{Species: id, ID: spawned, Health: bestiary[id].Health, Cooldown: rng.IntN(bestiary[id].Speed*2) + 1},
+		)
+		spawned++
+	}
+	return spawned
+}
+
+// collectCreatures returns every creature entity, in a stable order. The
+// slice is reused by the next call.
+func (s *Sim) collectCreatures() []ecs.Entity {
+	s.creatures = s.creatures[:0]
+	q := s.creatureFilter.Query()
+	for q.Next() {
+		s.creatures = append(s.creatures, q.Entity())
+	}
+	return s.creatures
+}
+
+// clearCreatures removes every creature, when the shuttle lifts off.
+func (s *Sim) clearCreatures() {
+	for _, e := range s.collectCreatures() {
+		s.ECS.RemoveEntity(e)
+	}
+	s.creatures = s.creatures[:0]
+}
+
+// CreatureAt returns the creature at (x, y) on the active surface.
+func (s *Sim) CreatureAt(x, y int) (*Creature, bool) {
+	e, ok := s.creatureAt(x, y)
+	if !ok {
+		return nil, false
+	}
+	_, c := s.creatureMap.Get(e)
+	return c, true
+}
+
+func (s *Sim) creatureAt(x, y int) (ecs.Entity, bool) {
+	q := s.creatureFilter.Query()
+	for q.Next() {
+		if pos, _ := q.Get(); pos.X == x && pos.Y == y {
+			e := q.Entity()
+			q.Close()
+			return e, true
+		}
+	}
+	return ecs.Entity{}, false
+}
+
+// Creatures calls fn for every creature on the active surface.
+func (s *Sim) Creatures(fn func(pos Position, c *Creature)) {
+	q := s.creatureFilter.Query()
+	for q.Next() {
+		pos, c := q.Get()
+		fn(*pos, c)
+	}
+}
+
+// creatureCanStand returns true if a creature can move onto (x, y): open
+// ground that isn't the shuttle pad, the player or another creature.
+func (s *Sim) creatureCanStand(x, y int) bool {
+	surf := s.ActiveSurface
+	if !surf.IsWalkable(x, y) || surf.GetTile(x, y).Kind == world.TileShuttlePad {
+		return false
+	}
+	if x == surf.PlayerX && y == surf.PlayerY {
+		return false
+	}
+	_, taken := s.creatureAt(x, y)
+	return !taken
+}
+
+// tickCreatures runs the wildlife on the active surface. While the player
+// is aboard the shuttle it only wanders.
+func (s *Sim) tickCreatures() {
+	surf := s.ActiveSurface
+	if surf == nil {
+		return
+	}
+	for _, e := range s.collectCreatures() {
+		pos, c := s.creatureMap.Get(e)
+		if c.Cooldown > 0 {
+			c.Cooldown--
+			continue
+		}
+		sp := SpeciesOf(c)
+		dx, dy := surf.PlayerX-pos.X, surf.PlayerY-pos.Y
+		d2 := dx*dx + dy*dy
+		seen := s.Outside && surf.IsVisible(pos.X, pos.Y)
+
+		switch {
+		case c.State == CreatureWander && seen && d2 <= sp.Sight*sp.Sight:
+			c.State = CreatureHunt
+			s.Log.Add(fmt.Sprintf("A %s has noticed you!", sp.Name), MsgWarning)
+		case c.State == CreatureHunt && (!s.Outside || d2 > creatureForget*creatureForget*sp.Sight*sp.Sight):
+			c.State = CreatureWander
+		}
+
+		switch c.State {
+		case CreatureHunt:
+			c.Cooldown = sp.Speed
+			if d2 == 1 || (d2 <= sp.Range*sp.Range && seen) {
+				s.creatureAttack(c, sp, d2 > 1)
+			} else {
+				s.stepCreature(pos, 1)
+			}
+		case CreatureFlee:
+			c.Cooldown = sp.Speed
+			s.stepCreature(pos, -1)
+		default:
+			c.Cooldown = 2 * sp.Speed
+			if r := s.creatureRoll(c, 0) % 6; r < 4 {
+				s.moveCreature(pos, compass[r][0], compass[r][1])
+			}
+		}
+	}
+}
+
+// compass holds the four directions creatures and the player move in.
+var compass = [4][2]int{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
+
+// moveCreature moves a creature by (dx, dy) if it can stand there.
+func (s *Sim) moveCreature(pos *Position, dx, dy int) bool {
+	if !s.creatureCanStand(pos.X+dx, pos.Y+dy) {
+		return false
+	}
+	pos.X += dx
+	pos.Y += dy
+	return true
+}
+
+// stepCreature moves a creature one tile toward the player (toward 1) or
+// away from them (toward -1), if any step gets it closer or further.
+func (s *Sim) stepCreature(pos *Position, toward int) {
+	surf := s.ActiveSurface
+	dist := func(x, y int) int {
+		dx, dy := surf.PlayerX-x, surf.PlayerY-y
+		return toward * (dx*dx + dy*dy)
+	}
+	best, bestDir := dist(pos.X, pos.Y), -1
+	for i, d := range compass {
+		x, y := pos.X+d[0], pos.Y+d[1]
+		if v := dist(x, y); v < best && s.creatureCanStand(x, y) {
+			best, bestDir = v, i
+		}
+	}
+	if bestDir >= 0 {
+		s.moveCreature(pos, compass[bestDir][0], compass[bestDir][1])
+	}
+}
+
+// creatureAttack has a hunting creature bite or spit at the player.
+func (s *Sim) creatureAttack(c *Creature, sp *Species, ranged bool) {
+	chance := creatureHitChance - 4*s.SkillCheck(SkillCombat)
+	if int(s.creatureRoll(c, 1)%100) >= chance {
+		if ranged {
+			s.Log.Add(fmt.Sprintf("The %s %s you and misses.", sp.Name, sp.Attack), MsgInfo)
+		} else {
+			s.Log.Add(fmt.Sprintf("You dodge the %s.", sp.Name), MsgInfo)
+		}
+		s.Skills.AddXP(SkillCombat, 0.2)
+		return
+	}
+	dmg := sp.Damage/2 + int(s.creatureRoll(c, 2)%uint64(sp.Damage-sp.Damage/2+1))
+	s.Needs.Health = max(s.Needs.Health-dmg, 0)
+	s.Log.Add(fmt.Sprintf("The %s %s you! -%d health.", sp.Name, sp.Attack, dmg), MsgCritical)
+	if s.Needs.Health == 0 {
+		s.killedBy = sp.Name
+	}
+}
+
+// PlayerAttack returns the player's chance to hit and damage per hit,
+// bare-handed or with the sidearm at a range in tiles.
+func (s *Sim) PlayerAttack(dist int) (chance, dmg int) {
+	combat := s.SkillCheck(SkillCombat)
+	if dist <= 1 {
+		return min(baseHitChance+meleeHitBonus+6*combat, 95), fistDamage + combat/2
+	}
+	return min(baseHitChance+6*combat-sidearmFalloff*dist, 95), sidearmDamage + combat/2
+}
+
+// attackCreature strikes or shoots a creature from dist tiles away.
+func (s *Sim) attackCreature(e ecs.Entity, dist int) {
+	_, c := s.creatureMap.Get(e)
+	sp := SpeciesOf(c)
+	chance, dmg := s.PlayerAttack(dist)
+	if int(s.attackRoll(c)%100) >= chance {
+		s.Log.Add(fmt.Sprintf("You miss the %s.", sp.Name), MsgInfo)
+		s.Skills.AddXP(SkillCombat, 0.5)
+		return
+	}
+	c.Health -= dmg
+	if c.State == CreatureWander {
+		c.State = CreatureHunt
+	}
+	if c.Health <= 0 {
+		s.ECS.RemoveEntity(e)
+		s.Log.Add(fmt.Sprintf("You kill the %s.", sp.Name), MsgDiscovery)
+		if s.Skills.AddXP(SkillCombat, sp.XP) {
+			LogLevelUp(s.Log, SkillCombat, s.Skills.Level(SkillCombat))
+		}
+		return
+	}
+	s.Log.Add(fmt.Sprintf("You hit the %s for %d.", sp.Name, dmg), MsgInfo)
+	if c.State != CreatureFlee && c.Health*100 < sp.FleeAt*sp.Health {
+		c.State = CreatureFlee
+		s.Log.Add(fmt.Sprintf("The %s turns and flees.", sp.Name), MsgInfo)
+	}
+	if s.Skills.AddXP(SkillCombat, 1.0) {
+		LogLevelUp(s.Log, SkillCombat, s.Skills.Level(SkillCombat))
+	}
+}
+
+// FireSidearm shoots the nearest creature the player can see within range.
+// Returns false if there is nothing to shoot at.
+func (s *Sim) FireSidearm() bool {
+	surf := s.ActiveSurface
+	if surf == nil || !s.Outside {
+		return false
+	}
+	target, best := ecs.Entity{}, sidearmRange*sidearmRange+1
+	for _, e := range s.collectCreatures() {
+		pos, _ := s.creatureMap.Get(e)
+		dx, dy := pos.X-surf.PlayerX, pos.Y-surf.PlayerY
+		if d2 := dx*dx + dy*dy; d2 < best && surf.IsVisible(pos.X, pos.Y) {
+			target, best = e, d2
+		}
+	}
+	if best > sidearmRange*sidearmRange {
+		s.Log.Add("Nothing in sight to shoot at.", MsgInfo)
+		return false
+	}
+	pos, _ := s.creatureMap.Get(target)
+	s.attackCreature(target, max(abs(pos.X-surf.PlayerX), abs(pos.Y-surf.PlayerY), 2))
+	return true
+}
@@ -8,9 +8,9 @@ import (
 // DiscoveryLog tracks what the player has found across the sector.
 type DiscoveryLog struct {
 	StarTypesSeen [5]bool      // indexed by StarType
-	SystemsVisited map[int]bool // indexed by system index
-	PlanetsScanned map[string]PlanetScanData // key = "sysIdx:objIdx"
-	StationsDocked map[int]bool // indexed by system index
+	SystemsVisited map[int]bool // indexed by Sector.SystemID
+	PlanetsScanned map[string]PlanetScanData // key = "sysID:objIdx"
+	StationsDocked map[int]bool // indexed by Sector.SystemID
 
 	TotalScans          int
 	TotalSystemsVisited int
@@ -18,6 +18,7 @@ type DiscoveryLog struct {
 	TotalStationsDocked int
 	EpisodesCompleted   int
 	MLCluesFound        int
+	MonkeyLionBoarded   bool
 
 	RecentScans []PlanetScanData // ordered newest-first, capped at 10
 }
@@ -41,9 +42,10 @@ func NewDiscoveryLog() *DiscoveryLog {
 	}
 }
 
-// ScanKey returns the map key for a planet scan.
-func ScanKey(sysIdx, objIdx int) string {
-	return fmt.Sprintf("%d:%d", sysIdx, objIdx)
+// ScanKey returns the map key for a planet scan. sysID is the
+// galaxy-wide Sector.SystemID of the planet's system.
+func ScanKey(sysID, objIdx int) string {
+	return fmt.Sprintf("%d:%d", sysID, objIdx)
 }
 
 // Flavor text pools per planet type.
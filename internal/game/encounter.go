@@ -24,6 +24,7 @@ type EncounterState struct {
 	Options    []EncounterOption
 	ResultText string // filled after player picks an option
 	Resolved   bool
+	Combat     *CombatState // non-nil once a pirate fight starts
 }
 
 // EncounterOption is a single choice available in an encounter menu.
@@ -43,28 +44,16 @@ type HailState struct {
 
 const hailTimeout = 300 // ticks before hail expires
 
-// Ship name pools (unique proper names, assigned during system generation).
-var traderShipNames = []string{
-	"Star Hauler", "Merchantman", "Cargo Queen", "Lucky Profit", "Silk Road",
-}
-
-var patrolShipNames = []string{
-	"Sentinel VII", "Watchdog", "Iron Law", "Peacekeeper", "Blue Line",
-}
-
-var pirateShipNames = []string{
-	"Void Fang", "Black Marlin", "Skull Dancer", "Dread Nail", "Gut Ripper",
-}
-
-// ShipProperName returns a proper name for a ship based on its AI type and a seed index.
+// ShipProperName returns a proper name for a ship based on its AI type and a
+// seed index. Names come from the content packs' ship name pools.
 func ShipProperName(kind ShipAIKind, idx int) string {
 	switch kind {
 	case AITrader:
-		return traderShipNames[idx%len(traderShipNames)]
+		return content.ShipNames.Trader[idx%len(content.ShipNames.Trader)]
 	case AIPatrol:
-		return patrolShipNames[idx%len(patrolShipNames)]
+		return content.ShipNames.Patrol[idx%len(content.ShipNames.Patrol)]
 	case AIPirate:
-		return pirateShipNames[idx%len(pirateShipNames)]
+		return content.ShipNames.Pirate[idx%len(content.ShipNames.Pirate)]
 	default:
 		return "Unknown"
 	}
@@ -138,7 +127,7 @@ func NewEncounter(ship *SpaceObject, sectorSeed int64, skills *PlayerSkills) *En
 			{Label: "Bribe (30cr)", Enabled: true},
 			{Label: "Bluff (Diplomacy Lv 3+)", Enabled: bluffEnabled, DisableText: "Diplomacy too low", SkillReq: SkillDiplomacy, SkillLevel: 3},
 			{Label: "Flee", Enabled: true},
-			{Label: "Fight", Enabled: false, DisableText: "Combat systems offline"},
+			{Label: "Fight", Enabled: false, DisableText: "Combat systems offline"}, // set by Sim.StartEncounter
 		}
 	}
 
@@ -168,6 +157,9 @@ func ResolveEncounter(sim *Sim, enc *EncounterState, optionIdx int) string {
 	if !opt.Enabled {
 		return opt.DisableText
 	}
+	if enc.Combat != nil && enc.Combat.Active() {
+		return resolveCombat(sim, enc, optionIdx)
+	}
 
 	enc.Resolved = true
 
@@ -188,6 +180,7 @@ func resolveTrader(sim *Sim, enc *EncounterState, idx int) string {
 		if sim.Skills.AddXP(SkillDiplomacy, 1.0) {
 			LogLevelUp(sim.Log, SkillDiplomacy, sim.Skills.Level(SkillDiplomacy))
 		}
+		sim.AdjustRep(enc.ShipObj.Faction, 1)
 		responses := []string{
 			"\"Safe travels, friend. The void is kinder to those who talk first.\"",
 			"\"Always nice to meet a friendly face out here. Most just shoot.\"",
@@ -205,13 +198,18 @@ func resolveTrader(sim *Sim, enc *EncounterState, idx int) string {
 		if sim.Skills.AddXP(SkillDiplomacy, 2.0) {
 			LogLevelUp(sim.Log, SkillDiplomacy, sim.Skills.Level(SkillDiplomacy))
 		}
-		// 40% chance of success
+		// 40% chance of success, 60% if the guild likes you
+		odds := 2
+		if sim.Rep(enc.ShipObj.Faction) >= RepFriendly {
+			odds = 3
+		}
 		seed := sim.Sector.Seed*333 + int64(enc.ShipObj.X) + int64(sim.Ticks)
 		rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed>>4|5)))
-		if rng.IntN(5) < 2 {
+		if rng.IntN(5) < odds {
 			// Success — give some water and food
-			sim.Resources.Water.Clean += 5
-			sim.Resources.Organic.Clean += 3
+			sim.Resources.Matter.Store(-1, MatterWater, 5, false)
+			sim.Resources.Matter.Store(-1, MatterOrganic, 3, false)
+			sim.AdjustRep(enc.ShipObj.Faction, 1)
 			return "\"Here, take these. We've got plenty.\"\n+5 clean water, +3 clean organics."
 		}
 		return "\"Sorry, friend. We're running lean ourselves. Can't spare any.\""
@@ -223,44 +221,76 @@ func resolveTrader(sim *Sim, enc *EncounterState, idx int) string {
 }
 
 func resolvePatrol(sim *Sim, enc *EncounterState, idx int) string {
+	authority := enc.ShipObj.Faction
+	rep := sim.Rep(authority)
 	switch idx {
 	case 0: // Identify yourself
 		if sim.Skills.AddXP(SkillDiplomacy, 1.0) {
 			LogLevelUp(sim.Log, SkillDiplomacy, sim.Skills.Level(SkillDiplomacy))
 		}
+		if rep <= RepUnfriendly {
+			// Known troublemaker: pay a fine to clear the record a little
+			fine := min(sim.Resources.Credits, 20)
+			sim.Resources.Credits -= fine
+			sim.AdjustRep(authority, 2)
+			sim.Log.Add(fmt.Sprintf("Patrol fine: -%dcr.", fine), MsgWarning)
+			return fmt.Sprintf("\"Your record is flagged, pilot. That's a %dcr fine.\"\nThey wait until the transfer clears.", fine)
+		}
+		sim.AdjustRep(authority, 1)
+		if rep >= RepFriendly {
+			return "\"Good to see you again, pilot. Carry on.\""
+		}
 		return "\"Credentials check out. Carry on, civilian. Stay safe.\""
 
 	case 1: // Report pirate activity
 		// Check if there's a pirate in the current system
 		sm := sim.Sector.CurrentSystemMap()
-		hasPirate := false
+		var pirate *SpaceObject
 		for i := range sm.Objects {
 			if sm.Objects[i].Kind == ObjShip && sm.Objects[i].AIKind == AIPirate {
-				hasPirate = true
+				pirate = &sm.Objects[i]
 				break
 			}
 		}
-		if hasPirate {
-			sim.Resources.Credits += 25
+		if pirate != nil {
+			// The patrol pays better bounties to pilots it trusts
+			bounty := max(25+rep/4, 10)
+			sim.Resources.Credits += bounty
 			if sim.Skills.AddXP(SkillDiplomacy, 3.0) {
 				LogLevelUp(sim.Log, SkillDiplomacy, sim.Skills.Level(SkillDiplomacy))
 			}
-			sim.Log.Add("Bounty received: +25cr.", MsgDiscovery)
-			return "\"Confirmed. We've dispatched units. Here's a bounty for the intel. +25cr.\""
+			sim.Log.Add(fmt.Sprintf("Bounty received: +%dcr.", bounty), MsgDiscovery)
+			sim.AdjustRep(authority, 3)
+			sim.AdjustRep(pirate.Faction, -3)
+			return fmt.Sprintf("\"Confirmed. We've dispatched units. Here's a bounty for the intel. +%dcr.\"", bounty)
 		}
 		return "\"We have no reports of pirate activity in this sector. False alarm.\""
 
 	case 2: // Request escort
-		return "\"We can't spare the resources for an escort right now.\nStay on marked lanes and you'll be fine. Probably.\""
+		if rep < RepFriendly {
+			return "\"We can't spare the resources for an escort right now.\nStay on marked lanes and you'll be fine. Probably.\""
+		}
+		// Friends of the patrol get an escort: no pirate here will hail them
+		sm := sim.Sector.CurrentSystemMap()
+		for i := range sm.Objects {
+			if sm.Objects[i].Kind == ObjShip && sm.Objects[i].AIKind == AIPirate {
+				sm.Objects[i].Hailed = true
+				sm.Objects[i].Pursuing = false
+			}
+		}
+		sim.Log.Add(fmt.Sprintf("%s is escorting you through the system.", enc.ShipName), MsgInfo)
+		return "\"For you? Of course. We'll keep the pirates off your back\nwhile you're in this system.\""
 
 	case 3: // Ignore
 		sim.Log.Add("Patrol logs you as uncooperative.", MsgWarning)
+		sim.AdjustRep(authority, -3)
 		return "The patrol vessel notes your non-compliance and moves on."
 	}
 	return ""
 }
 
 func resolvePirate(sim *Sim, enc *EncounterState, idx int) string {
+	enc.ShipObj.Pursuing = false // talking or fighting ends any chase; fleeing starts a new one
 	switch idx {
 	case 0: // Surrender cargo
 		lostCargo := 0
@@ -275,10 +305,15 @@ func resolvePirate(sim *Sim, enc *EncounterState, idx int) string {
 		lostCredits := sim.Resources.Credits / 2
 		sim.Resources.Credits -= lostCredits
 		sim.Log.Add(fmt.Sprintf("Lost %d cargo units and %dcr to pirates.", lostCargo, lostCredits), MsgCritical)
+		sim.AdjustRep(enc.ShipObj.Faction, 5)
 		return fmt.Sprintf("\"Pleasure doing business.\"\nYou lost %d cargo units and %dcr.", lostCargo, lostCredits)
 
 	case 1: // Bribe
-		cost := 30
+		cost := sim.pirateBribe(enc.ShipObj.Faction)
+		if cost == 0 {
+			enc.Resolved = false
+			return "\"Keep your credits. We want your hide.\""
+		}
 		if sim.Resources.Credits < cost {
 			enc.Resolved = false // let them pick again
 			return fmt.Sprintf("You don't have %dcr. The pirate is not amused.", cost)
@@ -288,6 +323,7 @@ func resolvePirate(sim *Sim, enc *EncounterState, idx int) string {
 			LogLevelUp(sim.Log, SkillDiplomacy, sim.Skills.Level(SkillDiplomacy))
 		}
 		sim.Log.Add(fmt.Sprintf("Bribed pirate: -%dcr.", cost), MsgWarning)
+		sim.AdjustRep(enc.ShipObj.Faction, 3)
 		return fmt.Sprintf("\"Smart choice.\" The pirate pockets your %dcr and warps away.", cost)
 
 	case 2: // Bluff
@@ -295,28 +331,29 @@ func resolvePirate(sim *Sim, enc *EncounterState, idx int) string {
 			LogLevelUp(sim.Log, SkillDiplomacy, sim.Skills.Level(SkillDiplomacy))
 		}
 		// Skill check: higher diplomacy = better odds
-		dipLevel := sim.Skills.Level(SkillDiplomacy)
+		dipLevel := sim.SkillCheck(SkillDiplomacy)
 		seed := sim.Sector.Seed*999 + int64(enc.ShipObj.X) + int64(sim.Ticks)
 		rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed>>4|7)))
 		roll := rng.IntN(10)
 		// Need to roll under diplomacy level (min 3 to even try)
 		if roll < dipLevel {
 			sim.Log.Add("Bluff successful! The pirate backs down.", MsgDiscovery)
+			sim.AdjustRep(enc.ShipObj.Faction, -3)
 			return "\"Wait... you're with the Patrol? Forget it, we're leaving!\"\nYour bluff worked."
 		}
 		sim.Log.Add("Bluff failed. The pirate sees through you.", MsgWarning)
+		sim.AdjustRep(enc.ShipObj.Faction, -2)
 		enc.Resolved = false // let them pick again — still in the encounter
 		return "\"Nice try, but I wasn't born yesterday.\" The pirate isn't fooled."
 
 	case 3: // Flee
-		// Make the pirate chase harder
-		enc.ShipObj.MoveRate = max(enc.ShipObj.MoveRate-2, 3)
-		enc.ShipObj.dirTimer = 30
+		sim.startChase(enc.ShipObj, true)
 		sim.Log.Add("Fleeing! The pirate gives chase.", MsgWarning)
-		return "You break off communications and gun the engines. The pirate follows."
+		sim.AdjustRep(enc.ShipObj.Faction, -2)
+		return fmt.Sprintf("You break off communications and gun the engines. The pirate follows.\nGet %d tiles clear of it to shake it off.", sim.ChaseRange())
 
-	case 4: // Fight (disabled)
-		return "Combat systems offline."
+	case 4: // Fight
+		return sim.startCombat(enc)
 	}
 	return ""
 }
@@ -143,6 +143,7 @@ type EpisodeState struct {
 	ResultText string
 	Resolved   bool
 	MLClue     bool // true if this episode dropped an ML clue
+	MonkeyLion bool // the Monkey Lion boarding rather than a rolled episode
 }
 
 // EpisodeOption is a single choice the player can make.
@@ -158,183 +159,13 @@ type EpisodeOption struct {
 // Data tables
 // ---------------------------------------------------------------------------
 
-var missionNames = [MissionCount]string{
-	"Crash Site", "Derelict Ship", "Distress Signal",
-	"Everyone Disappeared", "Missing Scientist", "Vanished Ship",
-	"Inspection", "Pursuit", "Shore Leave",
-	"Blackhole", "Civilization", "Nebula", "Planet", "Quasar",
-	"Star", "Unexplored System", "Wormhole",
-	"Defend", "Deliver", "Medical", "Rescue", "Transport",
-}
-
-var missionCategories = [MissionCount]MissionCategory{
-	CatInvestigate, CatInvestigate, CatInvestigate,
-	CatInvestigate, CatInvestigate, CatInvestigate,
-	CatMilitary, CatMilitary, CatMilitary,
-	CatResearch, CatResearch, CatResearch, CatResearch, CatResearch,
-	CatResearch, CatResearch, CatResearch,
-	CatSupport, CatSupport, CatSupport, CatSupport, CatSupport,
-}
-
-// Mission briefing templates. Slots: {system}, {location}, {char}
-var missionBriefings = [MissionCount]string{
-	// Investigate
-	"Sensors detect a crash site near {location} in the {system} system. Wreckage is scattered across the surface. {char}",
-	"A derelict vessel drifts near {location} in the {system} system. No life signs detected. Hull breached in multiple places. {char}",
-	"A weak distress signal broadcasts from {location} in the {system} system. The transmission is fragmented but urgent. {char}",
-	"All contact with {location} in the {system} system has been lost. The settlement went silent three days ago. No signals, no beacons. {char}",
-	"A research team at {location} reports their lead scientist has vanished. Equipment is still running but the lab is empty. {char}",
-	"A registered vessel was last tracked near {location} in the {system} system. It never arrived at its destination. {char}",
-	// Military
-	"A patrol authority near {location} in the {system} system demands you submit to inspection. They claim jurisdiction over this sector. {char}",
-	"An alert broadcasts from {location} in the {system} system — a fugitive vessel was spotted in the area. Authorities request pursuit assistance. {char}",
-	"You pick up a shore leave beacon from {location} in the {system} system. It's a designated rest stop for passing ships. {char}",
-	// Research
-	"A stable blackhole has been detected near {location} in the {system} system. Its accretion disk pulses with unusual energy patterns. {char}",
-	"Sensors reveal a pre-warp civilization on a planet near {location} in the {system} system. They appear unaware of spacefaring species. {char}",
-	"A dense nebula near {location} in the {system} system is emitting anomalous radiation patterns. Standard sensors can barely penetrate it. {char}",
-	"An uncharted planet near {location} in the {system} system shows unusual geological activity. The surface readings don't match any known world type. {char}",
-	"A quasar-like energy source has appeared near {location} in the {system} system. It shouldn't be possible at this scale. {char}",
-	"The star in the {system} system is behaving erratically near {location}. Solar output is fluctuating wildly outside predicted models. {char}",
-	"The region around {location} in the {system} system has never been charted. Long-range scans show... something. {char}",
-	"A spatial anomaly near {location} in the {system} system reads as a stable wormhole. The other end is unknown. {char}",
-	// Support
-	"A settlement at {location} in the {system} system is under attack. They're broadcasting on all frequencies for help. {char}",
-	"A supply request originates from {location} in the {system} system. They need essential cargo delivered urgently. {char}",
-	"A medical emergency has been declared at {location} in the {system} system. A pathogen is spreading through the population. {char}",
-	"A distress call from {location} in the {system} system reports people trapped in a structural collapse. Time is running out. {char}",
-	"Refugees near {location} in the {system} system need transport to safety. Their ship's engines are failing. {char}",
-}
-
-var twistNames = [TwistCount]string{
-	"Assassination Attempt", "Crew Infected", "Equipment Malfunction",
-	"Marooned", "Officer Goes Insane", "Series of Murders",
-	"Ship Captured", "Ship Damaged", "Surprise Attack",
-	"Taken Prisoner", "Thoughts Manifested", "Time Travel",
-	"Unjustly Court Martialed",
-}
-
-// Twist foreshadowing — subtle hint in the briefing.
-var twistHints = [TwistCount]string{
-	"You notice suspicious movement on sensors. Someone is watching.",
-	"Bioscans detect unusual pathogen signatures in the area.",
-	"Warning: shuttle systems showing intermittent faults.",
-	"Navigation confirms you're far from any safe harbor.",
-	"Comms chatter sounds... erratic. Something's off with someone out there.",
-	"Scanners detect anomalous bio-signatures. Multiple readings, all fading.",
-	"Sensors pick up vessels maneuvering into position around you.",
-	"Your hull integrity sensors are picking up micro-fracture warnings.",
-	"Your sensors flicker. Something feels wrong.",
-	"A tractor beam signature registers briefly, then disappears.",
-	"Reality feels... thin here. Instruments confirm spatial instability.",
-	"Chronometric readings are... inconsistent. Time is behaving strangely.",
-	"A judicial transmission is broadcasting on official frequencies.",
-}
-
-// Twist reveals — what actually happens (appended to result text).
-var twistReveals = [TwistCount]string{
-	"An assassin strikes! A hidden weapon fires at your shuttle. You take evasive action.",
-	"Warning — pathogen detected aboard! Your systems are contaminated.",
-	"Critical malfunction! Your recycler overloads, venting matter into space.",
-	"Your nav systems lock up. You're stranded until you can reroute power.",
-	"The officer aboard the nearby vessel has gone completely unhinged, raving about voices.",
-	"You discover evidence of multiple deaths. This wasn't an accident.",
-	"Energy dampeners activate — your shuttle is caught in a tractor web!",
-	"An impact rocks the shuttle. Hull breach warning!",
-	"Ambush! Vessels decloak and open fire! You take evasive action.",
-	"Force fields snap on around you. You've been captured!",
-	"Your thoughts become real. The shuttle fills with... was that always there?",
-	"Space warps around you. When it clears, the stars have shifted. Time has jumped.",
-	"You're being charged with crimes you didn't commit. A tribunal convenes.",
-}
-
-var locationNames = [LocCount]string{
-	"an alternate dimension rift", "a civilian colony", "a paradise garden world",
-	"a false utopia", "a military outpost", "a mining base",
-	"a pleasure district station", "a prehistoric planet", "a prison facility",
-	"a research base", "Starknight Command",
-}
-
-var characterTypeNames = [CharCount]string{
-	"Alien Ambassador", "Ambitious Officer", "Brainwashed Colonists",
-	"Creepy Children", "Deranged Scientist", "Eccentric Trader",
-	"Evil Twin", "Genetic Superhuman", "Giant Cube",
-	"Historical Figure", "Honorable Enemy Captain", "Hotshot Pilot",
-	"Lonely Godling", "Love Interest", "Massive Single Celled Organisms",
-	"Molten Stone Creature", "Old Rival", "Powerful Psychic",
-	"Primitive Monster", "Reclusive Dictator", "Robot Overlord",
-	"Rogue Satellite", "Secret Weapon", "Sentient Cloud",
-	"Shady Diplomat", "Shakespearean Acting Troupe", "Space Hippies",
-	"Supercomputer", "War Criminal",
-}
-
-// Character introductions — {name} slot for proper name.
-var characterIntros = [CharCount]string{
-	"{name}, an alien ambassador, hails your shuttle with formal greetings.",
-	"An ambitious young officer named {name} demands your attention on comms.",
-	"The colonists at the settlement stare blankly. They speak in unison.",
-	"Children peer at you through the viewport. Their eyes are... wrong.",
-	"{name}, a wild-eyed researcher, hails your shuttle frantically.",
-	"{name}, an eccentric trader, broadcasts a deal too good to be true.",
-	"Someone who looks exactly like you appears on the viewscreen. They smile.",
-	"{name}, a genetically enhanced being, radiates an unsettling calm.",
-	"A massive metallic cube orbits silently nearby. It does not respond to hails.",
-	"Historical records identify {name} — but that's impossible. They died centuries ago.",
-	"{name}, an enemy captain of considerable reputation, hails with unexpected courtesy.",
-	"A hotshot pilot called {name} buzzes your shuttle, showing off.",
-	"An entity calling itself {name} claims to be a god. A lonely one.",
-	"{name} appears on your viewscreen. Something about them is... magnetic.",
-	"Sensors show a single organism. It's the size of a moon. It's alive.",
-	"A creature of molten stone lumbers across the surface, radiating incredible heat.",
-	"You recognize {name} on the comm channel. An old rival. This won't be simple.",
-	"{name}, a powerful psychic, contacts you telepathically before you even hail.",
-	"Something massive and primitive roars on the surface. It's territorial.",
-	"{name}, a reclusive dictator, grants you a rare audience via encrypted channel.",
-	"An artificial intelligence designated {name} controls everything here.",
-	"A rogue satellite locks onto your shuttle and begins transmitting data.",
-	"Intelligence reports reference a secret weapon hidden in this system.",
-	"A sentient cloud drifts toward you, pulsing with bioluminescent patterns.",
-	"{name}, a diplomat of questionable reputation, offers to negotiate.",
-	"A troupe of Shakespearean actors hails you, mid-performance of Hamlet.",
-	"A commune of Space Hippies broadcasts peace symbols and folk music.",
-	"A vast supercomputer called {name} speaks in perfect monotone.",
-	"{name}, a wanted war criminal, is reportedly hiding in this system.",
-}
-
-// Proper names for characters — seeded RNG picks from these per character type.
-var characterProperNamePool = [CharCount][]string{
-	{"Thex", "Zira", "Ambassador Kol", "Envoy Tal'Set", "Diplomat Vreen"},       // Alien Ambassador
-	{"Lt. Harker", "Cmdr. Voss", "Ensign Zhao", "Lt. Pryce", "Cmdr. Nash"},     // Ambitious Officer
-	{"the colonists", "the settlers", "the inhabitants", "the population"},       // Brainwashed Colonists
-	{"the children", "the young ones", "the watchers"},                           // Creepy Children
-	{"Dr. Voss", "Dr. Krell", "Professor Zahn", "Dr. Mira", "Dr. Okkonen"},     // Deranged Scientist
-	{"Korb", "Madame Luxe", "Trader Nim", "the Merchant", "Dealmaker Fenn"},     // Eccentric Trader
-	{"your double", "the impostor", "the mirror", "the other you"},               // Evil Twin
-	{"Apex", "Nova", "Subject Zero", "The Perfected", "Augment Kael"},           // Genetic Superhuman
-	{"the Cube", "Object Seven", "the Monolith", "Grid Alpha"},                  // Giant Cube
-	{"Admiral Chen", "Captain Kirk", "General Voss", "Commander Shran"},          // Historical Figure
-	{"Captain D'vak", "Commander Torek", "Captain Sela", "Captain Krenn"},       // Honorable Enemy Captain
-	{"Ace", "Maverick", "Flash", "Daredevil", "Stardust"},                       // Hotshot Pilot
-	{"Eternus", "The Solitary", "Monad", "The Forsaken"},                        // Lonely Godling
-	{"Alex", "Morgan", "Casey", "Jordan", "Quinn"},                              // Love Interest
-	{"the Organism", "Macro-Entity", "the Living Moon", "Bio-Mass Alpha"},       // Massive Single Celled Organisms
-	{"the Golem", "Ignis", "the Colossus", "Pyrax"},                             // Molten Stone Creature
-	{"Rennick", "Castillo", "your old nemesis", "Torres", "Blake"},              // Old Rival
-	{"Sylar", "Mindkeeper", "The Oracle", "Psion", "Thought-weaver"},            // Powerful Psychic
-	{"the Beast", "the Leviathan", "the Horror", "the Predator"},                // Primitive Monster
-	{"Dictator Vorn", "Supreme Leader Kael", "Tyrant Drexx", "the Overlord"},    // Reclusive Dictator
-	{"NEXUS-9", "Sovereign", "the Machine", "AXIOM", "Unit Prime"},              // Robot Overlord
-	{"SAT-7", "Orbital-X", "the Probe", "Deep Eye"},                            // Rogue Satellite
-	{"Project Omega", "the Device", "Codename Fist", "the Prototype"},           // Secret Weapon
-	{"the Cloud", "Nimbus", "the Mist", "Vapor", "the Drift"},                  // Sentient Cloud
-	{"Ambassador Krel", "Envoy Shade", "Diplomat Vex", "Consul Nix"},            // Shady Diplomat
-	{"the Players", "the Troupe", "the Company", "the Thespians"},               // Shakespearean Acting Troupe
-	{"the Collective", "the Commune", "Star Children", "the Free Folk"},          // Space Hippies
-	{"ORACLE", "CORE", "ATLAS", "MINERVA", "LOGOS"},                             // Supercomputer
-	{"Dax Vrenn", "General Thule", "the Butcher", "Colonel Hask", "Krell"},      // War Criminal
-}
+// Mission, twist, location and character tables live in the content packs
+// (see content.go); MissionType, TwistType, LocationType and CharacterType
+// index into them. The constants above name the base pack's entries.
 
-// ML clue texts — dropped ~10% for eligible characters.
+// ML clue texts — dropped 1 in 10 by eligible characters, or when hunting the
+// Monkey Lion 5 in 10 by eligible characters and 2 in 10 by anyone else; see
+// mlClueChance.
 var mlClueTexts = []string{
 	"In the data you recovered, there's a fragment: '...the Monkey Lion\nawaits beyond the veil of stars...'",
 	"Among the records, star charts reference a massive vessel\ndesignation ML-7 — last seen jumping to unknown coordinates.",
@@ -343,15 +174,6 @@ var mlClueTexts = []string{
 	"Encrypted coordinates recovered. Cross-referencing against\nknown SH Drive signatures... partial match detected.",
 }
 
-// Characters eligible for ML clue drops.
-var mlClueEligible = map[CharacterType]bool{
-	CharEccentricTrader:  true,
-	CharHistoricalFigure: true,
-	CharDerangedScientist: true,
-	CharSentientCloud:    true,
-	CharSupercomputer:    true,
-}
-
 // ---------------------------------------------------------------------------
 // Options per mission category
 // ---------------------------------------------------------------------------
@@ -406,30 +228,31 @@ func RollEpisode(sectorSeed int64, sysIdx int, systemName string, skills *Player
 	}
 
 	// Roll the 4 tables
-	mission := MissionType(rng.IntN(int(MissionCount)))
-	twist := TwistType(rng.IntN(int(TwistCount)))
-	location := LocationType(rng.IntN(int(LocCount)))
-	character := CharacterType(rng.IntN(int(CharCount)))
+	mission := MissionType(rng.IntN(len(content.Missions)))
+	twist := TwistType(rng.IntN(len(content.Twists)))
+	location := LocationType(rng.IntN(len(content.Locations)))
+	character := CharacterType(rng.IntN(len(content.Characters)))
 
 	// Pick a proper name for the character
-	pool := characterProperNamePool[character]
+	pool := content.Characters[character].Names
 	charName := pool[rng.IntN(len(pool))]
 
 	// Assemble title
-	title := strings.ToUpper(missionNames[mission]) + " at " + strings.ToUpper(locationNames[location])
+	locationName := content.Locations[location]
+	title := strings.ToUpper(content.Missions[mission].Name) + " at " + strings.ToUpper(locationName)
 
 	// Assemble briefing
-	cat := missionCategories[mission]
-	briefingTemplate := missionBriefings[mission]
+	cat := content.Missions[mission].category
+	briefingTemplate := content.Missions[mission].Briefing
 	briefing := briefingTemplate
 	briefing = strings.ReplaceAll(briefing, "{system}", systemName)
-	briefing = strings.ReplaceAll(briefing, "{location}", locationNames[location])
+	briefing = strings.ReplaceAll(briefing, "{location}", locationName)
 	briefing = strings.ReplaceAll(briefing, "{char}", "")
 
-	charIntro := characterIntros[character]
+	charIntro := content.Characters[character].Intro
 	charIntro = strings.ReplaceAll(charIntro, "{name}", charName)
 
-	twistHint := twistHints[twist]
+	twistHint := content.Twists[twist].Hint
 
 	fullBriefing := strings.TrimSpace(briefing) + "\n\n" + charIntro + "\n\n" + twistHint
 
@@ -480,7 +303,7 @@ func ResolveEpisode(sim *Sim, ep *EpisodeState, optionIdx int) string {
 
 	ep.Resolved = true
 
-	cat := missionCategories[ep.Mission]
+	cat := content.Missions[ep.Mission].category
 	opts := categoryOptionSets[cat]
 
 	// Award skill XP
@@ -499,7 +322,7 @@ func ResolveEpisode(sim *Sim, ep *EpisodeState, optionIdx int) string {
 	seed := sim.Sector.Seed*3000 + int64(ep.Mission)*17 + int64(ep.Twist)*31
 	rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed>>16|11)))
 
-	charPool := characterProperNamePool[ep.Character]
+	charPool := content.Characters[ep.Character].Names
 	charName := charPool[rng.IntN(len(charPool))]
 
 	var baseText string
@@ -530,12 +353,8 @@ func ResolveEpisode(sim *Sim, ep *EpisodeState, optionIdx int) string {
 			credits = 25 + rng.IntN(15)
 			energyCost = 5
 			// Share supplies
-			if sim.Resources.Water.Clean >= 10 {
-				sim.Resources.Water.Clean -= 10
-			}
-			if sim.Resources.Organic.Clean >= 10 {
-				sim.Resources.Organic.Clean -= 10
-			}
+			sim.Resources.Matter.Draw(-1, MatterWater, 10)
+			sim.Resources.Matter.Draw(-1, MatterOrganic, 10)
 		}
 
 	case 1: // Moderate option
@@ -573,6 +392,15 @@ func ResolveEpisode(sim *Sim, ep *EpisodeState, optionIdx int) string {
 		}
 	}
 
+	// --- Faction standing: the patrol remembers who answers its calls,
+	// the guilds remember who helps their people ---
+	switch cat {
+	case CatMilitary:
+		sim.AdjustRep(sim.authority(), [3]int{5, 2, -3}[optionIdx])
+	case CatSupport:
+		sim.AdjustRep(sim.localGuild(), [3]int{4, 2, 0}[optionIdx])
+	}
+
 	// --- Twist modifier ---
 	twistApplied := false
 	switch ep.Twist {
@@ -592,8 +420,7 @@ func ResolveEpisode(sim *Sim, ep *EpisodeState, optionIdx int) string {
 		sim.Skills.AddXP(SkillEngineering, 3)
 	case TwistCrewInfected:
 		if optionIdx == 0 {
-			if sim.Resources.Organic.Clean >= 5 {
-				sim.Resources.Organic.Clean -= 5
+			if sim.Resources.Matter.Draw(-1, MatterOrganic, 5) {
 				twistText = "Pathogen contamination! Organic matter corrupted. -5 organic."
 			} else {
 				twistText = "Pathogen detected but containment holds. Close call."
@@ -660,18 +487,18 @@ func ResolveEpisode(sim *Sim, ep *EpisodeState, optionIdx int) string {
 			}
 			twistText = "You're charged with violating sector regulations.\nLegal fees eat into your earnings. -20cr."
 			twistApplied = true
+			sim.AdjustRep(sim.authority(), -5)
 			sim.Skills.AddXP(SkillDiplomacy, 3)
 		}
+	default:
+		// Twists added by content packs are flavour only
+		twistText = content.Twists[ep.Twist].Reveal
+		twistApplied = true
 	}
 
 	// Apply mechanical effects
 	sim.Resources.Credits += credits
-	if hullDmg > 0 {
-		sim.Resources.Hull -= hullDmg
-		if sim.Resources.Hull < 0 {
-			sim.Resources.Hull = 0
-		}
-	}
+	sim.damageHull(hullDmg, rng)
 	if energyCost > 0 {
 		sim.Resources.Energy -= energyCost
 		if sim.Resources.Energy < 0 {
@@ -696,12 +523,14 @@ func ResolveEpisode(sim *Sim, ep *EpisodeState, optionIdx int) string {
 		sim.Log.Add(fmt.Sprintf("Earned %dcr. Credits: %d.", credits, sim.Resources.Credits), MsgDiscovery)
 	}
 
-	// ML clue check (~10% for eligible characters)
+	// ML clue check (see mlClueChance)
 	mlClue := ""
-	if mlClueEligible[ep.Character] && rng.IntN(10) == 0 {
+	if rng.IntN(10) < sim.mlClueChance(content.Characters[ep.Character].MLClue) {
 		ep.MLClue = true
-		mlClue = "\n\n" + mlClueTexts[rng.IntN(len(mlClueTexts))]
-		sim.Log.Add("USS Monkey Lion clue discovered!", MsgDiscovery)
+		sim.Discovery.MLCluesFound++
+		news := sim.mlTrailNews()
+		mlClue = "\n\n" + mlClueTexts[rng.IntN(len(mlClueTexts))] + "\n\n" + news
+		sim.Log.Add("USS Monkey Lion clue discovered! "+news, MsgDiscovery)
 	}
 
 	// Assemble final result
@@ -715,9 +544,6 @@ func ResolveEpisode(sim *Sim, ep *EpisodeState, optionIdx int) string {
 
 	// Discovery tracking
 	sim.Discovery.EpisodesCompleted++
-	if ep.MLClue {
-		sim.Discovery.MLCluesFound++
-	}
 
 	return result
 }
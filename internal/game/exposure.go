@@ -0,0 +1,289 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spacehole-rogue/spacehole_rogue/internal/world"
+)
+
+// Out on a surface the suit stands between the player and the planet, and
+// the scan says what it is up against. A toxic or missing atmosphere drains
+// the suit's O2, extreme cold or heat works through it into the player's
+// body temperature, radiation belts dose them, and lava and crevasses can
+// be crossed in hazard gear at the price of a burn. Gear bought at stations
+// is kept in the cargo bay and drawn on as the suit needs it, and Survival's
+// environmental perks slow the cold, the heat, the breathing and the dose.
+
+// Exposure is what a surface does to the player through their suit.
+type Exposure uint8
+
+const (
+	ExposeNone      Exposure = iota
+	ExposeToxic              // the suit breathes from its own O2
+	ExposeCold               // body temperature falls
+	ExposeHeat               // body temperature rises
+	ExposeRadiation          // radiation dose builds
+)
+
+// hazardExposure is what each scan hazard does out on the surface.
+var hazardExposure = map[string]Exposure{
+	"No breathable atmosphere":      ExposeToxic,
+	"Toxic gas emissions":           ExposeToxic,
+	"Crushing atmospheric pressure": ExposeToxic,
+	"Extreme cold (-220C surface)":  ExposeCold,
+	"Surface temperature 800C+":     ExposeHeat,
+	"Intense radiation belts":       ExposeRadiation,
+}
+
+const (
+	suitO2Max         = 100
+	suitO2Interval    = 120 // ticks per point of suit O2 breathed: 200 sec on a full suit
+	suffocateInterval = 60  // 1 health a second with the suit's air gone
+	tempInterval      = 180 // body temperature moves a point every 3 sec outside...
+	tempRecovery      = 60  // ...and back a point a second anywhere else
+	tempWarn          = 50  // shivering or sweating
+	tempHarm          = 80  // 1 health every tempInterval from here on
+	doseInterval      = 240 // 1 point of radiation every 4 sec outside
+	hazardGearBurn    = 6   // burn crossing lava or a crevasse in hazard gear, less Survival level
+
+	resilienceLevel = 5 // Survival perk "Environmental resilience"
+	toleranceLevel  = 7 // Survival perk "Extreme environment tolerance"
+)
+
+// Exposure returns what the surface does to the player.
+func (sm *SurfaceMap) Exposure() Exposure {
+	return hazardExposure[sm.Hazard]
+}
+
+// ExposureName describes an exposure for the HUD.
+func ExposureName(e Exposure) string {
+	switch e {
+	case ExposeToxic:
+		return "Unbreathable air"
+	case ExposeCold:
+		return "Extreme cold"
+	case ExposeHeat:
+		return "Extreme heat"
+	case ExposeRadiation:
+		return "Radiation"
+	default:
+		return "None"
+	}
+}
+
+// hasGear returns true if a piece of suit gear is in the cargo bay.
+func (s *Sim) hasGear(k CargoKind) bool {
+	return s.Resources.CargoOf(k) > 0
+}
+
+// exposureSlowdown returns how many times slower exposure works through
+// the suit: twice with the right gear, and once more for each environmental
+// Survival perk.
+func (s *Sim) exposureSlowdown(gear CargoKind) uint64 {
+	n := uint64(1)
+	if gear != CargoNone && s.hasGear(gear) {
+		n *= 2
+	}
+	lvl := s.SkillCheck(SkillSurvival)
+	if lvl >= resilienceLevel {
+		n++
+	}
+	if lvl >= toleranceLevel {
+		n++
+	}
+	return n
+}
+
+// exposed returns what the player is exposed to right now: ExposeNone
+// aboard or off the surface.
+func (s *Sim) exposed() Exposure {
+	if s.ActiveSurface == nil || !s.Outside {
+		return ExposeNone
+	}
+	return s.ActiveSurface.Exposure()
+}
+
+// tickExposure lets the surface work on the player through their suit.
+func (s *Sim) tickExposure() {
+	e := s.exposed()
+	s.tickSuitO2(e)
+	s.tickBodyTemp(e)
+	if e == ExposeRadiation && s.Ticks%(doseInterval*s.exposureSlowdown(CargoRadShielding)) == 0 {
+		s.doseRadiation()
+	}
+}
+
+// tickSuitO2 breathes the suit's air down on toxic worlds and swaps in an
+// O2 canister from the cargo bay when it runs dry.
+func (s *Sim) tickSuitO2(e Exposure) {
+	r := &s.Resources
+	if e != ExposeToxic {
+		return
+	}
+	if r.SuitO2 > 0 && s.Ticks%(suitO2Interval*s.exposureSlowdown(CargoNone)) == 0 {
+		r.SuitO2--
+		switch r.SuitO2 {
+		case 25:
+			s.Log.Add("Suit O2 at 25%.", MsgWarning)
+		case 0:
+			if r.RemoveCargo(CargoO2Canisters, 1) > 0 {
+				r.SuitO2 = suitO2Max
+				s.Log.Add(fmt.Sprintf("Suit O2 empty. Switched to a fresh canister (%d left).", r.CargoOf(CargoO2Canisters)), MsgWarning)
+			} else {
+				s.Log.Add("SUIT O2 EMPTY. Get back to the shuttle!", MsgCritical)
+			}
+		}
+	}
+	if r.SuitO2 == 0 && s.Ticks%suffocateInterval == 0 {
+		s.Needs.Health = max(s.Needs.Health-1, 0)
+	}
+}
+
+// tickBodyTemp moves the player's body temperature with the surface's cold
+// or heat, and back toward comfortable anywhere else.
+func (s *Sim) tickBodyTemp(e Exposure) {
+	n := &s.Needs
+	switch {
+	case e == ExposeCold || e == ExposeHeat:
+		if s.Ticks%(tempInterval*s.exposureSlowdown(CargoThermalLiners)) == 0 {
+			before := n.Temp
+			if e == ExposeCold {
+				n.Temp = max(n.Temp-1, -100)
+			} else {
+				n.Temp = min(n.Temp+1, 100)
+			}
+			if abs(before) < tempWarn && abs(n.Temp) >= tempWarn {
+				s.Log.Add(tempWarning(n.Temp), MsgWarning)
+			}
+		}
+	case n.Temp != 0 && s.Ticks%tempRecovery == 0:
+		if n.Temp > 0 {
+			n.Temp--
+		} else {
+			n.Temp++
+		}
+	}
+	if abs(n.Temp) >= tempHarm && s.Ticks%tempInterval == 0 {
+		n.Health = max(n.Health-1, 0)
+	}
+}
+
+// tempWarning describes a body temperature that has become a problem.
+func tempWarning(temp int) string {
+	if temp < 0 {
+		return "You're shivering. The cold is getting through your suit."
+	}
+	return "You're sweating buckets. The heat is getting through your suit."
+}
+
+// doseRadiation adds a point of radiation for time spent in radiation belts.
+func (s *Sim) doseRadiation() {
+	sev := &s.Injuries[InjuryRadiation]
+	*sev = min(*sev+1, 100)
+	if *sev == InjurySerious {
+		s.Log.Add(fmt.Sprintf("Radiation dose serious (%d). Get to the med bay.", *sev), MsgCritical)
+	}
+}
+
+// ExposureHazard returns what exposure is doing to the player ("suffocation",
+// "hypothermia", "heatstroke"), or "" if their suit is holding up.
+func (s *Sim) ExposureHazard() string {
+	switch {
+	case s.Resources.SuitO2 == 0 && s.exposed() == ExposeToxic:
+		return hazardO2
+	case s.Needs.Temp <= -tempHarm:
+		return hazardCold
+	case s.Needs.Temp >= tempHarm:
+		return hazardHeat
+	}
+	return ""
+}
+
+// rechargeSuit tops the suit's O2 up from the shuttle's air on boarding.
+func (s *Sim) rechargeSuit() {
+	r := &s.Resources
+	if r.SuitO2 < suitO2Max {
+		r.SuitO2 = suitO2Max
+		s.Log.Add("Suit O2 recharged.", MsgInfo)
+	}
+}
+
+// reportExposure tells the player what their suit is up against as they
+// step out, and which of their gear will help.
+func (s *Sim) reportExposure() {
+	surf := s.ActiveSurface
+	e := surf.Exposure()
+	switch e {
+	case ExposeToxic:
+		s.Log.Add(fmt.Sprintf("Suit sensors: unbreathable air. Suit O2 %d%%, %d spare canisters.", s.Resources.SuitO2, s.Resources.CargoOf(CargoO2Canisters)), MsgWarning)
+	case ExposeCold, ExposeHeat:
+		msg := fmt.Sprintf("Suit sensors: %s.", strings.ToLower(ExposureName(e)))
+		if s.hasGear(CargoThermalLiners) {
+			msg += " Thermal liners fitted."
+		}
+		s.Log.Add(msg, MsgWarning)
+	case ExposeRadiation:
+		msg := "Suit sensors: heavy radiation."
+		if s.hasGear(CargoRadShielding) {
+			msg += " Rad shielding fitted."
+		}
+		s.Log.Add(msg, MsgWarning)
+	}
+	if s.hasGear(CargoHazardGear) {
+		s.Log.Add(fmt.Sprintf("Hazard gear on. You can cross the %s, carefully.", hazardTileName(surf.TerrainType)), MsgInfo)
+	}
+}
+
+// exposureGear is the gear that keeps each exposure at bay.
+var exposureGear = [...]CargoKind{
+	ExposeToxic:     CargoO2Canisters,
+	ExposeCold:      CargoThermalLiners,
+	ExposeHeat:      CargoThermalLiners,
+	ExposeRadiation: CargoRadShielding,
+}
+
+// adviseGear tells the player on landing, from a scan of the planet, whether
+// they brought the gear its surface calls for.
+func (s *Sim) adviseGear() {
+	e := s.ActiveSurface.Exposure()
+	if e == ExposeNone {
+		return
+	}
+	gear := exposureGear[e]
+	if s.hasGear(gear) {
+		s.Log.Add(fmt.Sprintf("Scan data: %s. %s aboard.", strings.ToLower(ExposureName(e)), CargoName(gear)), MsgInfo)
+	} else {
+		s.Log.Add(fmt.Sprintf("Scan data: %s, and no %s aboard. Keep it short out there.", strings.ToLower(ExposureName(e)), strings.ToLower(CargoName(gear))), MsgWarning)
+	}
+}
+
+// hazardTileName names a surface's hazard tiles.
+func hazardTileName(terrain world.TerrainType) string {
+	switch terrain {
+	case world.TerrainIce:
+		return "crevasses"
+	case world.TerrainVolcanic:
+		return "lava"
+	default:
+		return "vents"
+	}
+}
+
+// crossHazard burns the player for stepping over hazardous terrain in
+// hazard gear.
+func (s *Sim) crossHazard(terrain world.TerrainType) {
+	var msg string
+	switch terrain {
+	case world.TerrainIce:
+		msg = "You pick your way across the crevasse. The cold bites through your gloves."
+	case world.TerrainVolcanic:
+		msg = "Your heat boots carry you over the lava. Your legs still blister."
+	default:
+		msg = "You dash through the vent. It scalds you anyway."
+	}
+	s.injure(InjuryBurn, hazardGearBurn-s.SkillCheck(SkillSurvival), msg)
+	if s.Skills.AddXP(SkillSurvival, 1.0) {
+		LogLevelUp(s.Log, SkillSurvival, s.Skills.Level(SkillSurvival))
+	}
+}
@@ -0,0 +1,270 @@
+package game
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// FactionKind is the role a faction plays in the sector.
+type FactionKind uint8
+
+const (
+	FactionAuthority FactionKind = iota // runs the patrols and most stations
+	FactionPirates                      // pirate clan
+	FactionTraders                      // trader guild
+)
+
+// Faction is a power in the sector. Factions are generated from the sector
+// seed; ships and stations refer to them by index into Sector.Factions.
+// Names are drawn from shared pools, and a name means the same faction in
+// every sector it appears in.
+type Faction struct {
+	Name    string
+	Kind    FactionKind
+	Tagline string
+}
+
+// Sector faction layout: the authority first, then the clans, then the guilds.
+const (
+	numPirateClans  = 2
+	numTraderGuilds = 2
+)
+
+// Faction name pools.
+var authorityNames = []string{"Space Knights", "Rim Patrol Command", "Frontier Marshals"}
+
+var pirateClanNames = []string{
+	"Red Maw Clan", "Void Jackals", "Brotherhood of the Black Sun", "Rust Widows", "Skullcrack Syndicate",
+}
+
+var traderGuildNames = []string{
+	"Free Haulers' Guild", "Meridian Mercantile", "Copperline Combine", "Wayfarer Consortium", "Salt & Ember Trading Co.",
+}
+
+var factionTaglines = map[FactionKind][]string{
+	FactionAuthority: {
+		"See the galaxy. Die heroically. Pension not included.",
+		"Order in the void, one patrol at a time.",
+	},
+	FactionPirates: {
+		"Everything floats free eventually.",
+		"Your cargo. Our problem now.",
+	},
+	FactionTraders: {
+		"If it fits in a hold, we'll move it.",
+		"Honest prices. Mostly.",
+	},
+}
+
+// GenerateFactions creates the sector's factions from its seed.
+func GenerateFactions(seed int64) []Faction {
+	rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed>>12|13)))
+	factions := []Faction{newFaction(FactionAuthority, authorityNames[rng.IntN(len(authorityNames))], rng)}
+	for _, i := range rng.Perm(len(pirateClanNames))[:numPirateClans] {
+		factions = append(factions, newFaction(FactionPirates, pirateClanNames[i], rng))
+	}
+	for _, i := range rng.Perm(len(traderGuildNames))[:numTraderGuilds] {
+		factions = append(factions, newFaction(FactionTraders, traderGuildNames[i], rng))
+	}
+	return factions
+}
+
+func newFaction(kind FactionKind, name string, rng *rand.Rand) Faction {
+	taglines := factionTaglines[kind]
+	return Faction{Name: name, Kind: kind, Tagline: taglines[rng.IntN(len(taglines))]}
+}
+
+// FactionsOfKind returns the indices of all factions of a kind.
+func FactionsOfKind(factions []Faction, kind FactionKind) []int {
+	var ids []int
+	for i, f := range factions {
+		if f.Kind == kind {
+			ids = append(ids, i)
+		}
+	}
+	return ids
+}
+
+// pickFaction returns a random faction of a kind, or 0 (the authority) if
+// there is none.
+func pickFaction(factions []Faction, kind FactionKind, rng *rand.Rand) int {
+	ids := FactionsOfKind(factions, kind)
+	if len(ids) == 0 {
+		return 0
+	}
+	return ids[rng.IntN(len(ids))]
+}
+
+// FactionName returns a faction's name, or "Independent" for an unknown index.
+func (s *Sector) FactionName(id int) string {
+	if id < 0 || id >= len(s.Factions) {
+		return "Independent"
+	}
+	return s.Factions[id].Name
+}
+
+// FactionKindName returns a display label for a faction kind.
+func FactionKindName(kind FactionKind) string {
+	switch kind {
+	case FactionAuthority:
+		return "Patrol Authority"
+	case FactionPirates:
+		return "Pirate Clan"
+	case FactionTraders:
+		return "Trader Guild"
+	default:
+		return "Unknown"
+	}
+}
+
+// --- Reputation ---
+
+// Reputation runs from -100 (kill on sight) to +100 (family).
+const (
+	repMin = -100
+	repMax = 100
+
+	RepHostile    = -50
+	RepUnfriendly = -15
+	RepFriendly   = 15
+	RepAllied     = 50
+)
+
+// ReputationTier returns a label for a reputation score.
+func ReputationTier(rep int) string {
+	switch {
+	case rep <= RepHostile:
+		return "Hostile"
+	case rep <= RepUnfriendly:
+		return "Unfriendly"
+	case rep < RepFriendly:
+		return "Neutral"
+	case rep < RepAllied:
+		return "Friendly"
+	default:
+		return "Allied"
+	}
+}
+
+// Rep returns the player's reputation with a faction of the current sector.
+func (s *Sim) Rep(faction int) int {
+	if faction < 0 || faction >= len(s.Sector.Factions) {
+		return 0
+	}
+	return s.Reputation[s.Sector.Factions[faction].Name]
+}
+
+// AdjustRep changes the player's reputation with a faction and logs it.
+// At Diplomacy 5 ("Faction reputation bonus") gains are half again as big.
+func (s *Sim) AdjustRep(faction, delta int) {
+	if faction < 0 || faction >= len(s.Sector.Factions) {
+		return
+	}
+	s.adjustRepNamed(s.Sector.Factions[faction].Name, delta)
+}
+
+// adjustRepNamed changes the player's reputation with a faction by name,
+// for factions of sectors other than the current one.
+func (s *Sim) adjustRepNamed(name string, delta int) {
+	if delta == 0 {
+		return
+	}
+	if s.Reputation == nil {
+		s.Reputation = make(map[string]int)
+	}
+	if delta > 0 && s.Skills.Level(SkillDiplomacy) >= 5 {
+		delta += (delta + 1) / 2
+	}
+	before := s.Reputation[name]
+	after := min(max(before+delta, repMin), repMax)
+	s.Reputation[name] = after
+	if after == before {
+		return
+	}
+
+	s.Log.Add(fmt.Sprintf("%s: reputation %+d (%s).", name, after-before, ReputationTier(after)), MsgSocial)
+	if ReputationTier(before) != ReputationTier(after) {
+		s.Log.Add(fmt.Sprintf("The %s now consider you %s.", name, ReputationTier(after)), MsgDiscovery)
+	}
+}
+
+// authority returns the index of the sector's patrol authority.
+func (s *Sim) authority() int {
+	if ids := FactionsOfKind(s.Sector.Factions, FactionAuthority); len(ids) > 0 {
+		return ids[0]
+	}
+	return -1
+}
+
+// localGuild returns the trader guild with the most presence in the current
+// system: the station's guild if it has one, otherwise the first guild.
+func (s *Sim) localGuild() int {
+	sm := s.Sector.CurrentSystemMap()
+	if st := sm.FindStation(); st != nil && st.Faction < len(s.Sector.Factions) &&
+		s.Sector.Factions[st.Faction].Kind == FactionTraders {
+		return st.Faction
+	}
+	if ids := FactionsOfKind(s.Sector.Factions, FactionTraders); len(ids) > 0 {
+		return ids[0]
+	}
+	return -1
+}
+
+// --- Reputation effects ---
+
+// Station price modifiers per point of reputation: friends pay up to 20% less
+// and get up to 10% more for their cargo, enemies the reverse.
+const (
+	repBuyDiscount = 500.0
+	repSellBonus   = 1000.0
+)
+
+// StationPrice returns what the docked station charges the player for cargo.
+func (s *Sim) StationPrice(sd *StationData, kind CargoKind) int {
+	price := float64(sd.SellPrices[kind]) * (1 - float64(s.Rep(sd.FactionID))/repBuyDiscount)
+	return max(1, int(price+0.5))
+}
+
+// StationOffer returns what the docked station pays the player for cargo.
+func (s *Sim) StationOffer(sd *StationData, kind CargoKind) int {
+	price := float64(sd.BuyPrices[kind]) * (1 + float64(s.Rep(sd.FactionID))/repSellBonus)
+	return max(1, int(price+0.5))
+}
+
+// pirateBribe returns what a pirate clan wants to let the player go,
+// or 0 if it is too hostile to take money.
+func (s *Sim) pirateBribe(clan int) int {
+	rep := s.Rep(clan)
+	if rep <= RepHostile {
+		return 0
+	}
+	return min(max(30-rep/2, 10), 60)
+}
+
+// bribeOption builds a pirate's Bribe option at the clan's price.
+func (s *Sim) bribeOption(clan int) EncounterOption {
+	cost := s.pirateBribe(clan)
+	if cost == 0 {
+		return EncounterOption{Label: "Bribe", DisableText: "They want blood, not credits"}
+	}
+	return EncounterOption{Label: fmt.Sprintf("Bribe (%dcr)", cost), Enabled: true}
+}
+
+// hailRange returns how close (squared tiles) an NPC ship comes before
+// hailing, or 0 if it won't hail at all. Friendly clans leave you alone;
+// clans that hate you come looking.
+func (s *Sim) hailRange(ship *SpaceObject) int {
+	if ship.AIKind != AIPirate {
+		return 64 // within 8 tiles
+	}
+	switch rep := s.Rep(ship.Faction); {
+	case rep >= RepFriendly:
+		return 0
+	case rep <= RepHostile:
+		return 196 // within 14 tiles
+	case rep <= RepUnfriendly:
+		return 121 // within 11 tiles
+	default:
+		return 64
+	}
+}
@@ -0,0 +1,116 @@
+package game
+
+import "github.com/spacehole-rogue/spacehole_rogue/internal/world"
+
+// Field of view is symmetric shadowcasting: each quadrant around the viewer
+// is scanned row by row outward, and walls cast shadows that narrow the rows
+// beyond them. A tile counts as seen only if its centre is in view, so if A
+// can see B then B can see A. Slopes are kept as fractions so the edges of
+// shadows come out exact.
+
+// slope is n/d with d > 0.
+type slope struct{ n, d int }
+
+// fov is one field of view being cast.
+type fov struct {
+	grid    *world.TileGrid
+	ox, oy  int
+	radius  int
+	visible []bool
+	seen    []bool // may be nil
+}
+
+// castFOV marks every tile in line of sight of (ox, oy), out to radius, in
+// visible and seen. Neither is cleared first.
+func castFOV(grid *world.TileGrid, ox, oy, radius int, visible, seen []bool) {
+	f := fov{grid: grid, ox: ox, oy: oy, radius: radius, visible: visible, seen: seen}
+	f.reveal(ox, oy, 0, 0)
+	for q := 0; q < 4; q++ {
+		f.scan(q, 1, slope{-1, 1}, slope{1, 1})
+	}
+}
+
+// scan looks along one row of a quadrant between two slopes, then on to the
+// rows beyond whatever it saw past.
+func (f *fov) scan(q, depth int, start, end slope) {
+	if depth > f.radius {
+		return
+	}
+	prevWall, first := false, true
+	for col := roundTiesUp(depth, start); col <= roundTiesDown(depth, end); col++ {
+		x, y := f.transform(q, depth, col)
+		wall := f.grid.BlocksSight(x, y)
+		if wall || symmetric(depth, col, start, end) {
+			f.reveal(x, y, depth, col)
+		}
+		if !first && prevWall && !wall {
+			start = tileSlope(depth, col)
+		}
+		if !first && !prevWall && wall {
+			f.scan(q, depth+1, start, tileSlope(depth, col))
+		}
+		prevWall, first = wall, false
+	}
+	if !first && !prevWall {
+		f.scan(q, depth+1, start, end)
+	}
+}
+
+// transform turns a row and column in quadrant q (north, east, south,
+// west) into map coordinates.
+func (f *fov) transform(q, depth, col int) (int, int) {
+	switch q {
+	case 0:
+		return f.ox + col, f.oy - depth
+	case 1:
+		return f.ox + depth, f.oy + col
+	case 2:
+		return f.ox + col, f.oy + depth
+	default:
+		return f.ox - depth, f.oy + col
+	}
+}
+
+// reveal marks a tile seen if it is on the map and inside the circle.
+func (f *fov) reveal(x, y, depth, col int) {
+	if x < 0 || x >= f.grid.Width || y < 0 || y >= f.grid.Height {
+		return
+	}
+	if depth*depth+col*col > f.radius*f.radius+f.radius {
+		return
+	}
+	i := y*f.grid.Width + x
+	f.visible[i] = true
+	if f.seen != nil {
+		f.seen[i] = true
+	}
+}
+
+// tileSlope is the slope to the near edge of a tile.
+func tileSlope(depth, col int) slope {
+	return slope{2*col - 1, 2 * depth}
+}
+
+// symmetric returns true if the tile's centre lies between the slopes.
+func symmetric(depth, col int, start, end slope) bool {
+	return col*start.d >= depth*start.n && col*end.d <= depth*end.n
+}
+
+// roundTiesUp rounds depth*s to the nearest column, halves up.
+func roundTiesUp(depth int, s slope) int {
+	return floorDiv(2*depth*s.n+s.d, 2*s.d)
+}
+
+// roundTiesDown rounds depth*s to the nearest column, halves down.
+func roundTiesDown(depth int, s slope) int {
+	return -floorDiv(-(2*depth*s.n - s.d), 2*s.d)
+}
+
+// floorDiv divides rounding toward negative infinity, for b > 0.
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
@@ -0,0 +1,211 @@
+package game
+
+import "fmt"
+
+// SectorCoord locates a sector in the galaxy. The starting sector is {0, 0};
+// X grows east and Y grows south, like the sector map.
+type SectorCoord struct {
+	X, Y int
+}
+
+func (c SectorCoord) String() string {
+	return fmt.Sprintf("%d,%d", c.X, c.Y)
+}
+
+// Neighbor returns the adjacent sector in a gate direction.
+func (c SectorCoord) Neighbor(dir GateDir) SectorCoord {
+	switch dir {
+	case GateNorth:
+		c.Y--
+	case GateEast:
+		c.X++
+	case GateSouth:
+		c.Y++
+	case GateWest:
+		c.X--
+	}
+	return c
+}
+
+// GateDir is the sector edge a gate system leads across.
+type GateDir uint8
+
+const (
+	GateNone GateDir = iota
+	GateNorth
+	GateEast
+	GateSouth
+	GateWest
+)
+
+// gateDirs lists the gate directions in the order gates are assigned.
+var gateDirs = []GateDir{GateNorth, GateEast, GateSouth, GateWest}
+
+// Opposite returns the direction a gate jump arrives from.
+func (d GateDir) Opposite() GateDir {
+	switch d {
+	case GateNorth:
+		return GateSouth
+	case GateEast:
+		return GateWest
+	case GateSouth:
+		return GateNorth
+	case GateWest:
+		return GateEast
+	default:
+		return GateNone
+	}
+}
+
+// GateDirName returns a display label for a gate direction.
+func GateDirName(d GateDir) string {
+	switch d {
+	case GateNorth:
+		return "North"
+	case GateEast:
+		return "East"
+	case GateSouth:
+		return "South"
+	case GateWest:
+		return "West"
+	default:
+		return "None"
+	}
+}
+
+// GalaxyRadius bounds the galaxy: sectors run from -GalaxyRadius to
+// +GalaxyRadius on each axis. Edge sectors have no gates leading out.
+const GalaxyRadius = 3
+
+// InGalaxy returns true if a sector coordinate is inside the galaxy.
+func InGalaxy(c SectorCoord) bool {
+	return c.X >= -GalaxyRadius && c.X <= GalaxyRadius && c.Y >= -GalaxyRadius && c.Y <= GalaxyRadius
+}
+
+// Galaxy holds every sector generated so far. Sectors are generated the first
+// time they're needed, from a seed derived from the galaxy seed and their
+// coordinate, so the same seed always builds the same galaxy.
+type Galaxy struct {
+	Seed    int64
+	Sectors map[SectorCoord]*Sector
+	Current SectorCoord // sector the player is in
+}
+
+// NewGalaxy creates a galaxy with its starting sector generated.
+func NewGalaxy(seed int64) *Galaxy {
+	g := &Galaxy{Seed: seed, Sectors: make(map[SectorCoord]*Sector)}
+	g.EnsureSector(SectorCoord{})
+	return g
+}
+
+// SectorSeed derives a sector's seed from the galaxy seed. The starting
+// sector uses the galaxy seed itself, so it is the sector a single-sector
+// run with the same seed would have had.
+func (g *Galaxy) SectorSeed(c SectorCoord) int64 {
+	return g.Seed + int64(c.X)*7_919_001 + int64(c.Y)*104_729_003
+}
+
+// EnsureSector generates a sector if it doesn't exist yet and returns it.
+func (g *Galaxy) EnsureSector(c SectorCoord) *Sector {
+	if sec := g.Sectors[c]; sec != nil {
+		return sec
+	}
+	sec := NewSector(g.SectorSeed(c))
+	sec.Coord = c
+	if c != (SectorCoord{}) {
+		// Only the starting sector begins with a visited system
+		sec.Systems[0].Visited = false
+	}
+	sec.assignGates()
+	g.Sectors[c] = sec
+	return sec
+}
+
+// CurrentSector returns the sector the player is in.
+func (g *Galaxy) CurrentSector() *Sector {
+	return g.EnsureSector(g.Current)
+}
+
+// assignGates marks the system closest to each sector edge as the gate to
+// the neighbouring sector. Edges on the galaxy rim get no gate. Gates come
+// from star positions alone, so they can be rebuilt for any saved sector.
+func (s *Sector) assignGates() {
+	for i := range s.Systems {
+		s.Systems[i].Gate = GateNone
+	}
+	for _, dir := range gateDirs {
+		if !InGalaxy(s.Coord.Neighbor(dir)) {
+			continue
+		}
+		best, bestReach := -1, 0
+		for i, sys := range s.Systems {
+			if i == 0 || sys.Gate != GateNone {
+				continue // the central system is never a gate
+			}
+			var reach int
+			switch dir {
+			case GateNorth:
+				reach = -sys.Y
+			case GateEast:
+				reach = sys.X
+			case GateSouth:
+				reach = sys.Y
+			case GateWest:
+				reach = -sys.X
+			}
+			if best < 0 || reach > bestReach {
+				best, bestReach = i, reach
+			}
+		}
+		if best >= 0 {
+			s.Systems[best].Gate = dir
+		}
+	}
+}
+
+// GateSystem returns the index of the sector's gate in a direction, or -1.
+func (s *Sector) GateSystem(dir GateDir) int {
+	for i, sys := range s.Systems {
+		if sys.Gate == dir {
+			return i
+		}
+	}
+	return -1
+}
+
+// Discovery records identify systems galaxy-wide by SystemID. Systems in the
+// starting sector keep their index as their id; other sectors get a block of
+// ids from their coordinate.
+const sectorIDBlock = 16 // more than the most systems a sector can have
+
+// SystemID returns a galaxy-wide id for one of the sector's systems.
+func (s *Sector) SystemID(idx int) int {
+	if s.Coord == (SectorCoord{}) {
+		return idx
+	}
+	span := 2*GalaxyRadius + 2
+	x := s.Coord.X + GalaxyRadius + 1
+	y := s.Coord.Y + GalaxyRadius + 1
+	return (x*span+y)*sectorIDBlock + idx
+}
+
+// SystemIndex converts a SystemID back to an index in this sector.
+// It returns false if the id belongs to another sector.
+func (s *Sector) SystemIndex(id int) (int, bool) {
+	idx := id - s.SystemID(0)
+	if idx < 0 || idx >= len(s.Systems) {
+		return 0, false
+	}
+	return idx, true
+}
+
+// enterSector moves the player into another sector, arriving at system
+// arrival. Hails and orbits belong to the old sector and are dropped.
+func (s *Sim) enterSector(c SectorCoord, arrival int) {
+	s.Galaxy.Current = c
+	s.Sector = s.Galaxy.EnsureSector(c)
+	s.Sector.CurrentSystem = arrival
+	s.Sector.CursorSystem = arrival
+	s.PendingHail = nil
+	s.Log.Add(fmt.Sprintf("Crossed into sector %s.", c), MsgDiscovery)
+}
@@ -0,0 +1,250 @@
+package game
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// A run's goal is chosen before the prologue starts and decides what counts
+// as winning. Just Survive has no win condition: the run ends when the
+// player dies. The others end in victory once their condition is met.
+
+// Goal is what the player is playing for.
+type Goal uint8
+
+const (
+	GoalSurvive Goal = iota // the zero value, so older saves load as sandbox runs
+	GoalMonkeyLion
+	GoalHome
+	GoalPirateKing
+	GoalEmpire
+	GoalCount // sentinel
+)
+
+var goalNames = [GoalCount]string{
+	GoalSurvive:    "Just Survive",
+	GoalMonkeyLion: "Find the Monkey Lion",
+	GoalHome:       "Find Home",
+	GoalPirateKing: "Pirate King",
+	GoalEmpire:     "Build an Empire",
+}
+
+var goalDescriptions = [GoalCount]string{
+	GoalSurvive:    "No goal, pure sandbox. The journey is the point.",
+	GoalMonkeyLion: "Piece together clues to where the USS Monkey Lion went, then find it.",
+	GoalHome:       "Navigate back to your home system on the far rim of the galaxy.",
+	GoalPirateKing: "Earn the pirate clans' trust until they follow you.",
+	GoalEmpire:     "Grow rich and become the trader guilds' closest ally.",
+}
+
+// goalKeys are the goals' command-line names.
+var goalKeys = [GoalCount]string{
+	GoalSurvive:    "survive",
+	GoalMonkeyLion: "monkeylion",
+	GoalHome:       "home",
+	GoalPirateKing: "pirate",
+	GoalEmpire:     "empire",
+}
+
+var victoryTexts = [GoalCount]string{
+	GoalMonkeyLion: "You found the USS Monkey Lion.",
+	GoalHome:       "You made it home.",
+	GoalPirateKing: "The clans hail you as their Pirate King.",
+	GoalEmpire:     "Your trade empire spans the stars.",
+}
+
+// Win conditions.
+const (
+	MLCluesToReveal   = 4     // Monkey Lion clues that pin down its location
+	mlClueOdds        = 1     // in 10, for eligible characters
+	mlClueOddsGoal    = 5     // in 10, for eligible characters when hunting the Monkey Lion
+	mlClueOddsHunting = 2     // in 10, for anyone else when hunting the Monkey Lion
+	pirateKingClans   = 2     // pirate clans that must be Allied
+	empireCredits     = 20000 // credits needed, with one trader guild Allied
+	goalCheckInterval = 60    // every 1 sec
+)
+
+// GoalName returns the display name of a goal.
+func GoalName(g Goal) string {
+	if g >= GoalCount {
+		return "Unknown"
+	}
+	return goalNames[g]
+}
+
+// GoalDescription returns a one-line description of a goal.
+func GoalDescription(g Goal) string {
+	if g >= GoalCount {
+		return ""
+	}
+	return goalDescriptions[g]
+}
+
+// ParseGoal looks a goal up by its command-line name.
+func ParseGoal(key string) (Goal, bool) {
+	for g, k := range goalKeys {
+		if k == key {
+			return Goal(g), true
+		}
+	}
+	return 0, false
+}
+
+// GoalKeys returns the goals' command-line names, in goal order.
+func GoalKeys() []string {
+	return goalKeys[:]
+}
+
+// GoalTarget is the system a goal leads to.
+type GoalTarget struct {
+	Sector SectorCoord `json:"sector"`
+	System int         `json:"system"`
+	Name   string      `json:"name"`
+}
+
+// SetGoal picks the run's goal. Only possible before the first tick.
+func (s *Sim) SetGoal(g Goal) bool {
+	if s.Ticks > 0 || g >= GoalCount {
+		return false
+	}
+	s.Goal = g
+	s.GoalTarget = nil
+	if g == GoalHome {
+		s.GoalTarget = s.homeTarget()
+	}
+	s.Log.Add(fmt.Sprintf("Goal: %s. %s", GoalName(g), GoalDescription(g)), MsgDiscovery)
+	if t := s.GoalTarget; t != nil {
+		s.Log.Add(fmt.Sprintf("Home is %s, in sector %s.", t.Name, t.Sector), MsgInfo)
+	}
+	return true
+}
+
+// newGoalTarget names a system in a sector that may not be generated yet.
+func (s *Sim) newGoalTarget(c SectorCoord, idx int) *GoalTarget {
+	return &GoalTarget{Sector: c, System: idx, Name: s.peekSector(c).Systems[idx].Name}
+}
+
+// homeTarget places home at the central system of a corner sector: as far
+// from the start as the galaxy goes, and never a gate.
+func (s *Sim) homeTarget() *GoalTarget {
+	seed := s.Galaxy.Seed
+	rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed>>24|0x40e)))
+	c := SectorCoord{X: GalaxyRadius, Y: GalaxyRadius}
+	if rng.IntN(2) == 0 {
+		c.X = -c.X
+	}
+	if rng.IntN(2) == 0 {
+		c.Y = -c.Y
+	}
+	return s.newGoalTarget(c, 0)
+}
+
+// mlClueChance returns the odds in 10 that a character drops a Monkey Lion
+// clue. Eligible characters know something; players hunting the Monkey Lion
+// press everyone for what they've heard.
+func (s *Sim) mlClueChance(eligible bool) int {
+	switch {
+	case s.Goal != GoalMonkeyLion && eligible:
+		return mlClueOdds
+	case s.Goal != GoalMonkeyLion:
+		return 0
+	case eligible:
+		return mlClueOddsGoal
+	default:
+		return mlClueOddsHunting
+	}
+}
+
+// atGoalTarget returns true if the player is in the goal's target system.
+func (s *Sim) atGoalTarget() bool {
+	t := s.GoalTarget
+	return t != nil && s.Galaxy.Current == t.Sector && s.Sector.CurrentSystem == t.System
+}
+
+// alliedCount returns how many of the named factions the player is Allied
+// with, and the best standing with any of them that isn't.
+func (s *Sim) alliedCount(names []string) (allied int, best string, bestRep int) {
+	bestRep = repMin - 1
+	for _, name := range names {
+		rep, ok := s.Reputation[name]
+		switch {
+		case rep >= RepAllied:
+			allied++
+		case ok && rep > bestRep:
+			best, bestRep = name, rep
+		}
+	}
+	return allied, best, bestRep
+}
+
+// checkGoal ends the run in victory when the win condition is met.
+func (s *Sim) checkGoal() {
+	if s.Ticks%goalCheckInterval != 0 {
+		return
+	}
+	won := false
+	switch s.Goal {
+	case GoalMonkeyLion:
+		won = s.Discovery.MonkeyLionBoarded
+	case GoalHome:
+		won = s.atGoalTarget()
+	case GoalPirateKing:
+		allied, _, _ := s.alliedCount(pirateClanNames)
+		won = allied >= pirateKingClans
+	case GoalEmpire:
+		allied, _, _ := s.alliedCount(traderGuildNames)
+		won = allied > 0 && s.Resources.Credits >= empireCredits
+	}
+	if won {
+		s.Victory = true
+		s.VictoryText = victoryTexts[s.Goal]
+		s.Log.Add("VICTORY! "+s.VictoryText, MsgDiscovery)
+	}
+}
+
+// GoalProgress describes how far the player is toward their goal, one line
+// per condition.
+func (s *Sim) GoalProgress() []string {
+	switch s.Goal {
+	case GoalMonkeyLion:
+		lines := []string{fmt.Sprintf("Clues found: %d/%d", min(s.Discovery.MLCluesFound, MLCluesToReveal), MLCluesToReveal)}
+		trail, ok := s.MonkeyLionTrail()
+		switch {
+		case !ok:
+			lines = append(lines, "Location: unknown")
+		case trail.System >= 0:
+			lines = append(lines, fmt.Sprintf("Adrift at %s, sector %s", trail.Name, trail.Sector))
+		default:
+			lines = append(lines, fmt.Sprintf("Trail leads to sector %s", trail.Sector))
+		}
+		return lines
+	case GoalHome:
+		t := s.GoalTarget
+		if t == nil {
+			return nil
+		}
+		dx, dy := t.Sector.X-s.Galaxy.Current.X, t.Sector.Y-s.Galaxy.Current.Y
+		return []string{
+			fmt.Sprintf("Home: %s, sector %s", t.Name, t.Sector),
+			fmt.Sprintf("Sectors to go: %d", abs(dx)+abs(dy)),
+		}
+	case GoalPirateKing:
+		return s.allianceProgress("clans", pirateClanNames, pirateKingClans)
+	case GoalEmpire:
+		lines := []string{fmt.Sprintf("Credits: %d/%d", min(s.Resources.Credits, empireCredits), empireCredits)}
+		return append(lines, s.allianceProgress("guilds", traderGuildNames, 1)...)
+	default:
+		return []string{fmt.Sprintf("Days survived: %d", s.Ticks/TicksPerDay)}
+	}
+}
+
+// allianceProgress reports Allied factions out of those needed, and the
+// closest one to joining them.
+func (s *Sim) allianceProgress(label string, names []string, need int) []string {
+	allied, best, rep := s.alliedCount(names)
+	lines := []string{fmt.Sprintf("Allied %s: %d/%d", label, min(allied, need), need)}
+	if best != "" && allied < need {
+		lines = append(lines, fmt.Sprintf("%s: %d/%d", best, rep, RepAllied))
+	}
+	return lines
+}
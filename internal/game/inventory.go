@@ -17,6 +17,7 @@ const (
 	// Utility
 	ItemToolKit     // repairs equipment
 	ItemScanner     // improves scan range
+	ItemSuitLamp    // lights the way in the dark
 	ItemKindCount   // sentinel
 )
 
@@ -133,6 +134,7 @@ var itemTable = [ItemKindCount]itemEntry{
 	ItemMedKit:     {"Med Kit"},
 	ItemToolKit:    {"Tool Kit"},
 	ItemScanner:    {"Scanner"},
+	ItemSuitLamp:   {"Suit Lamp"},
 }
 
 // ItemName returns the display name for an item kind.
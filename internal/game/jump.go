@@ -0,0 +1,188 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/spacehole-rogue/spacehole_rogue/internal/world"
+)
+
+// JumpState tracks the jump drive spooling up for an FTL jump.
+// While spooling, the drive reserves its full power cost like constant-draw
+// equipment, so anything switched back on gets shed by tickPower.
+type JumpState struct {
+	Target    int     // destination system index
+	FuelCost  int     // fuel burned on arrival
+	TicksLeft int     // countdown to the jump
+	Gate      GateDir // set for a gate jump; Target is then a system in the neighbouring sector
+}
+
+// JumpSpoolTicks is how long the jump drive spools up before a jump (5 seconds).
+const JumpSpoolTicks = 300
+
+// jumpShedOrder is the order constant-draw equipment is powered down to free
+// power for the jump drive: luxuries first, the generator last.
+var jumpShedOrder = []world.EquipmentKind{
+	world.EquipWeapon,
+	world.EquipShield,
+	world.EquipCargoTransporter,
+	world.EquipScienceConsole,
+	world.EquipCargoConsole,
+	world.EquipMatterRecycler,
+	world.EquipPilotConsole,
+	world.EquipEngine,
+	world.EquipNavConsole,
+	world.EquipLifeSupport,
+	world.EquipGenerator,
+}
+
+// IsJumping returns true while the jump drive is spooling up.
+func (s *Sim) IsJumping() bool {
+	return s.Jump != nil
+}
+
+// JumpDrive returns the ship's jump drive, or nil if it has none.
+func (s *Sim) JumpDrive() *world.Equipment {
+	for i := range s.Grid.Tiles {
+		if eq := s.Grid.Tiles[i].Equipment; eq != nil && eq.Kind == world.EquipJumpDrive {
+			return eq
+		}
+	}
+	return nil
+}
+
+// NavigateTo starts spooling the jump drive for the target star system.
+// The jump needs an intact drive, enough fuel for the distance and the
+// drive's full power cost free; constant-draw equipment is powered down
+// until it is. The jump itself happens in tickJump once the drive is spooled.
+func (s *Sim) NavigateTo(targetIdx int) bool {
+	jump := &JumpState{Target: targetIdx, FuelCost: s.Sector.FuelCostTo(targetIdx)}
+	return s.spoolJump(jump, s.Sector.Systems[targetIdx].Name)
+}
+
+// NavigateGate starts spooling the jump drive for a gate jump from the
+// current system into the neighbouring sector. Gate jumps follow a charted
+// lane, so they always cost GateFuelCost.
+func (s *Sim) NavigateGate() bool {
+	dir := s.Sector.Systems[s.Sector.CurrentSystem].Gate
+	if dir == GateNone {
+		s.Log.Add("No sector gate in this system.", MsgWarning)
+		return false
+	}
+	coord := s.Galaxy.Current.Neighbor(dir)
+	next := s.Galaxy.EnsureSector(coord)
+	arrival := next.GateSystem(dir.Opposite())
+	if arrival < 0 {
+		s.Log.Add("The gate lane is uncharted on the far side.", MsgWarning)
+		return false
+	}
+	jump := &JumpState{Target: arrival, FuelCost: GateFuelCost(), Gate: dir}
+	return s.spoolJump(jump, fmt.Sprintf("%s, sector %s", next.Systems[arrival].Name, coord))
+}
+
+// spoolJump checks the ship can make a jump and starts the drive spooling.
+func (s *Sim) spoolJump(jump *JumpState, dest string) bool {
+	if s.Jump != nil {
+		s.Log.Add("Jump drive already spooling.", MsgWarning)
+		return false
+	}
+	if s.IsOnSurface() {
+		s.Log.Add("Can't jump from the surface. Lift off first.", MsgWarning)
+		return false
+	}
+	drive := s.JumpDrive()
+	if drive == nil {
+		s.Log.Add("No jump drive installed.", MsgWarning)
+		return false
+	}
+	if drive.Condition <= 0 {
+		s.Log.Add("Jump drive is wrecked. Repair it before jumping.", MsgWarning)
+		return false
+	}
+	if s.Resources.JumpFuel < jump.FuelCost {
+		s.Log.Add(fmt.Sprintf("Not enough jump fuel. Need %d, have %d.", jump.FuelCost, s.Resources.JumpFuel), MsgWarning)
+		return false
+	}
+	if s.Resources.Energy < drive.PowerCost {
+		s.Log.Add(fmt.Sprintf("Jump drive needs %d power, have %d. Scrounge some juice.",
+			drive.PowerCost, s.Resources.Energy), MsgWarning)
+		return false
+	}
+
+	s.freePowerForJump(drive.PowerCost)
+	jump.TicksLeft = JumpSpoolTicks
+	s.Jump = jump
+	s.Log.Add(fmt.Sprintf("Jump drive spooling. Destination: %s.", dest), MsgWarning)
+	return true
+}
+
+// freePowerForJump powers down constant-draw equipment in jumpShedOrder
+// until need power is free.
+func (s *Sim) freePowerForJump(need int) {
+	for _, kind := range jumpShedOrder {
+		if s.PowerAvailable() >= need {
+			return
+		}
+		for i := range s.Grid.Tiles {
+			eq := s.Grid.Tiles[i].Equipment
+			if eq == nil || eq.Kind != kind || !eq.On || eq.PowerMode != world.PowerConstant {
+				continue
+			}
+			eq.On = false
+			s.Log.Add(fmt.Sprintf("%s powered down for jump.", eq.Name()), MsgInfo)
+		}
+	}
+}
+
+// AbortJump cancels a spooling jump. No fuel or power is spent.
+func (s *Sim) AbortJump() bool {
+	if s.Jump == nil {
+		return false
+	}
+	s.Jump = nil
+	s.Log.Add("Jump aborted. Drive spinning down.", MsgWarning)
+	return true
+}
+
+// tickJump counts down the spool-up and performs the jump when it finishes.
+func (s *Sim) tickJump() {
+	if s.Jump == nil {
+		return
+	}
+	drive := s.JumpDrive()
+	if drive == nil || drive.Condition <= 0 {
+		s.Jump = nil
+		s.Log.Add("Jump drive failed during spool-up. Jump aborted.", MsgCritical)
+		return
+	}
+	if !drive.CanUse(s.Resources.Energy) {
+		s.Jump = nil
+		s.Log.Add("Jump drive lost power during spool-up. Jump aborted.", MsgCritical)
+		return
+	}
+	s.Jump.TicksLeft--
+	if s.Jump.TicksLeft > 0 {
+		return
+	}
+
+	jump := s.Jump
+	s.Jump = nil
+	s.useEquipment(drive)
+	s.Resources.JumpFuel -= jump.FuelCost
+
+	s.LeaveOrbit()
+	targetIdx := jump.Target
+	if jump.Gate != GateNone {
+		s.enterSector(s.Galaxy.Current.Neighbor(jump.Gate), targetIdx)
+	}
+	s.Sector.CurrentSystem = targetIdx
+	s.Sector.Systems[targetIdx].Visited = true
+	s.Sector.EnsureSystemMap(targetIdx)
+	star := s.Sector.Systems[targetIdx]
+	s.Log.Add(fmt.Sprintf("Arrived at %s. %s. Fuel: -%d.", star.Name, StarTypeName(star.Type), jump.FuelCost), MsgDiscovery)
+	s.Log.Add("Jump complete. Bring ship systems back online.", MsgInfo)
+	if s.Skills.AddXP(SkillPiloting, 5.0) {
+		LogLevelUp(s.Log, SkillPiloting, s.Skills.Level(SkillPiloting))
+	}
+	s.OnSystemVisited(targetIdx)
+	s.JumpCompleted = true
+}
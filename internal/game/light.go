@@ -0,0 +1,198 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/spacehole-rogue/spacehole_rogue/internal/world"
+)
+
+// Out on a surface, how far the player sees depends on the light. Daylight
+// reaches across the landing zone, the night side is black past a couple of
+// tiles, and storms and volcanic smoke close in on everything. A suit lamp
+// lights the dark but can't cut through the weather. Aboard, the shuttle's
+// lights go out with the power, and the lamp is all there is.
+
+const (
+	daylightSight  = 15 // tiles
+	starlightSight = 2  // on the night side
+	interiorSight  = 3  // emergency lighting in derelict interiors
+	lampSight      = 6  // suit lamp
+	darkShipSight  = 1  // feeling your way round a dark shuttle without a lamp
+	stormSight     = 4  // nothing further is seen in a storm...
+	smokeSight     = 8  // ...or through volcanic smoke
+
+	minDayLength   = TicksPerDay / 2 // planets turn once in 12 to 36 hours
+	weatherWindow  = TicksPerHour    // weather changes on the hour
+	stormOdds      = 8               // a storm blows 1 hour in this many...
+	stormOddsProne = 3               // ...or in this many where the scan warned of one
+)
+
+// stormHazards are the scan hazards that warn of storms.
+var stormHazards = map[string]bool{
+	"Micrometeorite storm risk":    true,
+	"Severe electrical storms":     true,
+	"Methane geyser eruptions":     true,
+	"Unpredictable lava eruptions": true,
+}
+
+// surfaceRoll returns a well-mixed number from a surface seed and a counter,
+// so conditions follow from the tick alone.
+func surfaceRoll(seed int64, n uint64) uint64 {
+	x := uint64(seed) + n*0x9e3779b97f4a7c15
+	x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+	x = (x ^ x>>27) * 0x94d049bb133111eb
+	return x ^ x>>31
+}
+
+// outdoors returns true if the surface has a sky.
+func (sm *SurfaceMap) outdoors() bool {
+	return sm.TerrainType != world.TerrainInterior
+}
+
+// IsNight returns true if the landing site is on the night side at a tick.
+func (sm *SurfaceMap) IsNight(ticks uint64) bool {
+	if !sm.outdoors() {
+		return false
+	}
+	day := minDayLength + surfaceRoll(sm.Seed, 0)%TicksPerDay
+	t := (ticks + surfaceRoll(sm.Seed, 1)) % day
+	return t >= day/2
+}
+
+// IsStorm returns true if a storm is blowing at a tick.
+func (sm *SurfaceMap) IsStorm(ticks uint64) bool {
+	if !sm.outdoors() {
+		return false
+	}
+	odds := uint64(stormOdds)
+	if stormHazards[sm.Hazard] {
+		odds = stormOddsProne
+	}
+	return surfaceRoll(sm.Seed, 2+ticks/weatherWindow)%odds == 0
+}
+
+// smoky returns true if volcanic smoke hangs over the surface.
+func (sm *SurfaceMap) smoky() bool {
+	return sm.TerrainType == world.TerrainVolcanic
+}
+
+// sightRadius returns how far the player can see at a tick.
+func (sm *SurfaceMap) sightRadius(ticks uint64, lamp bool) int {
+	r := daylightSight
+	switch {
+	case !sm.outdoors():
+		r = interiorSight
+	case sm.IsNight(ticks):
+		r = starlightSight
+	}
+	if lamp {
+		r = max(r, lampSight)
+	}
+	if sm.smoky() {
+		r = min(r, smokeSight)
+	}
+	if sm.IsStorm(ticks) {
+		r = min(r, stormSight)
+	}
+	return r
+}
+
+// Conditions describes the light and weather on the surface.
+func (sm *SurfaceMap) Conditions() string {
+	s := "Emergency lighting"
+	if sm.outdoors() {
+		s = "Day"
+		if sm.Night {
+			s = "Night"
+		}
+		if sm.smoky() {
+			s += ", smoke"
+		}
+	}
+	if sm.Storm {
+		s += ", storm"
+	}
+	return s
+}
+
+// tickSight keeps what the player can see up to date: out on a surface as
+// the light changes, and aboard while the shuttle's lights are out.
+func (s *Sim) tickSight() {
+	lamp := s.Resources.Inventory.HasItem(ItemSuitLamp)
+	if surf := s.ActiveSurface; surf != nil && s.Outside {
+		s.tickSurfaceLight(surf, lamp)
+	}
+	if !s.Outside && s.ShipDark() {
+		s.updateShipSight(lamp)
+	}
+}
+
+// tickSurfaceLight follows nightfall, dawn and storms on the surface.
+func (s *Sim) tickSurfaceLight(surf *SurfaceMap, lamp bool) {
+	night, storm := surf.IsNight(s.Ticks), surf.IsStorm(s.Ticks)
+	lit := surf.LightRadius > 0
+	if lit && night != surf.Night {
+		if night {
+			s.Log.Add("Night falls. Stay close to your lamp.", MsgWarning)
+		} else {
+			s.Log.Add("Dawn breaks over the horizon.", MsgInfo)
+		}
+	}
+	if lit && storm != surf.Storm {
+		if storm {
+			s.Log.Add("A storm rolls in. You can barely see your hand.", MsgWarning)
+		} else {
+			s.Log.Add("The storm passes.", MsgInfo)
+		}
+	}
+	surf.Night, surf.Storm = night, storm
+	if r := surf.sightRadius(s.Ticks, lamp); r != surf.LightRadius {
+		surf.LightRadius = r
+		surf.UpdateVisibility()
+	}
+}
+
+// ShipDark returns true if the shuttle's lights are out: the battery is flat.
+func (s *Sim) ShipDark() bool {
+	return s.Resources.Energy < 1
+}
+
+// updateShipSight casts the player's view aboard the dark shuttle.
+func (s *Sim) updateShipSight(lamp bool) {
+	if n := s.Grid.Width * s.Grid.Height; len(s.shipSight) != n {
+		s.shipSight = make([]bool, n)
+	}
+	clear(s.shipSight)
+	r := darkShipSight
+	if lamp {
+		r = lampSight
+	}
+	px, py := s.PlayerPos()
+	castFOV(s.Grid, px, py, r, s.shipSight, nil)
+}
+
+// ShipLit returns true if the player can see a tile aboard: everywhere with
+// the lights on, only what their lamp reaches with them out.
+func (s *Sim) ShipLit(x, y int) bool {
+	if !s.ShipDark() {
+		return true
+	}
+	if x < 0 || x >= s.Grid.Width || y < 0 || y >= s.Grid.Height || len(s.shipSight) != s.Grid.Width*s.Grid.Height {
+		return false
+	}
+	return s.shipSight[y*s.Grid.Width+x]
+}
+
+// SightStatus describes the light the player has to see by, for the HUD.
+func (s *Sim) SightStatus() string {
+	if surf := s.ActiveSurface; surf != nil && s.Outside {
+		return fmt.Sprintf("%s - sight %d", surf.Conditions(), surf.LightRadius)
+	}
+	if s.ShipDark() {
+		if s.Resources.Inventory.HasItem(ItemSuitLamp) {
+			return "LIGHTS OUT - suit lamp"
+		}
+		return "LIGHTS OUT"
+	}
+	return ""
+}
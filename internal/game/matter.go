@@ -0,0 +1,285 @@
+package game
+
+import "github.com/spacehole-rogue/spacehole_rogue/internal/world"
+
+// The matter network is the ship's plumbing: a directed graph built from the
+// matter equipment in the ship layout. Tanks store water and organics,
+// recyclers pull dirty matter out of tanks and return it clean, dispensers
+// (food and drink stations) draw clean matter and drains (toilet, shower)
+// send dirty matter back. Every node is piped to every node that handles the
+// same matter, so a second tank adds capacity and a second recycler adds
+// throughput. Power and jump fuel are not matter in this sense and keep their
+// own counters on Resources.
+
+// matterSpec is the capacity and throughput of a kind of matter equipment.
+type matterSpec struct {
+	capacity   int // tanks: matter held; recyclers: buffer per matter type
+	throughput int // recyclers: units per cycle; dispensers and drains: units per use
+}
+
+var matterSpecs = map[world.EquipmentKind]matterSpec{
+	world.EquipWaterTank:      {capacity: 100},
+	world.EquipOrganicTank:    {capacity: 100},
+	world.EquipMatterRecycler: {capacity: 5, throughput: 1},
+	world.EquipFoodStation:    {throughput: 5}, // organics per meal
+	world.EquipDrinkStation:   {throughput: 3}, // water per drink
+	world.EquipShower:         {throughput: 3}, // water per shower
+	world.EquipToilet:         {},              // takes whatever the body holds
+}
+
+// recycledMatter lists the matter types that flow through the network.
+var recycledMatter = []MatterType{MatterWater, MatterOrganic}
+
+// MatterNode is one piece of equipment in the matter network.
+type MatterNode struct {
+	Kind       world.EquipmentKind
+	X, Y       int // tile the equipment stands on
+	Role       ComponentType
+	Matter     MatterType    // tanks: matter held; dispensers and the shower: matter drawn
+	Throughput int           // see matterSpec
+	Tank       MatterPool    // tanks only
+	Buffer     RecyclerState // recyclers only
+
+	equip *world.Equipment // the equipment on the node's tile, bound from the grid
+	links []int            // nodes this one sends matter to
+}
+
+// Running returns true if the node's equipment is switched on and working.
+func (n *MatterNode) Running() bool {
+	return n.equip != nil && n.equip.On && !n.equip.IsBroken()
+}
+
+// MatterNetwork is the graph of a ship's matter equipment.
+type MatterNetwork struct {
+	Nodes []MatterNode
+}
+
+// BuildMatterNetwork creates the matter network for the equipment in a tile
+// grid. Tanks start empty.
+func BuildMatterNetwork(grid *world.TileGrid) MatterNetwork {
+	var net MatterNetwork
+	for y := 0; y < grid.Height; y++ {
+		for x := 0; x < grid.Width; x++ {
+			eq := grid.GetEquipment(x, y)
+			if eq == nil {
+				continue
+			}
+			spec, ok := matterSpecs[eq.Kind]
+			if !ok {
+				continue
+			}
+			info := EquipmentMatter[eq.Kind]
+			node := MatterNode{Kind: eq.Kind, X: x, Y: y, Role: info.Component, Throughput: spec.throughput}
+			switch info.Component {
+			case CompTank:
+				node.Matter = info.OutputType
+				node.Tank.Capacity = spec.capacity
+			case CompRecycler:
+				node.Buffer.Capacity = spec.capacity
+			case CompOutput, CompInput:
+				node.Matter = info.InputType // MatterNone for the toilet
+			}
+			net.Nodes = append(net.Nodes, node)
+		}
+	}
+	net.bind(grid)
+	return net
+}
+
+// bind attaches the nodes to their equipment and lays the pipes between
+// them. Called on build and after loading, since neither is saved.
+func (n *MatterNetwork) bind(grid *world.TileGrid) {
+	for i := range n.Nodes {
+		node := &n.Nodes[i]
+		node.equip = grid.GetEquipment(node.X, node.Y)
+		node.links = nil
+		for j := range n.Nodes {
+			if i != j && piped(node, &n.Nodes[j]) {
+				node.links = append(node.links, j)
+			}
+		}
+	}
+}
+
+// piped returns true if matter flows from node a to node b.
+func piped(a, b *MatterNode) bool {
+	switch a.Role {
+	case CompTank:
+		// Tanks feed recyclers and anything that draws their matter
+		return b.Role == CompRecycler || (b.Role == CompOutput || b.Role == CompInput) && b.Matter == a.Matter
+	case CompRecycler:
+		return b.Role == CompTank
+	case CompInput:
+		// The toilet drains every tank; the shower only its own matter
+		return b.Role == CompTank && (a.Matter == MatterNone || a.Matter == b.Matter)
+	default:
+		return false
+	}
+}
+
+// NodeAt returns the index of the node on a tile, or -1.
+func (n *MatterNetwork) NodeAt(x, y int) int {
+	for i, node := range n.Nodes {
+		if node.X == x && node.Y == y {
+			return i
+		}
+	}
+	return -1
+}
+
+// Pool returns the ship-wide total of a matter type across all tanks.
+func (n *MatterNetwork) Pool(m MatterType) MatterPool {
+	var pool MatterPool
+	for _, node := range n.Nodes {
+		if node.Role == CompTank && node.Matter == m {
+			pool.Clean += node.Tank.Clean
+			pool.Dirty += node.Tank.Dirty
+			pool.Capacity += node.Tank.Capacity
+		}
+	}
+	return pool
+}
+
+// tanks returns the tanks of a matter type piped to node i (to == true) or
+// from it (to == false). Node -1 stands for the whole ship.
+func (n *MatterNetwork) tanks(i int, m MatterType, to bool) []*MatterNode {
+	var list []*MatterNode
+	for j := range n.Nodes {
+		t := &n.Nodes[j]
+		if t.Role != CompTank || t.Matter != m {
+			continue
+		}
+		switch {
+		case i < 0:
+		case to && !hasLink(&n.Nodes[i], j):
+			continue
+		case !to && !hasLink(t, i):
+			continue
+		}
+		list = append(list, t)
+	}
+	return list
+}
+
+func hasLink(node *MatterNode, j int) bool {
+	for _, l := range node.links {
+		if l == j {
+			return true
+		}
+	}
+	return false
+}
+
+// Supply returns the clean matter available to node i from the tanks that
+// feed it, or across the ship for node -1.
+func (n *MatterNetwork) Supply(i int, m MatterType) int {
+	total := 0
+	for _, t := range n.tanks(i, m, false) {
+		total += t.Tank.Clean
+	}
+	return total
+}
+
+// Draw takes clean matter for node i from the tanks that feed it, fullest
+// first, or from any tank for node -1. It takes nothing unless it can take
+// the whole amount.
+func (n *MatterNetwork) Draw(i int, m MatterType, amount int) bool {
+	tanks := n.tanks(i, m, false)
+	total := 0
+	for _, t := range tanks {
+		total += t.Tank.Clean
+	}
+	if total < amount {
+		return false
+	}
+	for ; amount > 0; amount-- {
+		best := tanks[0]
+		for _, t := range tanks[1:] {
+			if t.Tank.Clean > best.Tank.Clean {
+				best = t
+			}
+		}
+		best.Tank.Clean--
+	}
+	return true
+}
+
+// Store sends matter from node i into the tanks it feeds, emptiest first, or
+// into any tank for node -1. Matter that doesn't fit is vented; Store returns
+// how much.
+func (n *MatterNetwork) Store(i int, m MatterType, amount int, dirty bool) int {
+	tanks := n.tanks(i, m, true)
+	for ; amount > 0; amount-- {
+		var best *MatterNode
+		for _, t := range tanks {
+			if t.Tank.Free() > 0 && (best == nil || t.Tank.Free() > best.Tank.Free()) {
+				best = t
+			}
+		}
+		if best == nil {
+			break
+		}
+		if dirty {
+			best.Tank.Dirty++
+		} else {
+			best.Tank.Clean++
+		}
+	}
+	return amount
+}
+
+// Fill sets every tank of a matter type to a share of its capacity, in
+// percent, clean and dirty.
+func (n *MatterNetwork) Fill(m MatterType, cleanPct, dirtyPct int) {
+	for i := range n.Nodes {
+		t := &n.Nodes[i]
+		if t.Role == CompTank && t.Matter == m {
+			t.Tank.Clean = t.Tank.Capacity * cleanPct / 100
+			t.Tank.Dirty = t.Tank.Capacity * dirtyPct / 100
+		}
+	}
+}
+
+// Refill tops every tank off with clean matter and empties the recyclers,
+// as a station's dock crew does.
+func (n *MatterNetwork) Refill() {
+	for i := range n.Nodes {
+		node := &n.Nodes[i]
+		switch node.Role {
+		case CompTank:
+			node.Tank.Clean = node.Tank.Capacity
+			node.Tank.Dirty = 0
+		case CompRecycler:
+			node.Buffer.WaterBuffer = 0
+			node.Buffer.OrganicBuffer = 0
+		}
+	}
+}
+
+// recycle runs one cycle of recycler i for a matter type: intake pulls dirty
+// matter from the tanks that feed it into its buffer, process returns
+// buffered matter clean to the emptiest tank.
+func (n *MatterNetwork) recycle(i int, m MatterType, intake, process bool) {
+	node := &n.Nodes[i]
+	buf := node.Buffer.buffer(m)
+	if buf == nil {
+		return
+	}
+	for range node.Throughput {
+		if intake && *buf < node.Buffer.Capacity {
+			var dirtiest *MatterNode
+			for _, t := range n.tanks(i, m, false) {
+				if t.Tank.Dirty > 0 && (dirtiest == nil || t.Tank.Dirty > dirtiest.Tank.Dirty) {
+					dirtiest = t
+				}
+			}
+			if dirtiest != nil {
+				dirtiest.Tank.Dirty--
+				*buf++
+			}
+		}
+		if process && *buf > 0 && n.Store(i, m, 1, false) == 0 {
+			*buf--
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package game
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/spacehole-rogue/spacehole_rogue/internal/world"
+)
+
+// Injuries are damage the body keeps after the moment that caused it. Burns
+// come from surface hazards, trauma from hits to the hull while aboard and
+// radiation from scanning planets inside radiation belts. Light injuries heal
+// on their own; serious ones keep costing health (burns, trauma) or max
+// health (radiation) until treated at the med bay with a med kit.
+
+// InjuryKind identifies a type of injury.
+type InjuryKind uint8
+
+const (
+	InjuryBurn InjuryKind = iota
+	InjuryTrauma
+	InjuryRadiation
+	InjuryKindCount // sentinel
+)
+
+// Injuries holds the severity of each kind of injury, 0-100.
+type Injuries [InjuryKindCount]int
+
+const (
+	injuryInterval = 600 // injuries heal or worsen every 10 sec
+	InjurySerious  = 30  // at or above this an injury needs the med bay
+)
+
+// Injury sources. Every injury costs 1 health up front per 5 severity.
+const (
+	burnSeverity       = 12 // per hazard touched, less Survival level
+	traumaChancePerDmg = 4  // percent chance per point of hull damage, while aboard
+	traumaSeverity     = 8  // plus up to the hull damage, less Survival level
+	radiationDose      = 40 // per scan of a planet in radiation belts, less 3 per Science level
+	minInjury          = 3
+)
+
+// Med bay treatment, per med kit.
+const (
+	medBayHeal      = 10 // health restored, plus 2 per Survival level
+	medBayTreatment = 30 // severity treated per injury, plus 5 per Survival (Science for radiation) level
+)
+
+// InjuryName returns the display name of an injury.
+func InjuryName(k InjuryKind) string {
+	switch k {
+	case InjuryBurn:
+		return "Burns"
+	case InjuryTrauma:
+		return "Trauma"
+	case InjuryRadiation:
+		return "Radiation"
+	default:
+		return "Unknown"
+	}
+}
+
+var injuryDeathReasons = [InjuryKindCount]string{
+	InjuryBurn:      "You succumbed to your burns.",
+	InjuryTrauma:    "You died of your injuries.",
+	InjuryRadiation: "Radiation sickness killed you.",
+}
+
+// Serious returns the most severe injury that needs treatment, or false if
+// none does.
+func (inj *Injuries) Serious() (InjuryKind, bool) {
+	worst, found := InjuryKind(0), false
+	for k := range InjuryKindCount {
+		if inj[k] >= InjurySerious && (!found || inj[k] > inj[worst]) {
+			worst, found = k, true
+		}
+	}
+	return worst, found
+}
+
+// Any returns true if the player has any injury at all.
+func (inj *Injuries) Any() bool {
+	for _, sev := range inj {
+		if sev > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// seriouslyInjured returns true if any injury needs the med bay.
+func (s *Sim) seriouslyInjured() bool {
+	_, ok := s.Injuries.Serious()
+	return ok
+}
+
+// injure adds an injury and takes the health the moment itself costs.
+func (s *Sim) injure(k InjuryKind, severity int, msg string) {
+	severity = max(severity, minInjury)
+	s.Injuries[k] = min(s.Injuries[k]+severity, 100)
+	s.Needs.Health = max(s.Needs.Health-severity/5, 0)
+	s.Log.Add(msg, MsgWarning)
+	if s.Injuries[k] >= InjurySerious {
+		s.Log.Add(fmt.Sprintf("%s serious (%d). Get to the med bay.", InjuryName(k), s.Injuries[k]), MsgCritical)
+	}
+}
+
+// tickInjuries heals light injuries and lets serious ones do their damage.
+func (s *Sim) tickInjuries() {
+	if s.Ticks%injuryInterval != 0 {
+		return
+	}
+	n := &s.Needs
+	for k := range InjuryKindCount {
+		sev := &s.Injuries[k]
+		switch {
+		case *sev == 0:
+		case *sev < InjurySerious:
+			*sev--
+		case k == InjuryRadiation:
+			n.MaxHealth = max(n.MaxHealth-1, 0)
+			n.Health = min(n.Health, n.MaxHealth)
+		default:
+			n.Health = max(n.Health-1, 0)
+		}
+	}
+}
+
+// burnFromHazard burns the player for walking into hazardous terrain.
+func (s *Sim) burnFromHazard(terrain world.TerrainType) {
+	var msg string
+	switch terrain {
+	case world.TerrainIce:
+		msg = "The crevasse edge is cryogenic. Frost burns your hands."
+	case world.TerrainVolcanic:
+		msg = "Too close to the lava. Your suit scorches and so do you."
+	case world.TerrainInterior:
+		msg = "Sparks from the breach burn you."
+	default:
+		msg = "A vent of superheated gas scalds you."
+	}
+	s.injure(InjuryBurn, burnSeverity-s.SkillCheck(SkillSurvival), msg)
+	if s.Skills.AddXP(SkillSurvival, 1.0) {
+		LogLevelUp(s.Log, SkillSurvival, s.Skills.Level(SkillSurvival))
+	}
+}
+
+// traumaFromHit may throw the player about when the hull takes a hit.
+func (s *Sim) traumaFromHit(dmg int, rng *rand.Rand) {
+	if s.Outside || rng.IntN(100) >= dmg*traumaChancePerDmg {
+		return
+	}
+	severity := traumaSeverity + rng.IntN(dmg+1) - s.SkillCheck(SkillSurvival)
+	s.injure(InjuryTrauma, severity, "The impact throws you into a bulkhead.")
+}
+
+// isRadiationHazard returns true if a scanned planet hazard is radiation.
+func isRadiationHazard(hazard string) bool {
+	return strings.Contains(strings.ToLower(hazard), "radiation")
+}
+
+// radiationFromScan doses the player for a close scan of a planet inside
+// radiation belts. Science knows how to keep the exposure down.
+func (s *Sim) radiationFromScan(scan PlanetScanData) {
+	if !isRadiationHazard(scan.Hazard) {
+		return
+	}
+	dose := radiationDose - 3*s.SkillCheck(SkillScience)
+	s.injure(InjuryRadiation, dose, fmt.Sprintf("The scan run takes you through %s's belts. Radiation dose %d.", scan.Name, max(dose, minInjury)))
+}
+
+// takeMedKit uses a med kit from the inventory, or from the cargo bay if
+// there are more aboard than contracts need. Returns a description of where
+// it came from, or "" if there was none.
+func (s *Sim) takeMedKit() string {
+	r := &s.Resources
+	switch {
+	case r.Inventory.HasItem(ItemMedKit):
+		r.Inventory.RemoveItem(ItemMedKit, 1)
+		return ItemName(ItemMedKit)
+	case r.CargoOf(CargoMedKits) > s.ContractCargo(CargoMedKits) && r.RemoveCargo(CargoMedKits, 1) > 0:
+		return CargoName(CargoMedKits) + " from cargo"
+	default:
+		return ""
+	}
+}
+
+// treatAtMedBay treats the player's injuries and restores health at the
+// med bay, using a med kit and the bay's power.
+func (s *Sim) treatAtMedBay(eq *world.Equipment) {
+	n := &s.Needs
+	if !s.Injuries.Any() && n.Health >= n.MaxHealth {
+		s.Log.Add("Med bay scan: no injuries. Clean bill of health.", MsgInfo)
+		return
+	}
+	source := s.takeMedKit()
+	if source == "" {
+		s.Log.Add("Med bay scan done. Treatment needs a med kit.", MsgWarning)
+		s.reportInjuries()
+		return
+	}
+	s.useEquipment(eq)
+
+	survival, science := s.SkillCheck(SkillSurvival), s.SkillCheck(SkillScience)
+	treated := false
+	for k := range InjuryKindCount {
+		if s.Injuries[k] == 0 {
+			continue
+		}
+		amount := medBayTreatment + 5*survival
+		if k == InjuryRadiation {
+			amount = medBayTreatment + 5*science
+			if s.Skills.AddXP(SkillScience, 4.0) {
+				LogLevelUp(s.Log, SkillScience, s.Skills.Level(SkillScience))
+			}
+		}
+		s.Injuries[k] = max(s.Injuries[k]-amount, 0)
+		treated = true
+	}
+	n.Health = min(n.Health+medBayHeal+2*survival, n.MaxHealth)
+
+	if treated {
+		s.Log.Add(fmt.Sprintf("Treated your injuries with %s. Health %d/%d.", source, n.Health, n.MaxHealth), MsgInfo)
+		s.reportInjuries()
+	} else {
+		s.Log.Add(fmt.Sprintf("Patched up with %s. Health %d/%d.", source, n.Health, n.MaxHealth), MsgInfo)
+	}
+	if s.Skills.AddXP(SkillSurvival, 4.0) {
+		LogLevelUp(s.Log, SkillSurvival, s.Skills.Level(SkillSurvival))
+	}
+}
+
+// reportInjuries logs each injury that remains.
+func (s *Sim) reportInjuries() {
+	for k := range InjuryKindCount {
+		sev := s.Injuries[k]
+		switch {
+		case sev >= InjurySerious:
+			s.Log.Add(fmt.Sprintf("%s: %d, serious.", InjuryName(k), sev), MsgWarning)
+		case sev > 0:
+			s.Log.Add(fmt.Sprintf("%s: %d, healing.", InjuryName(k), sev), MsgInfo)
+		}
+	}
+}
@@ -21,6 +21,8 @@ type Message struct {
 type MessageLog struct {
 	Messages []Message
 	maxSize  int
+
+	criticals uint64 // critical messages ever added, for waking the player
 }
 
 // NewMessageLog creates a log that keeps the most recent maxSize messages.
@@ -34,6 +36,9 @@ func NewMessageLog(maxSize int) *MessageLog {
 // Add appends a message, evicting the oldest if full.
 // Long messages are wrapped at maxWidth (default 55 chars for comms panel).
 func (l *MessageLog) Add(text string, priority MsgPriority) {
+	if priority == MsgCritical {
+		l.criticals++
+	}
 	const maxWidth = 55
 	lines := wrapText(text, maxWidth)
 	for _, line := range lines {
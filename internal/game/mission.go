@@ -0,0 +1,381 @@
+package game
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MissionKind identifies a type of station contract.
+type MissionKind uint8
+
+const (
+	MissionDelivery  MissionKind = iota // haul cargo to another station
+	MissionRetrieval                    // recover an item from a scanned planet and bring it back
+	MissionPassenger                    // fly a passenger to another station
+)
+
+// Mission is a contract from a station mission board.
+// Accepted missions live in Sim.Missions until they are completed or expire.
+type Mission struct {
+	Kind        MissionKind
+	Title       string
+	Giver       string      // station that posted the contract
+	Sector      SectorCoord // sector the contract's systems are in
+	GiverSystem int
+	Faction     int    // the giver station's faction; completing the contract raises standing with it
+	DestSystem  int    // destination station's system, or the planet's system for retrievals
+	DestName    string // destination station or planet
+	PlanetIdx   int    // retrieval: planet object index in DestSystem
+	Cargo       CargoKind
+	Count       int
+	Passenger   string
+	Reward      int
+	Deadline    uint64 // Sim.Ticks the contract expires at
+	Retrieved   bool   // retrieval: item is aboard, return it to the giver
+}
+
+// Mission board limits.
+const (
+	maxActiveMissions = 3
+	maxMissionRange   = 40.0 // sector units; farther stations don't post contracts here
+	missionFailRep    = -5   // standing lost with the giver for an abandoned or expired contract
+)
+
+// Goods stations pay to have hauled.
+var deliveryGoods = []CargoKind{
+	CargoWaterIce, CargoRationPacks, CargoPowerCells, CargoMedKits, CargoCircuitry,
+}
+
+var passengerNames = []string{
+	"Dr. Ana Voss", "Brother Tobias", "Mira Okonkwo", "Accountant Fenwick",
+	"Captain Ruiz (retired)", "Cadet Imre", "Old Pell", "Sister Halvorsen",
+}
+
+// HoursLeft returns the game hours until the mission expires (0 if expired).
+func (m *Mission) HoursLeft(now uint64) int {
+	if now >= m.Deadline {
+		return 0
+	}
+	return int((m.Deadline - now) / TicksPerHour)
+}
+
+// refreshMissionBoard posts a new set of contracts once per game day.
+func (s *Sim) refreshMissionBoard(sd *StationData) {
+	day := int(s.Ticks/TicksPerDay) + 1
+	if sd.BoardDay == day {
+		return
+	}
+	sd.BoardDay = day
+	sd.Board = s.generateMissions(sd, day)
+}
+
+// generateMissions rolls 2-4 contracts for the station in the current system.
+func (s *Sim) generateMissions(sd *StationData, day int) []Mission {
+	here := s.Sector.CurrentSystem
+	seed := s.Sector.Seed*7919 + int64(here)*131 + int64(day)
+	rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed>>8|5)))
+
+	// Stations within range that can take deliveries and passengers
+	var dests []int
+	for i := range s.Sector.Systems {
+		if i == here || s.Sector.DistanceBetween(here, i) > maxMissionRange {
+			continue
+		}
+		s.Sector.EnsureSystemMap(i)
+		if s.Sector.Systems[i].Map.FindStation() != nil {
+			dests = append(dests, i)
+		}
+	}
+	pois := s.scannedPOIs()
+
+	var board []Mission
+	n := 2 + rng.IntN(3)
+	for i := 0; i < n; i++ {
+		var m Mission
+		var ok bool
+		switch roll := rng.IntN(3); {
+		case roll == 2 && len(pois) > 0:
+			m, ok = s.retrievalMission(rng, pois), true
+		case roll == 1 && len(dests) > 0:
+			m, ok = s.passengerMission(rng, dests), true
+		case len(dests) > 0:
+			m, ok = s.deliveryMission(rng, dests), true
+		}
+		if !ok || onBoard(board, m) {
+			continue
+		}
+		m.Giver = sd.Name
+		m.Sector = s.Galaxy.Current
+		m.GiverSystem = here
+		m.Faction = sd.FactionID
+		board = append(board, m)
+	}
+	return board
+}
+
+// onBoard returns true if the board already has this contract or passenger.
+func onBoard(board []Mission, m Mission) bool {
+	for _, b := range board {
+		if b.Title == m.Title || (m.Passenger != "" && b.Passenger == m.Passenger) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Sim) deliveryMission(rng *rand.Rand, dests []int) Mission {
+	dest := dests[rng.IntN(len(dests))]
+	dist := s.Sector.DistanceBetween(s.Sector.CurrentSystem, dest)
+	kind := deliveryGoods[rng.IntN(len(deliveryGoods))]
+	count := 2 + rng.IntN(4)
+	name := s.Sector.Systems[dest].Map.FindStation().Name
+	return Mission{
+		Kind:       MissionDelivery,
+		Title:      fmt.Sprintf("Deliver %dx %s to %s", count, CargoName(kind), name),
+		DestSystem: dest,
+		DestName:   name,
+		Cargo:      kind,
+		Count:      count,
+		Reward:     25 + int(dist*3) + count*CargoBasePrice(kind)/4,
+		Deadline:   s.Ticks + uint64(12+int(dist*2))*TicksPerHour,
+	}
+}
+
+func (s *Sim) passengerMission(rng *rand.Rand, dests []int) Mission {
+	dest := dests[rng.IntN(len(dests))]
+	dist := s.Sector.DistanceBetween(s.Sector.CurrentSystem, dest)
+	who := passengerNames[rng.IntN(len(passengerNames))]
+	name := s.Sector.Systems[dest].Map.FindStation().Name
+	return Mission{
+		Kind:       MissionPassenger,
+		Title:      fmt.Sprintf("Fly %s to %s", who, name),
+		DestSystem: dest,
+		DestName:   name,
+		Passenger:  who,
+		Reward:     40 + int(dist*4),
+		Deadline:   s.Ticks + uint64(8+int(dist*1.5))*TicksPerHour,
+	}
+}
+
+func (s *Sim) retrievalMission(rng *rand.Rand, pois []poiSite) Mission {
+	site := pois[rng.IntN(len(pois))]
+	dist := s.Sector.DistanceBetween(s.Sector.CurrentSystem, site.sys)
+	return Mission{
+		Kind:       MissionRetrieval,
+		Title:      fmt.Sprintf("Recover an artifact from %s", site.scan.Name),
+		DestSystem: site.sys,
+		DestName:   site.scan.Name,
+		PlanetIdx:  site.obj,
+		Cargo:      CargoAlienArtifacts,
+		Count:      1,
+		Reward:     60 + int(dist*3),
+		Deadline:   s.Ticks + uint64(24+int(dist*2))*TicksPerHour,
+	}
+}
+
+// poiSite is a scanned planet with a point of interest.
+type poiSite struct {
+	sys, obj int
+	scan     PlanetScanData
+}
+
+// scannedPOIs lists scanned planets with a point of interest in the current
+// sector, sorted by system and planet so mission boards generate the same
+// way every run.
+func (s *Sim) scannedPOIs() []poiSite {
+	var sites []poiSite
+	for key, scan := range s.Discovery.PlanetsScanned {
+		if scan.POI == "" {
+			continue
+		}
+		sysStr, objStr, _ := strings.Cut(key, ":")
+		id, err1 := strconv.Atoi(sysStr)
+		obj, err2 := strconv.Atoi(objStr)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		sys, ok := s.Sector.SystemIndex(id)
+		if !ok {
+			continue // scanned in another sector
+		}
+		sites = append(sites, poiSite{sys: sys, obj: obj, scan: scan})
+	}
+	sort.Slice(sites, func(i, j int) bool {
+		if sites[i].sys != sites[j].sys {
+			return sites[i].sys < sites[j].sys
+		}
+		return sites[i].obj < sites[j].obj
+	})
+	return sites
+}
+
+// AcceptMission takes contract idx from the station's board.
+// Delivery cargo is loaded into the cargo bay straight away.
+func (s *Sim) AcceptMission(sd *StationData, idx int) bool {
+	if idx < 0 || idx >= len(sd.Board) {
+		return false
+	}
+	if len(s.Missions) >= maxActiveMissions {
+		s.Log.Add(fmt.Sprintf("You already have %d contracts. Finish one first.", maxActiveMissions), MsgWarning)
+		return false
+	}
+	m := sd.Board[idx]
+	if m.Kind == MissionDelivery {
+		loaded := s.Resources.AddCargo(m.Cargo, m.Count)
+		if loaded < m.Count {
+			s.Resources.RemoveCargo(m.Cargo, loaded)
+			s.Log.Add(fmt.Sprintf("Not enough cargo space for %dx %s.", m.Count, CargoName(m.Cargo)), MsgWarning)
+			return false
+		}
+	}
+
+	sd.Board = append(sd.Board[:idx], sd.Board[idx+1:]...)
+	s.Missions = append(s.Missions, m)
+	s.Log.Add(fmt.Sprintf("Contract accepted: %s. %dcr, %dh.", m.Title, m.Reward, m.HoursLeft(s.Ticks)), MsgInfo)
+	if m.Kind == MissionPassenger {
+		s.Log.Add(fmt.Sprintf("%s comes aboard.", m.Passenger), MsgSocial)
+	}
+	return true
+}
+
+// AbandonMission drops active contract idx. Delivery cargo is forfeited.
+func (s *Sim) AbandonMission(idx int) bool {
+	if idx < 0 || idx >= len(s.Missions) {
+		return false
+	}
+	m := s.Missions[idx]
+	s.Missions = append(s.Missions[:idx], s.Missions[idx+1:]...)
+	s.Log.Add(fmt.Sprintf("Contract abandoned: %s.", m.Title), MsgWarning)
+	s.forfeitMission(&m)
+	return true
+}
+
+// forfeitMission takes back what a failed contract put aboard and costs
+// standing with the giver's faction.
+func (s *Sim) forfeitMission(m *Mission) {
+	switch {
+	case m.Kind == MissionDelivery:
+		s.Resources.RemoveCargo(m.Cargo, m.Count)
+	case m.Kind == MissionRetrieval && m.Retrieved:
+		s.Resources.RemoveCargo(m.Cargo, 1)
+	}
+	if sec := s.Galaxy.Sectors[m.Sector]; sec != nil && m.Faction >= 0 && m.Faction < len(sec.Factions) {
+		s.adjustRepNamed(sec.Factions[m.Faction].Name, missionFailRep)
+	}
+}
+
+// contractHeld returns true, and says so, if every unit of a cargo kind
+// aboard belongs to a contract and none can be spared.
+func (s *Sim) contractHeld(kind CargoKind) bool {
+	if s.Resources.CargoOf(kind) > s.ContractCargo(kind) {
+		return false
+	}
+	s.Log.Add(fmt.Sprintf("%s: contract cargo. Deliver it or abandon the contract.", CargoName(kind)), MsgWarning)
+	return true
+}
+
+// ContractCargo returns the units of a cargo kind aboard that belong to
+// active contracts: delivery loads and recovered artifacts. They can't be
+// sold, jettisoned, burned or used.
+func (s *Sim) ContractCargo(kind CargoKind) int {
+	n := 0
+	for _, m := range s.Missions {
+		switch {
+		case m.Cargo != kind:
+		case m.Kind == MissionDelivery:
+			n += m.Count
+		case m.Kind == MissionRetrieval && m.Retrieved:
+			n++
+		}
+	}
+	return n
+}
+
+// expireMissions fails contracts past their deadline.
+func (s *Sim) expireMissions() {
+	kept := s.Missions[:0]
+	for _, m := range s.Missions {
+		if s.Ticks < m.Deadline {
+			kept = append(kept, m)
+			continue
+		}
+		switch m.Kind {
+		case MissionPassenger:
+			s.Log.Add(fmt.Sprintf("Contract expired: %s. They'll find another ride.", m.Title), MsgWarning)
+		case MissionDelivery:
+			s.Log.Add(fmt.Sprintf("Contract expired: %s. Cargo forfeited.", m.Title), MsgWarning)
+		default:
+			s.Log.Add(fmt.Sprintf("Contract expired: %s.", m.Title), MsgWarning)
+		}
+		s.forfeitMission(&m)
+	}
+	s.Missions = kept
+}
+
+// completeStationMissions pays out contracts that end at the docked station.
+func (s *Sim) completeStationMissions() {
+	here := s.Sector.CurrentSystem
+	kept := s.Missions[:0]
+	for _, m := range s.Missions {
+		if m.Sector != s.Galaxy.Current {
+			kept = append(kept, m) // ends in another sector
+			continue
+		}
+		done := false
+		switch m.Kind {
+		case MissionDelivery:
+			if m.DestSystem != here {
+				break
+			}
+			if have := s.Resources.CargoOf(m.Cargo); have < m.Count {
+				s.Log.Add(fmt.Sprintf("%s wants %dx %s; you have %d.", m.DestName, m.Count, CargoName(m.Cargo), have), MsgWarning)
+				break
+			}
+			s.Resources.RemoveCargo(m.Cargo, m.Count)
+			done = true
+		case MissionPassenger:
+			if m.DestSystem == here {
+				s.Log.Add(fmt.Sprintf("%s disembarks.", m.Passenger), MsgSocial)
+				done = true
+			}
+		case MissionRetrieval:
+			if m.GiverSystem != here || !m.Retrieved {
+				break
+			}
+			if s.Resources.RemoveCargo(m.Cargo, 1) == 0 {
+				// The recovered artifact is gone: nothing to hand in
+				s.Log.Add(fmt.Sprintf("Contract failed: %s. The %s is no longer aboard.", m.Title, CargoName(m.Cargo)), MsgWarning)
+				s.forfeitMission(&m)
+				continue
+			}
+			done = true
+		}
+		if !done {
+			kept = append(kept, m)
+			continue
+		}
+		s.Resources.Credits += m.Reward
+		s.Log.Add(fmt.Sprintf("Contract complete: %s. +%dcr.", m.Title, m.Reward), MsgDiscovery)
+		if s.Skills.AddXP(SkillDiplomacy, 5.0) {
+			LogLevelUp(s.Log, SkillDiplomacy, s.Skills.Level(SkillDiplomacy))
+		}
+		s.AdjustRep(m.Faction, 3)
+	}
+	s.Missions = kept
+}
+
+// retrievalFor returns the unfinished retrieval contract for a planet in the
+// current system, or nil.
+func (s *Sim) retrievalFor(planetIdx int) *Mission {
+	for i := range s.Missions {
+		m := &s.Missions[i]
+		if m.Kind == MissionRetrieval && !m.Retrieved && m.Sector == s.Galaxy.Current &&
+			m.DestSystem == s.Sector.CurrentSystem && m.PlanetIdx == planetIdx {
+			return m
+		}
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package game
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// The USS Monkey Lion vanished when its SpaceHole Drive fired, and its trail
+// runs through the clues some characters drop at the end of an episode. The
+// first clue names the sector it went to; each one after narrows the region
+// of that sector's map it can be in, until the last pins down its system.
+// There the ship drifts, running dark, until the player boards it.
+
+// mlTrailRadius is the radius of the sector map region the Monkey Lion is
+// known to be in after 1, 2 and 3 clues. MLCluesToReveal leaves only its
+// system.
+var mlTrailRadius = [MLCluesToReveal - 1]int{18, 11, 6}
+
+const mlBoardRange = 3 // tiles from the shuttle to board on the system map
+
+// MLTrail is where the clues found so far say the Monkey Lion is.
+type MLTrail struct {
+	Sector SectorCoord
+	X, Y   int    // centre of the region on the sector map
+	Radius int    // 0 once the system is known
+	System int    // index of its system, -1 until known
+	Name   string // name of its system, once known
+}
+
+// Contains returns true if a point on the sector map is inside the region.
+func (t MLTrail) Contains(x, y int) bool {
+	dx, dy := x-t.X, y-t.Y
+	return dx*dx+dy*dy <= t.Radius*t.Radius
+}
+
+// peekSector returns a sector without adding it to the galaxy: the
+// generated one if the player has been there, otherwise a fresh copy.
+func (s *Sim) peekSector(c SectorCoord) *Sector {
+	if sec := s.Galaxy.Sectors[c]; sec != nil {
+		return sec
+	}
+	return NewSector(s.Galaxy.SectorSeed(c))
+}
+
+// monkeyLionTarget places the Monkey Lion in any system outside the starting
+// sector. Every sector has at least 12 systems.
+func (s *Sim) monkeyLionTarget() *GoalTarget {
+	seed := s.Galaxy.Seed
+	rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed>>24|0x3717)))
+	var c SectorCoord
+	for c == (SectorCoord{}) {
+		c = SectorCoord{X: rng.IntN(2*GalaxyRadius+1) - GalaxyRadius, Y: rng.IntN(2*GalaxyRadius+1) - GalaxyRadius}
+	}
+	return s.newGoalTarget(c, 1+rng.IntN(11))
+}
+
+// MonkeyLionTrail returns where the clues point, or false before the first.
+func (s *Sim) MonkeyLionTrail() (MLTrail, bool) {
+	clues := s.Discovery.MLCluesFound
+	if clues == 0 {
+		return MLTrail{}, false
+	}
+	t := s.monkeyLionTarget()
+	star := s.peekSector(t.Sector).Systems[t.System]
+	if clues >= MLCluesToReveal {
+		return MLTrail{Sector: t.Sector, X: star.X, Y: star.Y, System: t.System, Name: t.Name}, true
+	}
+
+	// Each clue has its own region, offset from the star by at most half its
+	// radius on each axis so the star is always inside
+	seed := s.Galaxy.Seed
+	rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed>>24|0x7a11)+uint64(clues)))
+	r := mlTrailRadius[clues-1]
+	off := r / 2
+	return MLTrail{
+		Sector: t.Sector,
+		X:      star.X + rng.IntN(2*off+1) - off,
+		Y:      star.Y + rng.IntN(2*off+1) - off,
+		Radius: r,
+		System: -1,
+	}, true
+}
+
+// mlTrailNews describes what the latest clue added to the trail.
+func (s *Sim) mlTrailNews() string {
+	trail, ok := s.MonkeyLionTrail()
+	switch {
+	case !ok:
+		return ""
+	case trail.System >= 0:
+		return fmt.Sprintf("Coordinates locked: the Monkey Lion is at %s, sector %s.", trail.Name, trail.Sector)
+	case s.Discovery.MLCluesFound == 1:
+		return fmt.Sprintf("The trail leads to sector %s.", trail.Sector)
+	default:
+		return fmt.Sprintf("The search area in sector %s narrows.", trail.Sector)
+	}
+}
+
+// MonkeyLion returns the Monkey Lion if it's in this system, or nil.
+func (sm *SystemMap) MonkeyLion() *SpaceObject {
+	for i := range sm.Objects {
+		if sm.Objects[i].Kind == ObjMonkeyLion {
+			return &sm.Objects[i]
+		}
+	}
+	return nil
+}
+
+// tickMonkeyLion puts the Monkey Lion on the system map once the trail has
+// led the player to its system.
+func (s *Sim) tickMonkeyLion() {
+	if s.Ticks%goalCheckInterval != 0 || s.Discovery.MonkeyLionBoarded {
+		return
+	}
+	trail, ok := s.MonkeyLionTrail()
+	if !ok || trail.System < 0 || s.Galaxy.Current != trail.Sector || s.Sector.CurrentSystem != trail.System {
+		return
+	}
+	sm := s.Sector.Systems[trail.System].Map
+	if sm == nil || sm.MonkeyLion() != nil {
+		return
+	}
+	seed := s.Galaxy.Seed
+	rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed>>24|0x5b1d)))
+	sm.Objects = append(sm.Objects, SpaceObject{
+		Kind: ObjMonkeyLion,
+		Name: "USS Monkey Lion",
+		X:    40 + rng.IntN(SystemMapW-80),
+		Y:    15 + rng.IntN(SystemMapH-30),
+	})
+	s.Log.Add("Long-range sensors: a hull the size of a station, running dark. The Monkey Lion.", MsgDiscovery)
+}
+
+// BoardMonkeyLion starts the boarding episode. The shuttle has to be
+// alongside.
+func (s *Sim) BoardMonkeyLion() bool {
+	if s.ActiveEpisode != nil || s.Discovery.MonkeyLionBoarded {
+		return false
+	}
+	sm := s.Sector.CurrentSystemMap()
+	ml := sm.MonkeyLion()
+	if ml == nil {
+		return false
+	}
+	dx, dy := ml.X-sm.Shuttle.TileX(), ml.Y-sm.Shuttle.TileY()
+	if dx*dx+dy*dy > mlBoardRange*mlBoardRange {
+		s.Log.Add("Too far to board. Bring the shuttle alongside.", MsgWarning)
+		return false
+	}
+	s.ActiveEpisode = s.monkeyLionEpisode()
+	s.Log.Add(">>> DOCKING WITH THE MONKEY LION <<<", MsgDiscovery)
+	return true
+}
+
+// mlBoardingOptions are the ways aboard: the skill each trains, and the
+// level it needs (0 for none).
+var mlBoardingOptions = [3]struct {
+	label string
+	skill SkillID
+	level int
+}{
+	{"Cut through the main airlock", SkillSurvival, 0},
+	{"Restart her reactor from the engineering hatch", SkillEngineering, 3},
+	{"Slice into the bridge core and pull the drive logs", SkillScience, 3},
+}
+
+// monkeyLionEpisode builds the boarding episode.
+func (s *Sim) monkeyLionEpisode() *EpisodeState {
+	ep := &EpisodeState{
+		Title: "THE USS MONKEY LION",
+		Briefing: "The hull fills the viewport, kilometres of it, scorched black around the drive section " +
+			"where the SpaceHole Drive tore open space and took her with it. Her running lights are dark, " +
+			"but the docking port answers your handshake with a registry code you have only ever read in clues." +
+			"\n\nSomething aboard is still drawing power.",
+		MonkeyLion: true,
+	}
+	for _, o := range mlBoardingOptions {
+		opt := EpisodeOption{Label: o.label, Enabled: true}
+		if o.level > 0 && s.Skills.Level(o.skill) < o.level {
+			opt.Enabled = false
+			opt.DisableText = fmt.Sprintf("Requires %s Lv %d", SkillName(o.skill), o.level)
+			opt.SkillReq = o.skill
+			opt.SkillLevel = o.level
+		}
+		ep.Options = append(ep.Options, opt)
+	}
+	ep.Options = append(ep.Options, EpisodeOption{Label: "Leave her drifting for now", Enabled: true})
+	return ep
+}
+
+// mlBoardingResults is what each way aboard finds.
+var mlBoardingResults = [3]string{
+	"You cut through the airlock and walk her corridors by suit light. Frost on every surface, " +
+		"and in the mess hall, a meal still set out. The crew is gone. The ship is not.",
+	"The reactor coughs, catches and holds. Deck by deck her lights come up, and the old " +
+		"ship's computer greets you as captain. No one else answers.",
+	"The drive logs unspool across your visor: a jump with no destination, a hole in space, " +
+		"and a last entry from her captain. 'Whoever finds her, she's yours now.'",
+}
+
+// resolveMonkeyLion boards the Monkey Lion, or leaves it for later.
+func (s *Sim) resolveMonkeyLion(ep *EpisodeState, optionIdx int) string {
+	if ep.Resolved || optionIdx < 0 || optionIdx >= len(ep.Options) || !ep.Options[optionIdx].Enabled {
+		return ""
+	}
+	ep.Resolved = true
+	if optionIdx == len(mlBoardingOptions) {
+		s.Log.Add("You pull away from the Monkey Lion. She'll keep.", MsgInfo)
+		return "You pull away. The Monkey Lion drifts on in the dark.\nShe'll be here when you come back."
+	}
+
+	o := mlBoardingOptions[optionIdx]
+	if s.Skills.AddXP(o.skill, 25.0) {
+		LogLevelUp(s.Log, o.skill, s.Skills.Level(o.skill))
+	}
+	s.Discovery.MonkeyLionBoarded = true
+	s.Discovery.EpisodesCompleted++
+	if ml := s.Sector.CurrentSystemMap().MonkeyLion(); ml != nil {
+		ml.Name = "USS Monkey Lion (boarded)"
+	}
+	s.Log.Add("You boarded the USS Monkey Lion!", MsgDiscovery)
+
+	result := mlBoardingResults[optionIdx]
+	if s.Goal != GoalMonkeyLion {
+		// Not what this run was for, but she still has a hold full of stores
+		s.Resources.Credits += 1000
+		s.Resources.JumpFuel = s.Resources.MaxJumpFuel
+		result += "\n\nHer stores fill your fuel tank, and her hold pays well. +1000cr."
+	}
+	return result
+}
@@ -0,0 +1,176 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+)
+
+// Planets circle the star, and moons and stations their planets. Where a
+// body is follows from the tick count alone, so orbits need no saved state
+// and a system the player comes back to has moved on while they were away.
+// The star's gravity pulls on the shuttle, and making orbit means meeting
+// the planet where it is and matching its speed.
+
+const (
+	orbitSquash     = 0.6   // orbits are squashed vertically for pseudo-perspective
+	orbitBaseRadius = 25.0  // tiles: an orbit this far from the star...
+	orbitBasePeriod = 6000  // ...takes 2 game hours; periods grow as radius^1.5
+	moonBaseRadius  = 3.0   // tiles: an orbit this far from a planet...
+	moonBasePeriod  = 1500  // ...takes 25 seconds
+	starGravity     = 0.3   // pull on the shuttle at 1 tile, falling off with distance squared
+	maxGravity      = 0.003 // strongest pull, short of ShuttleAccel so the shuttle can always climb out
+	OrbitRange      = 3     // tiles from a planet to make orbit
+	OrbitMatchSpeed = 0.05  // fastest the shuttle can be moving relative to a planet to make orbit
+)
+
+// Orbit is a circular path around another object in the system.
+type Orbit struct {
+	Parent int     // index of the object orbited
+	Radius float64 // tiles
+	Phase  float64 // angle at tick 0, radians
+	Period int     // ticks per revolution, 0 for objects that don't orbit
+}
+
+// keplerPeriod scales an orbital period with radius^1.5.
+func keplerPeriod(base int, baseRadius, r float64) int {
+	if r <= 0 {
+		return 0
+	}
+	return int(float64(base) * math.Pow(r/baseRadius, 1.5))
+}
+
+// Angle returns where along the orbit the object is at a tick.
+func (o Orbit) Angle(ticks uint64) float64 {
+	return o.Phase + 2*math.Pi*float64(ticks%uint64(o.Period))/float64(o.Period)
+}
+
+// Offset returns the position at an angle relative to the parent.
+func (o Orbit) Offset(angle float64) (float64, float64) {
+	return math.Cos(angle) * o.Radius, math.Sin(angle) * o.Radius * orbitSquash
+}
+
+// ObjectPos returns where an object is at a tick, to a fraction of a tile.
+func (sm *SystemMap) ObjectPos(idx int, ticks uint64) (float64, float64) {
+	obj := &sm.Objects[idx]
+	if obj.Orbit.Period <= 0 {
+		return float64(obj.X), float64(obj.Y)
+	}
+	px, py := sm.ObjectPos(obj.Orbit.Parent, ticks)
+	ox, oy := obj.Orbit.Offset(obj.Orbit.Angle(ticks))
+	return px + ox, py + oy
+}
+
+// ObjectVelocity returns how far an object moves over the next tick.
+func (sm *SystemMap) ObjectVelocity(idx int, ticks uint64) (float64, float64) {
+	x0, y0 := sm.ObjectPos(idx, ticks)
+	x1, y1 := sm.ObjectPos(idx, ticks+1)
+	return x1 - x0, y1 - y0
+}
+
+// TickOrbits moves everything on an orbit to where it is at a tick.
+// Parents come before what orbits them, so each lands on its parent's new
+// position.
+func (sm *SystemMap) TickOrbits(ticks uint64) {
+	for i := range sm.Objects {
+		if sm.Objects[i].Orbit.Period <= 0 {
+			continue
+		}
+		x, y := sm.ObjectPos(i, ticks)
+		sm.Objects[i].X = int(math.Round(x))
+		sm.Objects[i].Y = int(math.Round(y))
+	}
+}
+
+// Pull accelerates the ship toward a point: strength at 1 tile, falling off
+// with distance squared, up to limit.
+func (p *ShipPhysics) Pull(x, y, strength, limit float64) {
+	dx, dy := x-p.X, y-p.Y
+	d2 := dx*dx + dy*dy
+	if d2 < 1 {
+		return // at the centre, pulled every way at once
+	}
+	a := min(strength/d2, limit)
+	d := math.Sqrt(d2)
+	p.VX += dx / d * a
+	p.VY += dy / d * a
+}
+
+// addMoons gives some planets moons: gas giants up to two, the others one
+// at most. It uses its own rng and appends after everything else so the
+// rest of the system generates the same as before.
+func addMoons(sm *SystemMap, seed int64) {
+	rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed>>16|13)))
+	n := len(sm.Objects)
+	for i := 0; i < n; i++ {
+		planet := sm.Objects[i]
+		if planet.Kind != ObjPlanet {
+			continue
+		}
+		moons := 0
+		if planet.PlanetType == PlanetGas {
+			moons = rng.IntN(3)
+		} else if rng.IntN(3) == 0 {
+			moons = 1
+		}
+		for m := 0; m < moons; m++ {
+			r := 5 + 3*float64(m) + rng.Float64()*2
+			o := Orbit{
+				Parent: i,
+				Radius: r,
+				Phase:  rng.Float64() * 2 * math.Pi,
+				Period: keplerPeriod(moonBasePeriod, moonBaseRadius, r),
+			}
+			ox, oy := o.Offset(o.Phase)
+			sm.Objects = append(sm.Objects, SpaceObject{
+				Kind:       ObjPlanet,
+				Name:       fmt.Sprintf("%s-%c", planet.Name, 'a'+m),
+				X:          planet.X + int(math.Round(ox)),
+				Y:          planet.Y + int(math.Round(oy)),
+				PlanetType: []PlanetKind{PlanetRocky, PlanetIce, PlanetVolcanic}[rng.IntN(3)],
+				Orbit:      o,
+			})
+		}
+	}
+}
+
+// OrbitMatch returns why the shuttle can't make orbit around an object in
+// the current system now, or "" if it can.
+func (s *Sim) OrbitMatch(idx int) string {
+	sm := s.Sector.CurrentSystemMap()
+	obj := &sm.Objects[idx]
+	x, y := sm.ObjectPos(idx, s.Ticks)
+	if math.Hypot(sm.Shuttle.X-x, sm.Shuttle.Y-y) > OrbitRange {
+		return fmt.Sprintf("Too far from %s to make orbit.", obj.Name)
+	}
+	vx, vy := sm.ObjectVelocity(idx, s.Ticks)
+	if math.Hypot(sm.Shuttle.VX-vx, sm.Shuttle.VY-vy) > OrbitMatchSpeed {
+		return fmt.Sprintf("Closing too fast. Match %s's speed to make orbit.", obj.Name)
+	}
+	return ""
+}
+
+// tickOrbits moves the current system's planets, moons and stations.
+func (s *Sim) tickOrbits() {
+	sm := s.Sector.Systems[s.Sector.CurrentSystem].Map
+	if sm != nil {
+		sm.TickOrbits(s.Ticks)
+	}
+}
+
+// holdOrbit keeps an orbiting shuttle over its planet. Breaking orbit keeps
+// the planet's velocity. Returns false in free flight.
+func (s *Sim) holdOrbit(sm *SystemMap) bool {
+	if !s.IsOrbiting() || s.OrbitPlanetIdx >= len(sm.Objects) {
+		return false
+	}
+	sm.Shuttle.X, sm.Shuttle.Y = sm.ObjectPos(s.OrbitPlanetIdx, s.Ticks)
+	sm.Shuttle.VX, sm.Shuttle.VY = sm.ObjectVelocity(s.OrbitPlanetIdx, s.Ticks)
+	return true
+}
+
+// pullShuttle lets the star's gravity act on the shuttle.
+func (sm *SystemMap) pullShuttle() {
+	star := &sm.Objects[0]
+	sm.Shuttle.Pull(float64(star.X), float64(star.Y), starGravity, maxGravity)
+}
@@ -0,0 +1,222 @@
+package game
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/spacehole-rogue/spacehole_rogue/internal/world"
+)
+
+// ReplayVersion is the format version written by Recorder.
+const ReplayVersion = 1
+
+// ErrReplayMismatch is returned when a replayed run ends in a different state
+// than the one recorded.
+var ErrReplayMismatch = errors.New("replay state hash mismatch")
+
+// A replay file is JSON lines: one replayHeader, one Action per line,
+// then a replayFooter written when recording stops cleanly. A run that
+// crashed has no footer; it can still be replayed up to the last action.
+
+type replayHeader struct {
+	Version int      `json:"version"`
+	Seed    int64    `json:"seed"`
+	Layout  string   `json:"layout"`
+	Packs   []string `json:"packs,omitempty"` // content packs loaded, base first; absent in older replays
+}
+
+type replayFooter struct {
+	Frames uint64 `json:"end"`
+	Hash   string `json:"hash"`
+}
+
+// Recorder writes a seed and a per-frame stream of actions to a replay file.
+// Call Frame after every Sim.Tick and Record for every Action applied.
+type Recorder struct {
+	w     *bufio.Writer
+	enc   *json.Encoder
+	frame uint64
+	err   error
+}
+
+// NewRecorder starts a replay file for a run created with NewSimWithPrologue.
+func NewRecorder(w io.Writer, seed int64, layout *world.ShipLayout) (*Recorder, error) {
+	bw := bufio.NewWriter(w)
+	r := &Recorder{w: bw, enc: json.NewEncoder(bw)}
+	r.write(replayHeader{Version: ReplayVersion, Seed: seed, Layout: layout.Name, Packs: ContentPacks()})
+	if r.err != nil {
+		return nil, fmt.Errorf("start recording: %w", r.err)
+	}
+	return r, nil
+}
+
+// Frame advances the recorder's frame counter. Call once per Sim.Tick, so
+// a frame is a tick even when a TickPolicy runs several or none per
+// Game.Update.
+func (r *Recorder) Frame() {
+	r.frame++
+}
+
+// Record appends an action, stamped with the current frame.
+// Actions are flushed immediately so a crash loses nothing.
+func (r *Recorder) Record(a Action) {
+	a.Tick = r.frame
+	r.write(a)
+}
+
+// Close writes the final frame count and state hash.
+func (r *Recorder) Close(s *Sim) error {
+	hash, err := s.StateHash()
+	if err != nil {
+		return fmt.Errorf("stop recording: %w", err)
+	}
+	r.write(replayFooter{Frames: r.frame, Hash: hash})
+	if r.err != nil {
+		return fmt.Errorf("stop recording: %w", r.err)
+	}
+	return nil
+}
+
+// write encodes one line and flushes. The first error sticks.
+func (r *Recorder) write(v any) {
+	if r.err != nil {
+		return
+	}
+	if err := r.enc.Encode(v); err != nil {
+		r.err = err
+		return
+	}
+	r.err = r.w.Flush()
+}
+
+// Replay is a recorded run loaded from a replay file.
+type Replay struct {
+	Seed     int64
+	Layout   string
+	Packs    []string // content packs the run was recorded with, nil if not recorded
+	Actions  []Action
+	Frames   uint64 // total frames recorded (last action's frame if incomplete)
+	Hash     string // final state hash, empty if incomplete
+	Complete bool   // false if the recording has no footer (e.g. the game crashed)
+}
+
+// ReadReplay parses a replay file.
+func ReadReplay(r io.Reader) (*Replay, error) {
+	dec := json.NewDecoder(r)
+	var hdr replayHeader
+	if err := dec.Decode(&hdr); err != nil {
+		return nil, fmt.Errorf("read replay header: %w", err)
+	}
+	if hdr.Version != ReplayVersion {
+		return nil, fmt.Errorf("replay version %d not supported (want %d)", hdr.Version, ReplayVersion)
+	}
+	rp := &Replay{Seed: hdr.Seed, Layout: hdr.Layout, Packs: hdr.Packs}
+
+	for line := 2; ; line++ {
+		// Each line is either an Action or the footer
+		var rec struct {
+			Action
+			End  *uint64 `json:"end"`
+			Hash string  `json:"hash"`
+		}
+		if err := dec.Decode(&rec); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("read replay line %d: %w", line, err)
+		}
+		if rec.End != nil {
+			rp.Frames, rp.Hash, rp.Complete = *rec.End, rec.Hash, true
+			break
+		}
+		if rec.Tick < rp.Frames {
+			return nil, fmt.Errorf("read replay line %d: frame %d out of order", line, rec.Tick)
+		}
+		rp.Actions = append(rp.Actions, rec.Action)
+		rp.Frames = rec.Tick
+	}
+	return rp, nil
+}
+
+// Run replays the recording against a fresh Sim, mirroring Game.Update:
+// each frame ticks the simulation once, then applies that frame's actions.
+// Actions stamped frame 0 were taken before the first tick, which only
+// happens in turn-based play.
+// If the recording is complete the final state hash is checked and
+// ErrReplayMismatch is returned (along with the Sim) when it differs.
+func (rp *Replay) Run(layout *world.ShipLayout) (*Sim, error) {
+	if rp.Layout != layout.Name {
+		return nil, fmt.Errorf("replay was recorded with layout %q, not %q", rp.Layout, layout.Name)
+	}
+	if err := rp.checkPacks(ContentPacks()); err != nil {
+		return nil, err
+	}
+	s := NewSimWithPrologue(layout, rp.Seed)
+	next := 0
+	apply := func(frame uint64) {
+		for next < len(rp.Actions) && rp.Actions[next].Tick == frame {
+			s.Apply(rp.Actions[next])
+			next++
+		}
+	}
+	apply(0)
+	for frame := uint64(1); frame <= rp.Frames; frame++ {
+		s.Tick()
+		apply(frame)
+	}
+	if !rp.Complete {
+		return s, nil
+	}
+	hash, err := s.StateHash()
+	if err != nil {
+		return s, err
+	}
+	if hash != rp.Hash {
+		return s, fmt.Errorf("%w: got %s, recorded %s", ErrReplayMismatch, hash, rp.Hash)
+	}
+	return s, nil
+}
+
+// checkPacks returns an error naming the first difference between the
+// content packs the replay was recorded with and the loaded ones. Packs
+// merge in order, so the order must match too. Replays from before packs
+// were recorded aren't checked.
+func (rp *Replay) checkPacks(loaded []string) error {
+	if rp.Packs == nil {
+		return nil
+	}
+	for i, p := range rp.Packs {
+		switch {
+		case i >= len(loaded) || !slices.Contains(loaded, p):
+			return fmt.Errorf("replay was recorded with content pack %q, which is not loaded", p)
+		case loaded[i] != p:
+			return fmt.Errorf("replay was recorded with content pack %q loaded before %q", p, loaded[i])
+		}
+	}
+	if len(loaded) > len(rp.Packs) {
+		return fmt.Errorf("content pack %q is loaded but the replay was recorded without it", loaded[len(rp.Packs)])
+	}
+	return nil
+}
+
+// StateHash returns a hex digest of the gameplay state.
+// It covers everything SaveSim writes except the message log,
+// so two runs with the same seed and actions hash identically.
+func (s *Sim) StateHash() (string, error) {
+	snap, err := s.snapshot()
+	if err != nil {
+		return "", err
+	}
+	snap.Log = nil
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -19,23 +19,33 @@ func (m *MatterPool) Free() int { return m.Capacity - m.Clean - m.Dirty }
 // MaxBodyFullness is the max matter the player's body can hold.
 const MaxBodyFullness = 30
 
-// RecyclerState tracks the internal buffer of the combined matter recycler.
-// Dirty matter is pulled from ship pools into the buffer, then processed into clean.
+// RecyclerState tracks the internal buffer of a combined matter recycler.
+// Dirty matter is pulled from ship tanks into the buffer, then processed into clean.
 type RecyclerState struct {
 	WaterBuffer   int // dirty water waiting to be processed
 	OrganicBuffer int // dirty organic waiting to be processed
 	Capacity      int // max per type in buffer
 }
 
+// buffer returns the buffer for a matter type, or nil if it isn't recycled.
+func (rc *RecyclerState) buffer(m MatterType) *int {
+	switch m {
+	case MatterWater:
+		return &rc.WaterBuffer
+	case MatterOrganic:
+		return &rc.OrganicBuffer
+	default:
+		return nil
+	}
+}
+
 // Resources tracks all matter and energy on the shuttle.
 type Resources struct {
-	Water   MatterPool
-	Organic MatterPool
+	Matter    MatterNetwork // water and organics, held in the ship's tanks
 	Energy    int
 	MaxEnergy int
 	Hull    int
 	MaxHull int
-	Recycler RecyclerState
 
 	// Jump fuel — separate from energy, used for FTL jumps
 	// Incinerator converts matter → fuel
@@ -57,6 +67,8 @@ type Resources struct {
 
 	// Personal inventory — small items the player carries
 	Inventory Inventory
+
+	SuitO2 int // air left in the EVA suit, 0-100; recharged aboard
 }
 
 // BodyFullness returns total matter in the player's body.
@@ -69,29 +81,36 @@ func (r *Resources) TotalWaste() int {
 	return r.WasteOrganic + r.WasteWater
 }
 
-// NewShuttleResources creates the starting resource state.
-// cargoPads is the number of cargo tile equipment in the ship layout.
+// NewShuttleResources creates the starting resource state for a ship layout:
+// one cargo pad per cargo tile and a matter network from its equipment.
 // You just woke from cryo — you've got some waste to deal with.
-// Matter is conserved: Water.Clean + Water.Dirty + BodyWater + WasteWater = Water.Capacity
-func NewShuttleResources(cargoPads int) Resources {
-	return Resources{
-		Water:    MatterPool{Clean: 78, Dirty: 17, Capacity: 100},
-		Organic:  MatterPool{Clean: 55, Dirty: 35, Capacity: 100},
+// Matter is conserved: tank clean + dirty + BodyWater + WasteWater = tank capacity
+func NewShuttleResources(grid *world.TileGrid) Resources {
+	r := Resources{
+		Matter:   BuildMatterNetwork(grid),
 		Energy:   95,
 		MaxEnergy: 100,
 		Hull:     100,
 		MaxHull:  100,
-		Recycler: RecyclerState{Capacity: 5},
 		// Jump fuel — starts with just enough for one jump
 		JumpFuel:    100, // one jump costs ~90
 		MaxJumpFuel: 100,
+		SuitO2:      suitO2Max,
 		// Cryo aftermath: body full of waste, need the toilet
 		WasteOrganic: 10,
 		WasteWater:   5,
 		// Economy
 		Credits:   100,
-		CargoPads: make([]CargoPad, cargoPads),
+		CargoPads: make([]CargoPad, grid.CountEquipment(world.EquipCargoTile)),
 	}
+	r.Matter.Fill(MatterWater, 78, 17)
+	r.Matter.Fill(MatterOrganic, 55, 35)
+	// Deborah's tool kits, for keeping the old girl running, her med kit
+	// and her suit lamp
+	r.Inventory.AddItem(ItemToolKit, 2)
+	r.Inventory.AddItem(ItemMedKit, 1)
+	r.Inventory.AddItem(ItemSuitLamp, 1)
+	return r
 }
 
 // CargoCount returns total cargo units across all pads.
@@ -103,6 +122,17 @@ func (r *Resources) CargoCount() int {
 	return n
 }
 
+// CargoOf returns the units of one cargo kind aboard.
+func (r *Resources) CargoOf(kind CargoKind) int {
+	n := 0
+	for _, p := range r.CargoPads {
+		if p.Kind == kind {
+			n += p.Count
+		}
+	}
+	return n
+}
+
 // PadsUsed returns the number of non-empty cargo pads.
 func (r *Resources) PadsUsed() int {
 	n := 0
@@ -179,6 +209,8 @@ type PlayerNeeds struct {
 	Hunger  int // 0 = full, 100 = starving
 	Thirst  int // 0 = hydrated, 100 = dehydrated
 	Hygiene int // 0 = clean, 100 = filthy
+	Fatigue int // 0 = rested, 100 = collapsing
+	Temp    int // body temperature: 0 = comfortable, -100 = frozen, 100 = cooked
 
 	// Player health — damaged by critical needs, heals slowly when needs are OK
 	Health    int // current health
@@ -191,6 +223,7 @@ func NewPlayerNeeds() PlayerNeeds {
 		Hunger:    0,
 		Thirst:    0,
 		Hygiene:   0,
+		Fatigue:   20,
 		Health:    100,
 		MaxHealth: 100,
 	}
@@ -356,10 +389,10 @@ func PackForMatter(m MatterType) ItemKind {
 	}
 }
 
-// TryFillTank attempts to use a pack from inventory to fill the given tank.
-// Matches pack matter type to tank matter type automatically.
+// TryFillTank attempts to use a pack from inventory to fill the tank on
+// tile (x, y). Matches pack matter type to tank matter type automatically.
 // Returns (filled amount, pack name) if successful, (0, "") if not.
-func (r *Resources) TryFillTank(eq world.EquipmentKind) (int, string) {
+func (r *Resources) TryFillTank(eq world.EquipmentKind, x, y int) (int, string) {
 	matterType := TankMatterType(eq)
 	if matterType == MatterNone {
 		return 0, ""
@@ -376,14 +409,14 @@ func (r *Resources) TryFillTank(eq world.EquipmentKind) (int, string) {
 		space := r.MaxEnergy - r.Energy
 		filled = min(PackFillAmount, space)
 		r.Energy += filled
-	case MatterWater:
-		space := r.Water.Capacity - r.Water.Clean - r.Water.Dirty
-		filled = min(PackFillAmount, space)
-		r.Water.Clean += filled
-	case MatterOrganic:
-		space := r.Organic.Capacity - r.Organic.Clean - r.Organic.Dirty
-		filled = min(PackFillAmount, space)
-		r.Organic.Clean += filled
+	case MatterWater, MatterOrganic:
+		node := r.Matter.NodeAt(x, y)
+		if node < 0 {
+			return 0, ""
+		}
+		tank := &r.Matter.Nodes[node].Tank
+		filled = min(PackFillAmount, tank.Free())
+		tank.Clean += filled
 	case MatterFuel:
 		space := r.MaxJumpFuel - r.JumpFuel
 		filled = min(PackFillAmount, space)
@@ -0,0 +1,912 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+
+	"github.com/mlange-42/ark/ecs"
+	"github.com/spacehole-rogue/spacehole_rogue/internal/world"
+)
+
+// SaveVersion is the schema version written by SaveSim.
+// Bump it whenever simSnapshot changes shape and register a migration
+// from the previous version in saveMigrations.
+const SaveVersion = 5
+
+// saveMigrations upgrades a raw snapshot from version N (the key) to N+1.
+// Migrations edit the decoded JSON object in place, so old fields can be
+// renamed, split or defaulted before the snapshot is decoded for real. They
+// get the run's ship layout for equipment a save's grid predates.
+var saveMigrations = map[int]func(state map[string]json.RawMessage, layout *world.ShipLayout) error{
+	1: migrateGalaxy,
+	2: migrateMatterNetwork,
+	3: migrateOutside,
+	4: migrateSuit,
+}
+
+// saveFile is the top-level envelope of a save file.
+type saveFile struct {
+	Version int             `json:"version"`
+	State   json.RawMessage `json:"state"`
+}
+
+// simSnapshot is the serialized form of a Sim.
+// Pointers that alias other parts of the state (hail/encounter ships,
+// the prologue surface) are stored as indices or flags and re-linked on load.
+type simSnapshot struct {
+	Ticks   uint64 `json:"ticks"`
+	PlayerX int    `json:"player_x"` // ECS player entity position
+	PlayerY int    `json:"player_y"`
+
+	Grid      *world.TileGrid `json:"grid"` // ship interior, including equipment state
+	Resources Resources       `json:"resources"`
+	Needs     PlayerNeeds     `json:"needs"`
+	Injuries  Injuries        `json:"injuries"`
+	Skills    PlayerSkills    `json:"skills"`
+	Discovery *DiscoveryLog   `json:"discovery"`
+	Log       []Message       `json:"log"`
+	LogSize   int             `json:"log_size"`
+	Galaxy    galaxySnapshot  `json:"galaxy"`
+
+	PendingHail     *hailSnapshot      `json:"pending_hail,omitempty"`
+	ActiveEncounter *encounterSnapshot `json:"active_encounter,omitempty"`
+	ActiveEpisode   *EpisodeState      `json:"active_episode,omitempty"`
+	Jump            *JumpState         `json:"jump,omitempty"`
+	Sleep           *SleepState        `json:"sleep,omitempty"`
+	Missions        []Mission          `json:"missions,omitempty"`
+	Reputation      map[string]int     `json:"reputation,omitempty"`
+	Air             []RoomAir          `json:"air"` // one per room, in FindRooms order
+
+	OrbitPlanetIdx    int                `json:"orbit_planet_idx"`
+	ActiveSurface     *SurfaceMap        `json:"active_surface,omitempty"` // nil when it is the prologue surface
+	Creatures         []creatureSnapshot `json:"creatures,omitempty"`      // creature entities on the active surface
+	OnPrologueSurface bool               `json:"on_prologue_surface"`
+	Outside           bool               `json:"outside"`
+	Prologue          *PrologueScenario  `json:"prologue,omitempty"`
+	PrologueSurface   *prologueSnapshot  `json:"prologue_surface,omitempty"`
+
+	Goal       Goal        `json:"goal,omitempty"`
+	GoalTarget *GoalTarget `json:"goal_target,omitempty"`
+
+	PlayerDead  bool   `json:"player_dead"`
+	DeathReason string `json:"death_reason"`
+	KilledBy    string `json:"killed_by,omitempty"`
+	Victory     bool   `json:"victory,omitempty"`
+	VictoryText string `json:"victory_text,omitempty"`
+}
+
+// galaxySnapshot stores every generated sector, ordered by coordinate.
+type galaxySnapshot struct {
+	Seed    int64            `json:"seed"`
+	Current SectorCoord      `json:"current"`
+	Sectors []sectorSnapshot `json:"sectors"`
+}
+
+// sectorSnapshot stores the sector with its generated system maps split out.
+type sectorSnapshot struct {
+	Systems       []StarSystem               `json:"systems"` // Map is always nil here, see Maps
+	Maps          map[int]*systemMapSnapshot `json:"maps"`    // keyed by system index
+	CurrentSystem int                        `json:"current_system"`
+	CursorSystem  int                        `json:"cursor_system"`
+	Seed          int64                      `json:"seed"`
+	Factions      []Faction                  `json:"factions,omitempty"`
+	Coord         SectorCoord                `json:"coord"`
+}
+
+// systemMapSnapshot stores a SystemMap including its unexported rng state.
+type systemMapSnapshot struct {
+	Width    int                   `json:"width"`
+	Height   int                   `json:"height"`
+	Objects  []spaceObjectSnapshot `json:"objects"`
+	Shuttle  ShipPhysics           `json:"shuttle"`
+	Station  *StationData          `json:"station,omitempty"`
+	Seed     int64                 `json:"seed"`
+	StarName string                `json:"star_name"`
+	RNG      []byte                `json:"rng"` // PCG state from MarshalBinary
+}
+
+// spaceObjectSnapshot stores a SpaceObject including its AI timers.
+type spaceObjectSnapshot struct {
+	SpaceObject
+	MoveTimer int `json:"move_timer"`
+	DirTimer  int `json:"dir_timer"`
+}
+
+// hailSnapshot stores a pending hail by object index in the current system.
+type hailSnapshot struct {
+	ShipIdx   int `json:"ship_idx"`
+	TicksLeft int `json:"ticks_left"`
+}
+
+// encounterSnapshot stores an encounter with its ship as an object index.
+type encounterSnapshot struct {
+	EncounterState
+	ShipIdx int `json:"ship_idx"`
+}
+
+// creatureSnapshot stores a creature entity with its position.
+type creatureSnapshot struct {
+	Position
+	Creature
+}
+
+// prologueSnapshot stores the prologue progress and starting map.
+type prologueSnapshot struct {
+	Surface        *SurfaceMap             `json:"surface"`
+	ObjectivesLeft []PrologueObjectiveKind `json:"objectives_left"`
+	FuelFound      bool                    `json:"fuel_found"`
+	PartsFound     bool                    `json:"parts_found"`
+	PowerFound     bool                    `json:"power_found"`
+}
+
+// SaveSim writes the full simulation state to w.
+func SaveSim(w io.Writer, s *Sim) error {
+	snap, err := s.snapshot()
+	if err != nil {
+		return fmt.Errorf("save sim: %w", err)
+	}
+	state, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("save sim: %w", err)
+	}
+	if err := json.NewEncoder(w).Encode(saveFile{Version: SaveVersion, State: state}); err != nil {
+		return fmt.Errorf("save sim: %w", err)
+	}
+	return nil
+}
+
+// LoadSim reads a simulation written by SaveSim.
+// The layout must be the ship layout the run was started with.
+func LoadSim(r io.Reader, layout *world.ShipLayout) (*Sim, error) {
+	var f saveFile
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return nil, fmt.Errorf("load sim: %w", err)
+	}
+	state, err := migrateSave(f.Version, f.State, layout)
+	if err != nil {
+		return nil, fmt.Errorf("load sim: %w", err)
+	}
+	var snap simSnapshot
+	if err := json.Unmarshal(state, &snap); err != nil {
+		return nil, fmt.Errorf("load sim: %w", err)
+	}
+	s, err := restoreSim(&snap, layout)
+	if err != nil {
+		return nil, fmt.Errorf("load sim: %w", err)
+	}
+	return s, nil
+}
+
+// migrateGalaxy upgrades a version 1 save, which had a single sector, to a
+// galaxy with that sector at the start. Reputation was indexed by the
+// sector's factions and is now keyed by faction name. Early version 1 saves
+// predate factions altogether; their sector gets its factions now, its
+// ships and stations are assigned to them (see assignSavedFactions) and
+// accepted contracts take their giver station's faction.
+func migrateGalaxy(state map[string]json.RawMessage, _ *world.ShipLayout) error {
+	var sector struct {
+		Seed     int64     `json:"seed"`
+		Factions []Faction `json:"factions"`
+	}
+	if err := json.Unmarshal(state["sector"], &sector); err != nil {
+		return fmt.Errorf("sector: %w", err)
+	}
+	if len(sector.Factions) == 0 {
+		sector.Factions = GenerateFactions(sector.Seed)
+		raw, stations, err := assignSavedFactions(state["sector"], sector.Factions)
+		if err != nil {
+			return fmt.Errorf("sector: %w", err)
+		}
+		state["sector"] = raw
+		if raw, ok := state["missions"]; ok {
+			if state["missions"], err = setMissionFactions(raw, stations, -1); err != nil {
+				return fmt.Errorf("missions: %w", err)
+			}
+		}
+	}
+	galaxy, err := json.Marshal(struct {
+		Seed    int64             `json:"seed"`
+		Sectors []json.RawMessage `json:"sectors"`
+	}{sector.Seed, []json.RawMessage{state["sector"]}})
+	if err != nil {
+		return err
+	}
+	state["galaxy"] = galaxy
+	delete(state, "sector")
+
+	raw, ok := state["reputation"]
+	if !ok {
+		return nil
+	}
+	var byIndex []int
+	if err := json.Unmarshal(raw, &byIndex); err != nil {
+		return fmt.Errorf("reputation: %w", err)
+	}
+	byName := make(map[string]int)
+	for i, rep := range byIndex {
+		if i < len(sector.Factions) && rep != 0 {
+			byName[sector.Factions[i].Name] = rep
+		}
+	}
+	state["reputation"], err = json.Marshal(byName)
+	return err
+}
+
+// assignSavedFactions gives a sector saved before factions existed the
+// factions generated from its seed, and assigns each generated system's
+// ships and station to them the way generation does. Stations named their
+// faction; a name that matches one of the new factions is kept. Returns the
+// sector and each station's faction by system index.
+func assignSavedFactions(raw json.RawMessage, factions []Faction) (json.RawMessage, map[int]int, error) {
+	var sec map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &sec); err != nil {
+		return nil, nil, err
+	}
+	var err error
+	if sec["factions"], err = json.Marshal(factions); err != nil {
+		return nil, nil, err
+	}
+	var maps map[int]map[string]json.RawMessage
+	if raw, ok := sec["maps"]; ok {
+		if err := json.Unmarshal(raw, &maps); err != nil {
+			return nil, nil, fmt.Errorf("system maps: %w", err)
+		}
+	}
+	stations := make(map[int]int)
+	for idx, m := range maps {
+		var seed int64
+		var fields []map[string]json.RawMessage
+		if err := json.Unmarshal(m["seed"], &seed); err != nil {
+			return nil, nil, fmt.Errorf("system map %d seed: %w", idx, err)
+		}
+		if err := json.Unmarshal(m["objects"], &fields); err != nil {
+			return nil, nil, fmt.Errorf("system map %d objects: %w", idx, err)
+		}
+		objs := make([]SpaceObject, len(fields))
+		for i, f := range fields {
+			if err := json.Unmarshal(f["Kind"], &objs[i].Kind); err != nil {
+				return nil, nil, fmt.Errorf("system map %d object %d: %w", idx, i, err)
+			}
+			if raw, ok := f["AIKind"]; ok {
+				if err := json.Unmarshal(raw, &objs[i].AIKind); err != nil {
+					return nil, nil, fmt.Errorf("system map %d object %d: %w", idx, i, err)
+				}
+			}
+		}
+		assignFactions(objs, seed, factions)
+		for i := range objs {
+			if objs[i].Kind == ObjStation {
+				stations[idx] = objs[i].Faction
+			}
+		}
+		if raw, ok := m["station"]; ok && string(raw) != "null" {
+			if m["station"], err = migrateStationFaction(raw, factions, stations, idx); err != nil {
+				return nil, nil, fmt.Errorf("system map %d station: %w", idx, err)
+			}
+		}
+		for i, f := range fields {
+			if objs[i].Kind == ObjStation {
+				objs[i].Faction = stations[idx]
+			}
+			f["Faction"] = json.RawMessage(strconv.Itoa(objs[i].Faction))
+		}
+		if m["objects"], err = json.Marshal(fields); err != nil {
+			return nil, nil, err
+		}
+	}
+	if maps != nil {
+		if sec["maps"], err = json.Marshal(maps); err != nil {
+			return nil, nil, err
+		}
+	}
+	raw, err = json.Marshal(sec)
+	return raw, stations, err
+}
+
+// migrateStationFaction turns a station's faction name into an index into
+// the sector's factions, kept in stations[sys], falling back to the faction
+// its station object was assigned when the name isn't one of them. The
+// contracts on its board take the station's faction.
+func migrateStationFaction(raw json.RawMessage, factions []Faction, stations map[int]int, sys int) (json.RawMessage, error) {
+	var sd map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &sd); err != nil {
+		return nil, err
+	}
+	var name string
+	if raw, ok := sd["Faction"]; ok {
+		if err := json.Unmarshal(raw, &name); err != nil {
+			return nil, fmt.Errorf("faction: %w", err)
+		}
+	}
+	for i, f := range factions {
+		if name != "" && f.Name == name {
+			stations[sys] = i
+			break
+		}
+	}
+	delete(sd, "Faction")
+	sd["FactionID"] = json.RawMessage(strconv.Itoa(stations[sys]))
+	if raw, ok := sd["Board"]; ok {
+		var err error
+		if sd["Board"], err = setMissionFactions(raw, stations, sys); err != nil {
+			return nil, fmt.Errorf("board: %w", err)
+		}
+	}
+	return json.Marshal(sd)
+}
+
+// setMissionFactions gives contracts saved before factions existed their
+// giver station's faction: the station in sys, or in each contract's
+// GiverSystem when sys is -1.
+func setMissionFactions(raw json.RawMessage, stations map[int]int, sys int) (json.RawMessage, error) {
+	var missions []map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &missions); err != nil {
+		return nil, err
+	}
+	for i, m := range missions {
+		giver := sys
+		if giver < 0 {
+			if err := json.Unmarshal(m["GiverSystem"], &giver); err != nil {
+				return nil, fmt.Errorf("contract %d: %w", i, err)
+			}
+		}
+		m["Faction"] = json.RawMessage(strconv.Itoa(stations[giver]))
+	}
+	return json.Marshal(missions)
+}
+
+// migrateMatterNetwork upgrades a version 2 save, which had one water pool,
+// one organic pool and one recycler buffer, to a matter network built from
+// the saved ship grid. Pooled matter is poured into the tanks in layout order
+// and the buffer goes to the first recycler.
+func migrateMatterNetwork(state map[string]json.RawMessage, _ *world.ShipLayout) error {
+	var grid world.TileGrid
+	if err := json.Unmarshal(state["grid"], &grid); err != nil {
+		return fmt.Errorf("grid: %w", err)
+	}
+	var res map[string]json.RawMessage
+	if err := json.Unmarshal(state["resources"], &res); err != nil {
+		return fmt.Errorf("resources: %w", err)
+	}
+	var water, organic MatterPool
+	var recycler RecyclerState
+	for key, v := range map[string]any{"Water": &water, "Organic": &organic, "Recycler": &recycler} {
+		if raw, ok := res[key]; ok {
+			if err := json.Unmarshal(raw, v); err != nil {
+				return fmt.Errorf("resources %s: %w", key, err)
+			}
+		}
+		delete(res, key)
+	}
+
+	net := BuildMatterNetwork(&grid)
+	for _, m := range recycledMatter {
+		pool := water
+		if m == MatterOrganic {
+			pool = organic
+		}
+		net.Store(-1, m, pool.Clean, false)
+		net.Store(-1, m, pool.Dirty, true)
+	}
+	for i := range net.Nodes {
+		if net.Nodes[i].Role == CompRecycler {
+			net.Nodes[i].Buffer.WaterBuffer = recycler.WaterBuffer
+			net.Nodes[i].Buffer.OrganicBuffer = recycler.OrganicBuffer
+			break
+		}
+	}
+
+	var err error
+	if res["Matter"], err = json.Marshal(net); err != nil {
+		return err
+	}
+	state["resources"], err = json.Marshal(res)
+	return err
+}
+
+// migrateOutside upgrades a version 3 save, which had no room air, no life
+// support and no record of the player leaving the shuttle. The air is left
+// out so the rooms load with standard air, and the layout's life support is
+// installed on the saved grid so something keeps it that way. The player is
+// outside if they were on the prologue surface, which is where the game used
+// to put them on load.
+func migrateOutside(state map[string]json.RawMessage, layout *world.ShipLayout) error {
+	if raw, ok := state["on_prologue_surface"]; ok {
+		state["outside"] = raw
+	}
+
+	var grid world.TileGrid
+	if err := json.Unmarshal(state["grid"], &grid); err != nil {
+		return fmt.Errorf("grid: %w", err)
+	}
+	fresh := layout.ToTileGrid()
+	if grid.Width != fresh.Width || grid.Height != fresh.Height {
+		return nil // not this layout's grid; restoreSim rejects it
+	}
+	for y := 0; y < fresh.Height; y++ {
+		for x := 0; x < fresh.Width; x++ {
+			if fresh.EquipmentKindAt(x, y) == world.EquipLifeSupport && grid.Get(x, y).Equipment == nil {
+				t := fresh.Get(x, y)
+				t.Equipment.On = true // installed running, so the air holds
+				grid.Set(x, y, t)
+			}
+		}
+	}
+	var err error
+	state["grid"], err = json.Marshal(&grid)
+	return err
+}
+
+// migrateSuit upgrades a version 4 save, which had no EVA suit, by filling
+// the suit's O2. Body temperature starts comfortable at its zero value.
+// Version 5 also added the suit gear cargo kinds ahead of content pack
+// cargo, so pack cargo on pads and in station tables moves up past them.
+func migrateSuit(state map[string]json.RawMessage, _ *world.ShipLayout) error {
+	var res map[string]json.RawMessage
+	if err := json.Unmarshal(state["resources"], &res); err != nil {
+		return fmt.Errorf("resources: %w", err)
+	}
+	res["SuitO2"] = json.RawMessage(strconv.Itoa(suitO2Max))
+	if raw, ok := res["CargoPads"]; ok {
+		var pads []CargoPad
+		if err := json.Unmarshal(raw, &pads); err != nil {
+			return fmt.Errorf("cargo pads: %w", err)
+		}
+		for i := range pads {
+			if pads[i].Kind >= suitGearFirst {
+				pads[i].Kind += suitGearKinds
+			}
+		}
+		var err error
+		if res["CargoPads"], err = json.Marshal(pads); err != nil {
+			return err
+		}
+	}
+	var err error
+	if state["resources"], err = json.Marshal(res); err != nil {
+		return err
+	}
+
+	var galaxy map[string]json.RawMessage
+	if err := json.Unmarshal(state["galaxy"], &galaxy); err != nil {
+		return fmt.Errorf("galaxy: %w", err)
+	}
+	var sectors []map[string]json.RawMessage
+	if err := json.Unmarshal(galaxy["sectors"], &sectors); err != nil {
+		return fmt.Errorf("sectors: %w", err)
+	}
+	for _, sec := range sectors {
+		raw, ok := sec["maps"]
+		if !ok {
+			continue
+		}
+		var maps map[string]map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &maps); err != nil {
+			return fmt.Errorf("system maps: %w", err)
+		}
+		for _, m := range maps {
+			raw, ok := m["station"]
+			if !ok || string(raw) == "null" {
+				continue
+			}
+			if m["station"], err = shiftStationCargo(raw); err != nil {
+				return fmt.Errorf("station: %w", err)
+			}
+		}
+		if sec["maps"], err = json.Marshal(maps); err != nil {
+			return err
+		}
+	}
+	if galaxy["sectors"], err = json.Marshal(sectors); err != nil {
+		return err
+	}
+	state["galaxy"], err = json.Marshal(galaxy)
+	return err
+}
+
+// Where version 5 put the suit gear cargo kinds, and how many there are.
+const (
+	suitGearFirst = CargoO2Canisters
+	suitGearKinds = CargoKindCount - CargoO2Canisters
+)
+
+// shiftStationCargo opens a gap for the suit gear kinds in a version 4
+// station's per-cargo tables. The station doesn't carry them.
+func shiftStationCargo(raw json.RawMessage) (json.RawMessage, error) {
+	var sd map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &sd); err != nil {
+		return nil, err
+	}
+	for _, key := range []string{"SellPrices", "BuyPrices", "Stock", "Stocked"} {
+		var table []json.RawMessage
+		if err := json.Unmarshal(sd[key], &table); err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		if len(table) <= int(suitGearFirst) {
+			continue // no pack cargo; fitCargoKinds grows it on load
+		}
+		zero := json.RawMessage("0")
+		if key == "Stocked" {
+			zero = json.RawMessage("false")
+		}
+		gap := make([]json.RawMessage, suitGearKinds)
+		for i := range gap {
+			gap[i] = zero
+		}
+		table = append(table[:suitGearFirst], append(gap, table[suitGearFirst:]...)...)
+		var err error
+		if sd[key], err = json.Marshal(table); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(sd)
+}
+
+// migrateSave runs registered migrations until state is at SaveVersion.
+func migrateSave(version int, state json.RawMessage, layout *world.ShipLayout) (json.RawMessage, error) {
+	if version > SaveVersion {
+		return nil, fmt.Errorf("save version %d is newer than supported version %d", version, SaveVersion)
+	}
+	if version == SaveVersion {
+		return state, nil
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(state, &obj); err != nil {
+		return nil, err
+	}
+	for ; version < SaveVersion; version++ {
+		migrate, ok := saveMigrations[version]
+		if !ok {
+			return nil, fmt.Errorf("no migration from save version %d", version)
+		}
+		if err := migrate(obj, layout); err != nil {
+			return nil, fmt.Errorf("migrate save version %d: %w", version, err)
+		}
+	}
+	return json.Marshal(obj)
+}
+
+// snapshot captures the Sim into its serializable form.
+func (s *Sim) snapshot() (*simSnapshot, error) {
+	px, py := s.PlayerPos()
+	snap := &simSnapshot{
+		Ticks:          s.Ticks,
+		PlayerX:        px,
+		PlayerY:        py,
+		Grid:           s.Grid,
+		Resources:      s.Resources,
+		Needs:          s.Needs,
+		Injuries:       s.Injuries,
+		Skills:         s.Skills,
+		Discovery:      s.Discovery,
+		Log:            s.Log.Messages,
+		LogSize:        s.Log.maxSize,
+		ActiveEpisode:  s.ActiveEpisode,
+		Jump:           s.Jump,
+		Sleep:          s.Sleep,
+		Missions:       s.Missions,
+		Reputation:     s.Reputation,
+		Air:            s.Air,
+		OrbitPlanetIdx: s.OrbitPlanetIdx,
+		Outside:        s.Outside,
+		Prologue:       s.Prologue,
+		Goal:           s.Goal,
+		GoalTarget:     s.GoalTarget,
+		PlayerDead:     s.PlayerDead,
+		DeathReason:    s.DeathReason,
+		KilledBy:       s.killedBy,
+		Victory:        s.Victory,
+		VictoryText:    s.VictoryText,
+	}
+
+	galaxy, err := snapshotGalaxy(s.Galaxy)
+	if err != nil {
+		return nil, err
+	}
+	snap.Galaxy = galaxy
+
+	sm := s.Sector.Systems[s.Sector.CurrentSystem].Map
+	if s.PendingHail != nil {
+		snap.PendingHail = &hailSnapshot{
+			ShipIdx:   objectIndex(sm, s.PendingHail.Ship),
+			TicksLeft: s.PendingHail.TicksLeft,
+		}
+	}
+	if s.ActiveEncounter != nil {
+		enc := *s.ActiveEncounter
+		enc.ShipObj = nil
+		snap.ActiveEncounter = &encounterSnapshot{
+			EncounterState: enc,
+			ShipIdx:        objectIndex(sm, s.ActiveEncounter.ShipObj),
+		}
+	}
+
+	if s.PrologueSurface != nil {
+		ps := s.PrologueSurface
+		snap.PrologueSurface = &prologueSnapshot{
+			Surface:        ps.SurfaceMap,
+			ObjectivesLeft: ps.ObjectivesLeft,
+			FuelFound:      ps.FuelFound,
+			PartsFound:     ps.PartsFound,
+			PowerFound:     ps.PowerFound,
+		}
+		snap.OnPrologueSurface = s.ActiveSurface != nil && s.ActiveSurface == ps.SurfaceMap
+	}
+	if !snap.OnPrologueSurface {
+		snap.ActiveSurface = s.ActiveSurface
+	}
+	s.Creatures(func(pos Position, c *Creature) {
+		snap.Creatures = append(snap.Creatures, creatureSnapshot{pos, *c})
+	})
+	return snap, nil
+}
+
+// snapshotGalaxy captures every generated sector. Sectors are sorted by
+// coordinate so the same state always serializes the same way.
+func snapshotGalaxy(g *Galaxy) (galaxySnapshot, error) {
+	snap := galaxySnapshot{Seed: g.Seed, Current: g.Current}
+	coords := make([]SectorCoord, 0, len(g.Sectors))
+	for c := range g.Sectors {
+		coords = append(coords, c)
+	}
+	sort.Slice(coords, func(i, j int) bool {
+		if coords[i].Y != coords[j].Y {
+			return coords[i].Y < coords[j].Y
+		}
+		return coords[i].X < coords[j].X
+	})
+	for _, c := range coords {
+		sec, err := snapshotSector(g.Sectors[c])
+		if err != nil {
+			return snap, fmt.Errorf("sector %s: %w", c, err)
+		}
+		snap.Sectors = append(snap.Sectors, sec)
+	}
+	return snap, nil
+}
+
+// snapshotSector splits generated system maps out of the sector.
+func snapshotSector(sec *Sector) (sectorSnapshot, error) {
+	snap := sectorSnapshot{
+		Systems:       make([]StarSystem, len(sec.Systems)),
+		Maps:          make(map[int]*systemMapSnapshot),
+		CurrentSystem: sec.CurrentSystem,
+		CursorSystem:  sec.CursorSystem,
+		Seed:          sec.Seed,
+		Factions:      sec.Factions,
+		Coord:         sec.Coord,
+	}
+	for i, sys := range sec.Systems {
+		if sys.Map != nil {
+			sm, err := snapshotSystemMap(sys.Map)
+			if err != nil {
+				return snap, fmt.Errorf("system %d: %w", i, err)
+			}
+			snap.Maps[i] = sm
+		}
+		sys.Map = nil
+		snap.Systems[i] = sys
+	}
+	return snap, nil
+}
+
+// snapshotSystemMap captures a system map, its NPC timers and its rng state.
+func snapshotSystemMap(sm *SystemMap) (*systemMapSnapshot, error) {
+	rngState, err := sm.src.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	snap := &systemMapSnapshot{
+		Width:    sm.Width,
+		Height:   sm.Height,
+		Objects:  make([]spaceObjectSnapshot, len(sm.Objects)),
+		Shuttle:  sm.Shuttle,
+		Station:  sm.Station,
+		Seed:     sm.seed,
+		StarName: sm.starName,
+		RNG:      rngState,
+	}
+	for i, obj := range sm.Objects {
+		snap.Objects[i] = spaceObjectSnapshot{
+			SpaceObject: obj,
+			MoveTimer:   obj.moveTimer,
+			DirTimer:    obj.dirTimer,
+		}
+	}
+	return snap, nil
+}
+
+// restoreSystemMap rebuilds a system map from its snapshot.
+func restoreSystemMap(snap *systemMapSnapshot) (*SystemMap, error) {
+	src := &rand.PCG{}
+	if err := src.UnmarshalBinary(snap.RNG); err != nil {
+		return nil, err
+	}
+	sm := &SystemMap{
+		Width:    snap.Width,
+		Height:   snap.Height,
+		Objects:  make([]SpaceObject, len(snap.Objects)),
+		Shuttle:  snap.Shuttle,
+		Station:  snap.Station,
+		rng:      rand.New(src),
+		src:      src,
+		seed:     snap.Seed,
+		starName: snap.StarName,
+	}
+	for i, o := range snap.Objects {
+		obj := o.SpaceObject
+		obj.moveTimer = o.MoveTimer
+		obj.dirTimer = o.DirTimer
+		sm.Objects[i] = obj
+	}
+	if sm.Station != nil {
+		sm.Station.fitCargoKinds()
+	}
+	return sm, nil
+}
+
+// restoreSector rebuilds a sector and its generated system maps.
+func restoreSector(snap *sectorSnapshot) (*Sector, error) {
+	sector := &Sector{
+		Systems:       snap.Systems,
+		CurrentSystem: snap.CurrentSystem,
+		CursorSystem:  snap.CursorSystem,
+		Seed:          snap.Seed,
+		Factions:      snap.Factions,
+		Coord:         snap.Coord,
+	}
+	if len(sector.Factions) == 0 {
+		sector.Factions = GenerateFactions(sector.Seed) // saved before factions existed
+	}
+	if sector.CurrentSystem < 0 || sector.CurrentSystem >= len(sector.Systems) {
+		return nil, fmt.Errorf("current system %d out of range", sector.CurrentSystem)
+	}
+	for idx, smSnap := range snap.Maps {
+		if idx < 0 || idx >= len(sector.Systems) {
+			return nil, fmt.Errorf("system map %d out of range", idx)
+		}
+		sm, err := restoreSystemMap(smSnap)
+		if err != nil {
+			return nil, fmt.Errorf("system %d: %w", idx, err)
+		}
+		sector.Systems[idx].Map = sm
+	}
+	sector.assignGates() // saved before gates existed
+	return sector, nil
+}
+
+// restoreSim rebuilds a Sim, its ECS world and the player entity from a snapshot.
+func restoreSim(snap *simSnapshot, layout *world.ShipLayout) (*Sim, error) {
+	if snap.Grid == nil || snap.Discovery == nil {
+		return nil, fmt.Errorf("snapshot is missing ship grid or discovery log")
+	}
+	if snap.Grid.Width != layout.Width || snap.Grid.Height != layout.Height {
+		return nil, fmt.Errorf("ship grid %dx%d does not match layout %q (%dx%d)",
+			snap.Grid.Width, snap.Grid.Height, layout.Name, layout.Width, layout.Height)
+	}
+
+	w := ecs.NewWorld(256)
+	posMap := ecs.NewMap[Position](w)
+	player := ecs.NewMap2[Position, PlayerControlled](w).NewEntity(
+		&Position{X: snap.PlayerX, Y: snap.PlayerY},
+		&PlayerControlled{},
+	)
+
+	log := NewMessageLog(max(snap.LogSize, 1))
+	log.Messages = append(log.Messages, snap.Log...)
+
+	galaxy := &Galaxy{
+		Seed:    snap.Galaxy.Seed,
+		Sectors: make(map[SectorCoord]*Sector),
+		Current: snap.Galaxy.Current,
+	}
+	for i := range snap.Galaxy.Sectors {
+		sec, err := restoreSector(&snap.Galaxy.Sectors[i])
+		if err != nil {
+			return nil, fmt.Errorf("sector %s: %w", snap.Galaxy.Sectors[i].Coord, err)
+		}
+		galaxy.Sectors[sec.Coord] = sec
+	}
+	sector := galaxy.Sectors[galaxy.Current]
+	if sector == nil {
+		return nil, fmt.Errorf("current sector %s not in save", galaxy.Current)
+	}
+
+	s := &Sim{
+		ECS:            w,
+		Grid:           snap.Grid,
+		Layout:         layout,
+		Resources:      snap.Resources,
+		Needs:          snap.Needs,
+		Injuries:       snap.Injuries,
+		Log:            log,
+		Ticks:          snap.Ticks,
+		Galaxy:         galaxy,
+		Sector:         sector,
+		Skills:         snap.Skills,
+		Discovery:      snap.Discovery,
+		ActiveEpisode:  snap.ActiveEpisode,
+		Jump:           snap.Jump,
+		Sleep:          snap.Sleep,
+		Missions:       snap.Missions,
+		Reputation:     snap.Reputation,
+		OrbitPlanetIdx: snap.OrbitPlanetIdx,
+		ActiveSurface:  snap.ActiveSurface,
+		Outside:        snap.Outside,
+		Prologue:       snap.Prologue,
+		Goal:           snap.Goal,
+		GoalTarget:     snap.GoalTarget,
+		PlayerDead:     snap.PlayerDead,
+		DeathReason:    snap.DeathReason,
+		killedBy:       snap.KilledBy,
+		Victory:        snap.Victory,
+		VictoryText:    snap.VictoryText,
+		player:         player,
+		posMap:         posMap,
+		creatureMap:    ecs.NewMap2[Position, Creature](w),
+		creatureFilter: ecs.NewFilter2[Position, Creature](w),
+	}
+	s.Resources.Matter.bind(s.Grid)
+	s.initAir(snap.Air)
+	for i := range snap.Creatures {
+		c := &snap.Creatures[i]
+		s.creatureMap.NewEntity(&c.Position, &c.Creature)
+	}
+
+	if ps := snap.PrologueSurface; ps != nil {
+		if ps.Surface == nil {
+			return nil, fmt.Errorf("prologue snapshot is missing its surface")
+		}
+		s.PrologueSurface = &PrologueSurface{
+			SurfaceMap:     ps.Surface,
+			Scenario:       s.Prologue,
+			ObjectivesLeft: ps.ObjectivesLeft,
+			FuelFound:      ps.FuelFound,
+			PartsFound:     ps.PartsFound,
+			PowerFound:     ps.PowerFound,
+		}
+		if snap.OnPrologueSurface {
+			s.ActiveSurface = ps.Surface
+		}
+	}
+
+	sm := s.Sector.Systems[s.Sector.CurrentSystem].Map
+	if h := snap.PendingHail; h != nil {
+		ship := objectAt(sm, h.ShipIdx)
+		if ship == nil {
+			return nil, fmt.Errorf("pending hail ship %d not found", h.ShipIdx)
+		}
+		s.PendingHail = &HailState{Ship: ship, TicksLeft: h.TicksLeft}
+	}
+	if e := snap.ActiveEncounter; e != nil {
+		enc := e.EncounterState
+		enc.ShipObj = objectAt(sm, e.ShipIdx)
+		s.ActiveEncounter = &enc
+	}
+	return s, nil
+}
+
+// objectIndex returns the index of obj in sm.Objects, or -1.
+func objectIndex(sm *SystemMap, obj *SpaceObject) int {
+	if sm == nil || obj == nil {
+		return -1
+	}
+	for i := range sm.Objects {
+		if &sm.Objects[i] == obj {
+			return i
+		}
+	}
+	return -1
+}
+
+// objectAt returns a pointer to sm.Objects[idx], or nil if out of range.
+func objectAt(sm *SystemMap, idx int) *SpaceObject {
+	if sm == nil || idx < 0 || idx >= len(sm.Objects) {
+		return nil
+	}
+	return &sm.Objects[idx]
+}
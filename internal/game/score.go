@@ -0,0 +1,134 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// A run ends in death or victory and is scored from what the player
+// achieved. The hall of fame keeps the best score for each seed and goal.
+
+// ScoreLine is one row of a run's score: what was counted and what it was worth.
+type ScoreLine struct {
+	Label  string `json:"label"`
+	Count  int    `json:"count"`
+	Points int    `json:"points"`
+}
+
+// RunSummary is how a run ended and what it scored.
+type RunSummary struct {
+	Seed    int64       `json:"seed"`
+	Goal    string      `json:"goal"`
+	Victory bool        `json:"victory"`
+	Ending  string      `json:"ending"` // death reason or victory text
+	Ticks   uint64      `json:"ticks"`
+	Lines   []ScoreLine `json:"lines"`
+	Score   int         `json:"score"`
+	Date    string      `json:"date,omitempty"` // set by the caller; the Sim has no wall clock
+}
+
+// Points per unit of each scored achievement.
+const (
+	pointsPerHour    = 2
+	pointsPerSystem  = 50
+	pointsPerScan    = 20
+	pointsPerStation = 30
+	pointsPerEpisode = 75
+	pointsPerClue    = 200
+	creditsPerPoint  = 10
+	pointsPerLevel   = 25 // per skill level above the first
+	pointsForVictory = 1000
+)
+
+// SummarizeRun scores the run so far.
+func SummarizeRun(s *Sim) RunSummary {
+	d := s.Discovery
+	levels := 0
+	for id := range SkillCount {
+		levels += s.Skills.Level(id) - 1
+	}
+	hours := int(s.Ticks / TicksPerHour)
+
+	sum := RunSummary{
+		Seed:    s.Galaxy.Seed,
+		Goal:    GoalName(s.Goal),
+		Victory: s.Victory,
+		Ending:  s.DeathReason,
+		Ticks:   s.Ticks,
+		Lines: []ScoreLine{
+			{"Hours survived", hours, hours * pointsPerHour},
+			{"Systems visited", d.TotalSystemsVisited, d.TotalSystemsVisited * pointsPerSystem},
+			{"Planets scanned", d.TotalScans, d.TotalScans * pointsPerScan},
+			{"Stations docked", d.TotalStationsDocked, d.TotalStationsDocked * pointsPerStation},
+			{"Episodes completed", d.EpisodesCompleted, d.EpisodesCompleted * pointsPerEpisode},
+			{"Monkey Lion clues", d.MLCluesFound, d.MLCluesFound * pointsPerClue},
+			{"Credits", s.Resources.Credits, s.Resources.Credits / creditsPerPoint},
+			{"Skill levels gained", levels, levels * pointsPerLevel},
+		},
+	}
+	switch {
+	case s.Victory:
+		sum.Ending = s.VictoryText
+		sum.Lines = append(sum.Lines, ScoreLine{"Goal reached", 1, pointsForVictory})
+	case !s.PlayerDead:
+		sum.Ending = "Still out there."
+	}
+	for _, l := range sum.Lines {
+		sum.Score += l.Points
+	}
+	return sum
+}
+
+// hallOfFameSize is how many runs the hall of fame keeps.
+const hallOfFameSize = 20
+
+// HallOfFame is the best runs played on this machine, highest score first.
+type HallOfFame struct {
+	Runs []RunSummary `json:"runs"`
+}
+
+// ReadHallOfFame parses a hall of fame file.
+func ReadHallOfFame(r io.Reader) (*HallOfFame, error) {
+	var h HallOfFame
+	if err := json.NewDecoder(r).Decode(&h); err != nil {
+		return nil, fmt.Errorf("read hall of fame: %w", err)
+	}
+	return &h, nil
+}
+
+// Write stores the hall of fame as indented JSON.
+func (h *HallOfFame) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(h); err != nil {
+		return fmt.Errorf("write hall of fame: %w", err)
+	}
+	return nil
+}
+
+// Add enters a run, keeping only the best score for its seed and goal.
+// Returns the run's rank from 1, or 0 if it didn't make the list.
+func (h *HallOfFame) Add(run RunSummary) int {
+	for i, old := range h.Runs {
+		if old.Seed == run.Seed && old.Goal == run.Goal {
+			if old.Score >= run.Score {
+				return 0
+			}
+			h.Runs = append(h.Runs[:i], h.Runs[i+1:]...)
+			break
+		}
+	}
+	h.Runs = append(h.Runs, run)
+	sort.SliceStable(h.Runs, func(i, j int) bool { return h.Runs[i].Score > h.Runs[j].Score })
+	if len(h.Runs) > hallOfFameSize {
+		h.Runs = h.Runs[:hallOfFameSize]
+	}
+	for i := range h.Runs {
+		if h.Runs[i].Seed == run.Seed && h.Runs[i].Goal == run.Goal {
+			return i + 1
+		}
+	}
+	return 0
+}
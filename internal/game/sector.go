@@ -22,6 +22,7 @@ type StarSystem struct {
 	X, Y    int // position on the sector map grid
 	Type    StarType
 	Visited bool
+	Gate    GateDir    // edge this system leads across to the neighbouring sector, if any
 	Map     *SystemMap // nil until first visit, then lazy-generated
 }
 
@@ -31,6 +32,8 @@ type Sector struct {
 	CurrentSystem int // index — where the player is now
 	CursorSystem  int // index — where the cursor is pointing
 	Seed          int64
+	Factions      []Faction   // generated from Seed; ships and stations index into it
+	Coord         SectorCoord // position in the galaxy
 }
 
 // Sector map bounds (within the 80x45 grid)
@@ -42,13 +45,6 @@ const (
 	minStarDist = 4 // minimum distance between stars
 )
 
-var starNames = []string{
-	"Vega Prime", "Kepler's Rest", "Nyx", "Caelum", "Draconis",
-	"Forge", "Hadal Deep", "Meridian", "Obsidian", "Solis",
-	"Tempest", "Umbra", "Zenith", "Arcturus", "Cygnus",
-	"Eridani", "Lyra", "Procyon", "Rigel", "Sirius",
-}
-
 // NewSector generates a sector from a seed.
 func NewSector(seed int64) *Sector {
 	rng := rand.New(rand.NewPCG(uint64(seed), 0))
@@ -56,8 +52,8 @@ func NewSector(seed int64) *Sector {
 	numSystems := 12 + rng.IntN(4) // 12-15
 
 	// Shuffle names
-	names := make([]string, len(starNames))
-	copy(names, starNames)
+	names := make([]string, len(content.StarNames))
+	copy(names, content.StarNames)
 	rng.Shuffle(len(names), func(i, j int) {
 		names[i], names[j] = names[j], names[i]
 	})
@@ -98,6 +94,7 @@ func NewSector(seed int64) *Sector {
 		CurrentSystem: 0,
 		CursorSystem:  0,
 		Seed:          seed,
+		Factions:      GenerateFactions(seed),
 	}
 }
 
@@ -121,14 +118,26 @@ func (s *Sector) DistanceBetween(a, b int) float64 {
 	return math.Sqrt(dx*dx + dy*dy)
 }
 
-// EnergyCostTo returns the energy cost to travel from current system to target.
-func (s *Sector) EnergyCostTo(target int) int {
+// Jump fuel cost: a fixed charge to open the jump plus a charge per unit of
+// distance. The farthest nearest-neighbour in a sector is ~23 units, so a full
+// tank always reaches at least one other star.
+const (
+	jumpFuelBase    = 60
+	jumpFuelPerUnit = 1.5
+)
+
+// FuelCostTo returns the jump fuel needed to travel from current system to target.
+func (s *Sector) FuelCostTo(target int) int {
 	dist := s.DistanceBetween(s.CurrentSystem, target)
-	cost := int(dist * 1.5)
-	if cost < 5 {
-		cost = 5
-	}
-	return cost
+	return jumpFuelBase + int(math.Ceil(dist*jumpFuelPerUnit))
+}
+
+// gateLaneDist is the length of the charted lane between two sector gates.
+const gateLaneDist = 16.0
+
+// GateFuelCost returns the jump fuel needed to cross a gate into the next sector.
+func GateFuelCost() int {
+	return jumpFuelBase + int(math.Ceil(gateLaneDist*jumpFuelPerUnit))
 }
 
 // NearestInDirection returns the index of the nearest star from the cursor
@@ -175,7 +184,7 @@ func (s *Sector) EnsureSystemMap(idx int) {
 		return
 	}
 	seed := s.Seed*1000 + int64(idx)
-	sys.Map = GenerateSystemMap(seed, sys.Type, sys.Name)
+	sys.Map = GenerateSystemMap(seed, sys.Type, sys.Name, s.Factions)
 }
 
 // CurrentSystemMap returns the system map for the current star system, generating it if needed.
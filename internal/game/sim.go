@@ -9,6 +9,10 @@ import (
 
 // Time scale: 1 game day = 20 real minutes = 72,000 ticks at 60 TPS.
 // 1 game hour = 50 real seconds = 3,000 ticks.
+const (
+	TicksPerHour = 3000
+	TicksPerDay  = 24 * TicksPerHour
+)
 
 // Tick intervals (at 60 TPS)
 const (
@@ -34,9 +38,11 @@ type Sim struct {
 	Layout    *world.ShipLayout
 	Resources Resources
 	Needs     PlayerNeeds
+	Injuries  Injuries
 	Log       *MessageLog
 	Ticks     uint64
-	Sector    *Sector
+	Galaxy    *Galaxy
+	Sector    *Sector // the galaxy's current sector
 
 	// Equipment state is tracked per-tile in Ship.Grid.EquipmentOn
 
@@ -51,11 +57,29 @@ type Sim struct {
 	// Episode state
 	ActiveEpisode *EpisodeState
 
+	// FTL jump state — non-nil while the jump drive spools up
+	Jump *JumpState
+
+	// Sleep state — non-nil while the player sleeps
+	Sleep *SleepState
+
+	// Accepted station contracts
+	Missions []Mission
+
+	// Standing with each faction by name. Factions with the same name in
+	// different sectors are the same organisation.
+	Reputation map[string]int
+
 	// Orbit state — when the player is orbiting a planet from the system map
 	OrbitPlanetIdx int // index into SystemMap.Objects, or -1 if not orbiting
 
 	// Surface exploration state
 	ActiveSurface *SurfaceMap // nil when not on surface
+	Outside       bool        // player is out on the surface rather than aboard
+
+	// Life support: the ship's rooms, derived from Grid, and the air in each
+	Rooms *world.RoomMap
+	Air   []RoomAir
 
 	// Prologue state — starting scenario
 	Prologue        *PrologueScenario // generated starting scenario
@@ -68,18 +92,32 @@ type Sim struct {
 	CargoActivated  bool // set when player uses cargo console
 	ScanActivated   bool // set when player uses science console
 	CommsActivated  bool // set when player uses viewscreen with pending hail
+	JumpCompleted   bool // set when an FTL jump arrives at its destination
+	Intercepted     bool // set when a chasing pirate runs the shuttle down
+
+	// Run goal, and the system it leads to once known
+	Goal       Goal
+	GoalTarget *GoalTarget
 
 	// Game over state
 	PlayerDead  bool
 	DeathReason string
-
-	player ecs.Entity
-	posMap *ecs.Map[Position]
+	Victory     bool
+	VictoryText string
+
+	player         ecs.Entity
+	posMap         *ecs.Map[Position]
+	creatureMap    *ecs.Map2[Position, Creature]
+	creatureFilter *ecs.Filter2[Position, Creature]
+	creatures      []ecs.Entity // scratch for collectCreatures
+	killedBy       string       // the creature that landed the killing blow
+	attacks        uint64       // player attacks on creatures this tick, see attackRoll
+	shipSight      []bool       // tiles the player can see aboard while the lights are out
 }
 
-// IsGameOver returns true if the player has died.
+// IsGameOver returns true if the player has died or reached their goal.
 func (s *Sim) IsGameOver() bool {
-	return s.PlayerDead
+	return s.PlayerDead || s.Victory
 }
 
 // NewSim creates a simulation from a ship layout.
@@ -99,7 +137,8 @@ func NewSim(layout *world.ShipLayout) *Sim {
 	log.Add("All systems online. Generator, recycler running.", MsgInfo)
 	log.Add("You should probably find the toilet.", MsgWarning)
 
-	sector := NewSector(42)
+	galaxy := NewGalaxy(42)
+	sector := galaxy.CurrentSector()
 	disc := NewDiscoveryLog()
 	// Mark starting system and star type as discovered
 	disc.SystemsVisited[0] = true
@@ -107,22 +146,23 @@ func NewSim(layout *world.ShipLayout) *Sim {
 	disc.StarTypesSeen[int(sector.Systems[0].Type)] = true
 	disc.TotalStarTypesSeen = 1
 
-	// Count cargo pads from the actual ship layout
-	cargoPadCount := grid.CountEquipment(world.EquipCargoTile)
-
 	s := &Sim{
 		ECS:            w,
 		Grid:           grid,
 		Layout:         layout,
-		Resources:      NewShuttleResources(cargoPadCount),
-		Needs:          PlayerNeeds{Hunger: 40, Thirst: 30, Hygiene: 20, Health: 100, MaxHealth: 100},
+		Resources:      NewShuttleResources(grid),
+		Needs:          PlayerNeeds{Hunger: 40, Thirst: 30, Hygiene: 20, Fatigue: 20, Health: 100, MaxHealth: 100},
 		Log:            log,
+		Galaxy:         galaxy,
 		Sector:         sector,
 		Discovery:      disc,
 		OrbitPlanetIdx: -1,
 		player:         player,
 		posMap:         posMap,
+		creatureMap:    ecs.NewMap2[Position, Creature](w),
+		creatureFilter: ecs.NewFilter2[Position, Creature](w),
 	}
+	s.initAir(nil)
 	// Turn on all toggleable equipment by default
 	s.Grid.SetAllEquipmentState(true)
 	return s
@@ -153,32 +193,35 @@ func NewSimWithPrologue(layout *world.ShipLayout, seed int64) *Sim {
 	log.Add(strandingDescriptions[prologue.Stranding], MsgInfo)
 	log.Add(prologue.Objective, MsgWarning)
 
-	sector := NewSector(seed)
+	galaxy := NewGalaxy(seed)
+	sector := galaxy.CurrentSector()
 	disc := NewDiscoveryLog()
 	// Don't mark any system as visited yet - we're stranded before reaching one
 	disc.TotalSystemsVisited = 0
 
-	// Count cargo pads from the actual ship layout
-	cargoPadCount := grid.CountEquipment(world.EquipCargoTile)
-
 	// Equipment defaults to off (EquipmentOn = false in tiles)
 	// Shuttle is broken - nothing works until prologue complete
 	s := &Sim{
 		ECS:             w,
 		Grid:            grid,
 		Layout:          layout,
-		Resources:       NewShuttleResources(cargoPadCount),
-		Needs:           PlayerNeeds{Hunger: 40, Thirst: 30, Hygiene: 20, Health: 100, MaxHealth: 100},
+		Resources:       NewShuttleResources(grid),
+		Needs:           PlayerNeeds{Hunger: 40, Thirst: 30, Hygiene: 20, Fatigue: 20, Health: 100, MaxHealth: 100},
 		Log:             log,
+		Galaxy:          galaxy,
 		Sector:          sector,
 		Discovery:       disc,
 		OrbitPlanetIdx:  -1,
 		Prologue:        prologue,
 		PrologueSurface: prologueSurface,
 		ActiveSurface:   prologueSurface.SurfaceMap, // start on surface
+		Outside:         true,
 		player:          player,
 		posMap:          posMap,
+		creatureMap:     ecs.NewMap2[Position, Creature](w),
+		creatureFilter:  ecs.NewFilter2[Position, Creature](w),
 	}
+	s.initAir(nil)
 	// Shuttle is dead - no power
 	s.Resources.Energy = 0
 	return s
@@ -278,9 +321,10 @@ func (s *Sim) CompletePrologue() {
 	// Clear prologue state
 	s.PrologueSurface = nil
 	s.ActiveSurface = nil
+	s.Outside = false
 
 	// Mark first system as visited
-	s.Discovery.SystemsVisited[s.Sector.CurrentSystem] = true
+	s.Discovery.SystemsVisited[s.Sector.SystemID(s.Sector.CurrentSystem)] = true
 	s.Discovery.TotalSystemsVisited = 1
 	s.Discovery.StarTypesSeen[int(s.Sector.Systems[s.Sector.CurrentSystem].Type)] = true
 	s.Discovery.TotalStarTypesSeen = 1
@@ -314,18 +358,31 @@ func (s *Sim) TryMovePlayer(dx, dy int) bool {
 
 // Tick advances the simulation by one step.
 func (s *Sim) Tick() {
-	if s.PlayerDead {
-		return // no more simulation after death
+	if s.IsGameOver() {
+		return // no more simulation after death or victory
 	}
 
+	criticals := s.Log.criticals
 	s.Ticks++
+	s.attacks = 0
 	s.tickPower()
+	s.tickJump()
+	s.tickWear()
 	s.tickGenerator()
 	s.tickRecycler()
 	s.tickBody()
 	s.tickNeeds()
+	s.tickFatigue()
+	s.tickInjuries()
+	s.tickAir()
+	s.tickExposure()
+	s.tickSight()
+	s.tickCreatures()
+	s.tickOrbits()
 	s.tickSystemMapNPCs()
 	s.tickSystemMapShuttle()
+	s.tickChase()
+	s.tickMonkeyLion()
 	s.tickHails()
 	if s.Ticks%warningInterval == 0 {
 		s.checkWarnings()
@@ -334,15 +391,26 @@ func (s *Sim) Tick() {
 	// Check for player death
 	if s.Needs.IsDead() {
 		s.PlayerDead = true
-		if s.Needs.Thirst >= 100 {
+		if s.killedBy != "" {
+			s.DeathReason = fmt.Sprintf("You were killed by a %s.", s.killedBy)
+		} else if cause := s.ExposureHazard(); cause != "" {
+			s.DeathReason = airDeathReasons[cause]
+		} else if s.Needs.Thirst >= 100 {
 			s.DeathReason = "You died of dehydration."
+		} else if k, ok := s.Injuries.Serious(); ok {
+			s.DeathReason = injuryDeathReasons[k]
 		} else if s.Needs.MaxHealth <= 0 {
 			s.DeathReason = "You wasted away from starvation."
+		} else if cause := s.AirHazard(); cause != "" {
+			s.DeathReason = airDeathReasons[cause]
 		} else {
 			s.DeathReason = "You died."
 		}
 		s.Log.Add(s.DeathReason, MsgCritical)
+	} else {
+		s.checkGoal()
 	}
+	s.checkSleepInterrupts(criticals)
 }
 
 // Power check interval (every 1 second at 60 TPS)
@@ -393,6 +461,12 @@ func (s *Sim) PowerReserved() int {
 			reserved += eq.PowerCost
 		}
 	}
+	// A spooling jump drive holds its full cost until it fires
+	if s.Jump != nil {
+		if drive := s.JumpDrive(); drive != nil {
+			reserved += drive.PowerCost
+		}
+	}
 	return reserved
 }
 
@@ -402,49 +476,42 @@ func (s *Sim) PowerAvailable() int {
 }
 
 func (s *Sim) tickGenerator() {
-	if !s.Grid.AnyEquipmentOn(world.EquipGenerator) {
+	gen := s.bestRunning(world.EquipGenerator)
+	if gen == nil {
 		return
 	}
 	// Generator needs at least 1 energy to run (bootstrap problem)
 	if s.Resources.Energy < 1 {
 		return
 	}
-	if s.Ticks%generatorInterval == 0 {
+	// A worn generator produces more slowly
+	if s.Ticks%scaledInterval(generatorInterval, gen.Efficiency) == 0 {
 		if s.Resources.Energy < s.Resources.MaxEnergy {
 			s.Resources.Energy++
 		}
 	}
 }
 
+// tickRecycler runs every working recycler in the matter network. Each one
+// cycles independently, so extra recyclers clean matter faster.
 func (s *Sim) tickRecycler() {
-	if !s.Grid.AnyEquipmentOn(world.EquipMatterRecycler) {
-		return
-	}
-	r := &s.Resources
-	rc := &r.Recycler
-
-	// Intake phase: pull dirty matter from ship pools into recycler buffer
-	if s.Ticks%recyclerIntakeInterval == 0 {
-		if r.Water.Dirty > 0 && rc.WaterBuffer < rc.Capacity {
-			r.Water.Dirty--
-			rc.WaterBuffer++
-		}
-		if r.Organic.Dirty > 0 && rc.OrganicBuffer < rc.Capacity {
-			r.Organic.Dirty--
-			rc.OrganicBuffer++
+	net := &s.Resources.Matter
+	for i := range net.Nodes {
+		node := &net.Nodes[i]
+		if node.Role != CompRecycler || !node.Running() {
+			continue
 		}
-	}
+		eff := node.equip.Efficiency // a worn recycler has lower throughput
 
-	// Process phase: convert buffered dirty → clean
-	// Power cost is handled by constant draw reservation (10 power while ON)
-	if s.Ticks%recyclerProcessInterval == 0 {
-		if rc.WaterBuffer > 0 {
-			rc.WaterBuffer--
-			r.Water.Clean++
+		// Intake pulls dirty matter from the tanks into the buffer; process
+		// turns it clean. Power cost is handled by constant draw reservation.
+		intake := s.Ticks%scaledInterval(recyclerIntakeInterval, eff) == 0
+		process := s.Ticks%scaledInterval(recyclerProcessInterval, eff) == 0
+		if !intake && !process {
+			continue
 		}
-		if rc.OrganicBuffer > 0 {
-			rc.OrganicBuffer--
-			r.Organic.Clean++
+		for _, m := range recycledMatter {
+			net.recycle(i, m, intake, process)
 		}
 	}
 }
@@ -505,8 +572,9 @@ func (s *Sim) tickNeeds() {
 		}
 	}
 
-	// Health regeneration: slowly heal when needs are under control
-	if n.Hunger < 80 && n.Thirst < 80 && n.Health < n.MaxHealth && s.Ticks%healthRegenInterval == 0 {
+	// Health regeneration: slowly heal when needs are under control, the
+	// air is breathable and no injury needs treatment
+	if n.Hunger < 80 && n.Thirst < 80 && n.Health < n.MaxHealth && s.Ticks%healthRegenInterval == 0 && s.AirHazard() == "" && s.ExposureHazard() == "" && !s.seriouslyInjured() {
 		n.Health = min(n.MaxHealth, n.Health+1)
 	}
 
@@ -523,7 +591,8 @@ func (s *Sim) tickSystemMapNPCs() {
 
 func (s *Sim) tickSystemMapShuttle() {
 	sm := s.Sector.Systems[s.Sector.CurrentSystem].Map
-	if sm != nil {
+	if sm != nil && !s.holdOrbit(sm) {
+		sm.pullShuttle()
 		sm.Shuttle.Tick()
 		sm.Shuttle.ClampToBounds(sm.Width, sm.Height)
 	}
@@ -539,15 +608,17 @@ func (s *Sim) checkWarnings() {
 		s.Log.Add("POWER DEPLETED. Recyclers offline.", MsgCritical)
 	}
 
-	if r.Water.Clean <= 15 && r.Water.Clean > 0 {
-		s.Log.Add(fmt.Sprintf("Clean water low: %d.", r.Water.Clean), MsgWarning)
-	} else if r.Water.Clean == 0 {
+	water := r.Matter.Pool(MatterWater)
+	organic := r.Matter.Pool(MatterOrganic)
+	if water.Clean <= 15 && water.Clean > 0 {
+		s.Log.Add(fmt.Sprintf("Clean water low: %d.", water.Clean), MsgWarning)
+	} else if water.Clean == 0 {
 		s.Log.Add("NO CLEAN WATER. Dehydration imminent.", MsgCritical)
 	}
 
-	if r.Organic.Clean <= 15 && r.Organic.Clean > 0 {
-		s.Log.Add(fmt.Sprintf("Clean organics low: %d.", r.Organic.Clean), MsgWarning)
-	} else if r.Organic.Clean == 0 {
+	if organic.Clean <= 15 && organic.Clean > 0 {
+		s.Log.Add(fmt.Sprintf("Clean organics low: %d.", organic.Clean), MsgWarning)
+	} else if organic.Clean == 0 {
 		s.Log.Add("NO CLEAN ORGANICS. Starvation imminent.", MsgCritical)
 	}
 
@@ -570,6 +641,45 @@ func (s *Sim) checkWarnings() {
 	if n.Hygiene >= 80 {
 		s.Log.Add("You reek. Consider a shower.", MsgWarning)
 	}
+
+	if k, ok := s.Injuries.Serious(); ok {
+		s.Log.Add(fmt.Sprintf("%s serious (%d). Get to the med bay.", InjuryName(k), s.Injuries[k]), MsgCritical)
+	}
+
+	if s.exposed() == ExposeToxic {
+		if r.SuitO2 == 0 {
+			s.Log.Add("SUIT O2 EMPTY. Get back to the shuttle!", MsgCritical)
+		} else if r.SuitO2 <= 25 && r.CargoOf(CargoO2Canisters) == 0 {
+			s.Log.Add(fmt.Sprintf("Suit O2 low: %d%%. No spare canisters.", r.SuitO2), MsgWarning)
+		}
+	}
+	if abs(n.Temp) >= tempHarm {
+		s.Log.Add(tempWarning(n.Temp), MsgCritical)
+	}
+
+	if s.Sleep == nil {
+		if n.Fatigue >= FatigueExhausted {
+			s.Log.Add("You're exhausted. Find a bed before you drop.", MsgCritical)
+		} else if n.Fatigue >= FatigueTired {
+			s.Log.Add("Getting tired.", MsgWarning)
+		}
+	}
+
+	for i, a := range s.Air {
+		if len(a.Holes) > 0 {
+			s.Log.Add(fmt.Sprintf("Hull breach in the %s. Patch it (R) with a tool kit or spare parts.", s.Rooms.Rooms[i].Name), MsgCritical)
+		}
+	}
+	switch s.AirHazard() {
+	case hazardO2:
+		s.Log.Add("Oxygen low. You're getting light-headed.", MsgCritical)
+	case hazardCO2:
+		s.Log.Add("CO2 building up. Check life support.", MsgCritical)
+	case hazardCold:
+		s.Log.Add("Freezing cold in here. Check life support.", MsgCritical)
+	case hazardHeat:
+		s.Log.Add("The heat is unbearable. Check life support.", MsgCritical)
+	}
 }
 
 // Interact handles the player pressing E on the tile they're standing on.
@@ -584,6 +694,13 @@ func (s *Sim) Interact() {
 		s.Log.Add("Nothing to interact with here.", MsgSocial)
 		return
 	}
+	node := r.Matter.NodeAt(px, py) // -1 unless eq is matter equipment
+
+	// Broken equipment does nothing — try to fix it instead
+	if eq.IsBroken() {
+		s.RepairEquipment()
+		return
+	}
 
 	// Check on-use power cost before processing
 	if !eq.CanUse(r.Energy) {
@@ -593,19 +710,20 @@ func (s *Sim) Interact() {
 
 	switch eq.Kind {
 	case world.EquipFoodStation:
-		// Eat: clean organic leaves ship → enters body, hunger drops
-		if r.Organic.Clean < 5 {
+		// Eat: clean organic leaves the tanks → enters body, hunger drops
+		meal := r.Matter.Nodes[node].Throughput
+		if r.Matter.Supply(node, MatterOrganic) < meal {
 			s.Log.Add("Not enough clean organics to dispense a meal.", MsgWarning)
 			return
 		}
-		if r.BodyFullness()+5 > MaxBodyFullness {
+		if r.BodyFullness()+meal > MaxBodyFullness {
 			s.Log.Add("Too full to eat. Use the toilet first.", MsgWarning)
 			return
 		}
 		// Deduct on-use power
-		eq.Use(&r.Energy)
-		r.Organic.Clean -= 5
-		r.BodyOrganic += 5
+		s.useEquipment(eq)
+		r.Matter.Draw(node, MatterOrganic, meal)
+		r.BodyOrganic += meal
 		s.Needs.Hunger = max(s.Needs.Hunger-35, 0)
 		// Eating restores max health lost to starvation
 		if s.Needs.MaxHealth < 100 {
@@ -619,20 +737,22 @@ func (s *Sim) Interact() {
 		}
 
 	case world.EquipDrinkStation:
-		// Drink: clean water leaves ship → enters body, thirst drops
-		if r.Water.Clean < 3 {
-			s.Log.Add(fmt.Sprintf("Not enough clean water. %dc %dd.", r.Water.Clean, r.Water.Dirty), MsgWarning)
+		// Drink: clean water leaves the tanks → enters body, thirst drops
+		drink := r.Matter.Nodes[node].Throughput
+		if r.Matter.Supply(node, MatterWater) < drink {
+			water := r.Matter.Pool(MatterWater)
+			s.Log.Add(fmt.Sprintf("Not enough clean water. %dc %dd.", water.Clean, water.Dirty), MsgWarning)
 			return
 		}
-		if r.BodyFullness()+3 > MaxBodyFullness {
+		if r.BodyFullness()+drink > MaxBodyFullness {
 			s.Log.Add("Too full to drink. Use the toilet first.", MsgWarning)
 			return
 		}
-		eq.Use(&r.Energy)
-		r.Water.Clean -= 3
-		r.BodyWater += 3
+		s.useEquipment(eq)
+		r.Matter.Draw(node, MatterWater, drink)
+		r.BodyWater += drink
 		s.Needs.Thirst = max(s.Needs.Thirst-25, 0)
-		s.Log.Add(fmt.Sprintf("Gulped some recycled water. %dc remaining.", r.Water.Clean), MsgInfo)
+		s.Log.Add(fmt.Sprintf("Gulped some recycled water. %dc remaining.", r.Matter.Supply(node, MatterWater)), MsgInfo)
 		if s.Skills.AddXP(SkillSurvival, 1.5) {
 			LogLevelUp(s.Log, SkillSurvival, s.Skills.Level(SkillSurvival))
 		}
@@ -644,26 +764,29 @@ func (s *Sim) Interact() {
 			s.Log.Add("Nothing to deposit. Efficient.", MsgSocial)
 			return
 		}
-		eq.Use(&r.Energy)
+		s.useEquipment(eq)
 		s.Log.Add(fmt.Sprintf("Waste flushed. +%d dirty organic, +%d dirty water back in system.",
 			r.WasteOrganic, r.WasteWater), MsgInfo)
-		r.Organic.Dirty += r.WasteOrganic
-		r.Water.Dirty += r.WasteWater
+		vented := r.Matter.Store(node, MatterOrganic, r.WasteOrganic, true) +
+			r.Matter.Store(node, MatterWater, r.WasteWater, true)
 		r.WasteOrganic = 0
 		r.WasteWater = 0
+		if vented > 0 {
+			s.Log.Add(fmt.Sprintf("Tanks full. %d units of waste vented to space.", vented), MsgWarning)
+		}
 		if s.Skills.AddXP(SkillSurvival, 0.5) {
 			LogLevelUp(s.Log, SkillSurvival, s.Skills.Level(SkillSurvival))
 		}
 
 	case world.EquipShower:
 		// Uses clean water → dirty water (external, doesn't go through body)
-		if r.Water.Clean < 3 {
+		shower := r.Matter.Nodes[node].Throughput
+		if !r.Matter.Draw(node, MatterWater, shower) {
 			s.Log.Add("Not enough clean water for a shower.", MsgWarning)
 			return
 		}
-		eq.Use(&r.Energy)
-		r.Water.Clean -= 3
-		r.Water.Dirty += 3
+		s.useEquipment(eq)
+		r.Matter.Store(node, MatterWater, shower, true)
 		s.Needs.Hygiene = max(s.Needs.Hygiene-40, 0)
 		s.Log.Add("Quick shower. Refreshing.", MsgInfo)
 		if s.Skills.AddXP(SkillSurvival, 0.5) {
@@ -671,7 +794,7 @@ func (s *Sim) Interact() {
 		}
 
 	case world.EquipBed:
-		s.Log.Add("You rest briefly. The void doesn't care.", MsgSocial)
+		s.GoToSleep(true)
 
 	case world.EquipLocker:
 		s.Log.Add("Storage locker. Empty for now.", MsgSocial)
@@ -681,7 +804,7 @@ func (s *Sim) Interact() {
 			s.Log.Add("Navigation console is powered off. Press T to turn it on.", MsgWarning)
 			return
 		}
-		eq.Use(&r.Energy)
+		s.useEquipment(eq)
 		s.NavActivated = true
 		s.Log.Add("Navigation console activated.", MsgInfo)
 
@@ -690,10 +813,10 @@ func (s *Sim) Interact() {
 			s.Log.Add("Pilot console is powered off. Press T to turn it on.", MsgWarning)
 			return
 		}
-		eq.Use(&r.Energy)
+		s.useEquipment(eq)
 		if s.IsOrbiting() {
 			// Check if planet has a scanned POI for landing
-			scanKey := ScanKey(s.Sector.CurrentSystem, s.OrbitPlanetIdx)
+			scanKey := ScanKey(s.Sector.SystemID(s.Sector.CurrentSystem), s.OrbitPlanetIdx)
 			if scan, ok := s.Discovery.PlanetsScanned[scanKey]; ok && scan.POI != "" {
 				// POI exists — land on surface
 				s.LandOnPlanet()
@@ -713,7 +836,7 @@ func (s *Sim) Interact() {
 			s.Log.Add("Science console is powered off. Press T to turn it on.", MsgWarning)
 			return
 		}
-		eq.Use(&r.Energy)
+		s.useEquipment(eq)
 		s.ScanActivated = true
 		if s.IsOrbiting() {
 			sm := s.Sector.CurrentSystemMap()
@@ -725,13 +848,13 @@ func (s *Sim) Interact() {
 
 	case world.EquipIncinerator:
 		// Incinerator shows fuel status, actual conversion happens via cargo console
-		eq.Use(&r.Energy)
+		s.useEquipment(eq)
 		s.Log.Add(fmt.Sprintf("Fuel tank: %d/%d", s.Resources.JumpFuel, s.Resources.MaxJumpFuel), MsgInfo)
 		s.Log.Add("Use cargo console to incinerate cargo for fuel.", MsgInfo)
 
 	case world.EquipFuelTank:
 		// Try to fill with a pack from inventory
-		if filled, packName := s.Resources.TryFillTank(world.EquipFuelTank); filled > 0 {
+		if filled, packName := s.Resources.TryFillTank(world.EquipFuelTank, px, py); filled > 0 {
 			s.Log.Add(fmt.Sprintf("Used %s. +%d fuel.", packName, filled), MsgDiscovery)
 			// Prologue objective tracking
 			if s.InPrologue() && !s.PrologueSurface.FuelFound {
@@ -741,8 +864,34 @@ func (s *Sim) Interact() {
 		}
 		s.Log.Add(fmt.Sprintf("Fuel tank: %d/%d.", r.JumpFuel, r.MaxJumpFuel), MsgInfo)
 
+	case world.EquipJumpDrive:
+		s.Log.Add(fmt.Sprintf("Jump drive: condition %d%%. Needs %d free power to jump.", eq.Condition, eq.PowerCost), MsgInfo)
+		s.Log.Add(fmt.Sprintf("Fuel tank: %d/%d. Power free: %d.", r.JumpFuel, r.MaxJumpFuel, s.PowerAvailable()), MsgInfo)
+
+	case world.EquipWeapon:
+		state := "safe"
+		if eq.On {
+			state = "armed"
+		}
+		s.Log.Add(fmt.Sprintf("Pulse cannon: %s, condition %d%%. Press T to arm.", state, eq.Condition), MsgInfo)
+
+	case world.EquipShield:
+		state := "down"
+		if eq.On {
+			state = "up"
+		}
+		s.Log.Add(fmt.Sprintf("Shield emitter: %s, condition %d%%. Press T to raise.", state, eq.Condition), MsgInfo)
+
+	case world.EquipLifeSupport:
+		state := "offline"
+		if eq.On {
+			state = "running"
+		}
+		s.Log.Add(fmt.Sprintf("Life support: %s, condition %d%%.", state, eq.Condition), MsgInfo)
+		s.reportAir()
+
 	case world.EquipMedical:
-		s.Log.Add("Medical station. Not yet operational.", MsgInfo)
+		s.treatAtMedBay(eq)
 
 	case world.EquipEngine:
 		// During prologue, can install spare parts here
@@ -760,7 +909,7 @@ func (s *Sim) Interact() {
 			if s.Grid.IsEquipmentOn(px, py) {
 				status = "ON"
 			}
-			s.Log.Add(fmt.Sprintf("Engine [%s]. Provides thrust.", status), MsgInfo)
+			s.Log.Add(fmt.Sprintf("Engine [%s] %d%%. Provides thrust.", status, eq.Condition), MsgInfo)
 		}
 		s.Skills.AddXP(SkillEngineering, 0.5)
 
@@ -769,7 +918,8 @@ func (s *Sim) Interact() {
 		if s.Grid.IsEquipmentOn(px, py) {
 			status = "ON"
 		}
-		s.Log.Add(fmt.Sprintf("Generator [%s]. Produces 1 energy/sec.", status), MsgInfo)
+		s.Log.Add(fmt.Sprintf("Generator [%s] %d%%. Produces 1 energy every %.1f sec.",
+			status, eq.Condition, float64(scaledInterval(generatorInterval, eq.Efficiency))/60), MsgInfo)
 		s.Skills.AddXP(SkillEngineering, 0.5)
 
 	case world.EquipCargoTransporter:
@@ -781,7 +931,7 @@ func (s *Sim) Interact() {
 
 	case world.EquipPowerCell:
 		// Try to fill with a pack from inventory
-		if filled, packName := s.Resources.TryFillTank(world.EquipPowerCell); filled > 0 {
+		if filled, packName := s.Resources.TryFillTank(world.EquipPowerCell, px, py); filled > 0 {
 			s.Log.Add(fmt.Sprintf("Used %s. +%d energy.", packName, filled), MsgDiscovery)
 			// Prologue: power objective complete when we have enough to run generator (10)
 			if s.InPrologue() && !s.PrologueSurface.PowerFound {
@@ -798,32 +948,34 @@ func (s *Sim) Interact() {
 
 	case world.EquipOrganicTank:
 		// Try to fill with a pack from inventory
-		if filled, packName := s.Resources.TryFillTank(world.EquipOrganicTank); filled > 0 {
+		if filled, packName := s.Resources.TryFillTank(world.EquipOrganicTank, px, py); filled > 0 {
 			s.Log.Add(fmt.Sprintf("Used %s. +%d organics.", packName, filled), MsgDiscovery)
 		}
-		s.Log.Add(fmt.Sprintf("Organic tank: %dc %dd. Digesting: %d, waste: %d.",
-			r.Organic.Clean, r.Organic.Dirty, r.BodyOrganic, r.WasteOrganic), MsgInfo)
+		tank := r.Matter.Nodes[node].Tank
+		s.Log.Add(fmt.Sprintf("Organic tank: %dc %dd / %d. Digesting: %d, waste: %d.",
+			tank.Clean, tank.Dirty, tank.Capacity, r.BodyOrganic, r.WasteOrganic), MsgInfo)
 
 	case world.EquipWaterTank:
 		// Try to fill with a pack from inventory
-		if filled, packName := s.Resources.TryFillTank(world.EquipWaterTank); filled > 0 {
+		if filled, packName := s.Resources.TryFillTank(world.EquipWaterTank, px, py); filled > 0 {
 			s.Log.Add(fmt.Sprintf("Used %s. +%d water.", packName, filled), MsgDiscovery)
 		}
-		s.Log.Add(fmt.Sprintf("Water tank: %dc %dd. Body: %d, waste: %d.",
-			r.Water.Clean, r.Water.Dirty, r.BodyWater, r.WasteWater), MsgInfo)
+		tank := r.Matter.Nodes[node].Tank
+		s.Log.Add(fmt.Sprintf("Water tank: %dc %dd / %d. Body: %d, waste: %d.",
+			tank.Clean, tank.Dirty, tank.Capacity, r.BodyWater, r.WasteWater), MsgInfo)
 
 	case world.EquipMatterRecycler:
 		status := "OFF"
 		if s.Grid.IsEquipmentOn(px, py) {
 			status = "ON"
 		}
-		rc := &r.Recycler
-		s.Log.Add(fmt.Sprintf("Recycler [%s]. Buffer: %dw %do / %d cap.",
-			status, rc.WaterBuffer, rc.OrganicBuffer, rc.Capacity), MsgInfo)
+		rc := &r.Matter.Nodes[node].Buffer
+		s.Log.Add(fmt.Sprintf("Recycler [%s] %d%%. Buffer: %dw %do / %d cap.",
+			status, eq.Condition, rc.WaterBuffer, rc.OrganicBuffer, rc.Capacity), MsgInfo)
 		s.Skills.AddXP(SkillEngineering, 0.5)
 
 	case world.EquipViewscreen:
-		eq.Use(&r.Energy)
+		s.useEquipment(eq)
 		if s.PendingHail != nil {
 			s.CommsActivated = true
 			s.Log.Add(fmt.Sprintf("Answering hail from %s.", s.PendingHail.Ship.Name), MsgInfo)
@@ -837,7 +989,7 @@ func (s *Sim) Interact() {
 			s.Log.Add("Cargo console is powered off. Press T to turn it on.", MsgWarning)
 			return
 		}
-		eq.Use(&r.Energy)
+		s.useEquipment(eq)
 		s.CargoActivated = true
 		s.Log.Add("Cargo console activated.", MsgInfo)
 
@@ -845,7 +997,11 @@ func (s *Sim) Interact() {
 		s.Log.Add("Cargo pad. Empty.", MsgInfo)
 
 	case world.EquipDoor:
-		// E opens/closes the door
+		// E opens/closes the door; outer doors won't open onto vacuum
+		if !eq.Open && s.ActiveSurface == nil && s.isOuterDoor(px, py) {
+			s.Log.Add("Outer door. Safety interlock: won't open in vacuum.", MsgWarning)
+			return
+		}
 		eq.Open = !eq.Open
 		if eq.Open {
 			s.Log.Add("Door opened.", MsgInfo)
@@ -872,6 +1028,12 @@ func (s *Sim) ToggleEquipment() {
 		return
 	}
 
+	// Broken equipment stays off until repaired
+	if !eq.On && eq.IsBroken() {
+		s.Log.Add(fmt.Sprintf("%s is broken. Repair it first (R).", eq.Name()), MsgWarning)
+		return
+	}
+
 	// Check if trying to turn ON equipment without power
 	if !eq.On && s.Resources.Energy < 1 {
 		s.Log.Add("No power. Can't turn on equipment.", MsgWarning)
@@ -975,6 +1137,33 @@ func (s *Sim) ToggleEquipment() {
 			s.Log.Add("Cargo console powered down.", MsgInfo)
 		}
 
+	case world.EquipWeapon:
+		eq.On = !eq.On
+		if eq.On {
+			s.Log.Add("Pulse cannon armed.", MsgWarning)
+		} else {
+			s.Log.Add("Pulse cannon safed.", MsgInfo)
+		}
+
+	case world.EquipShield:
+		eq.On = !eq.On
+		if eq.On {
+			s.Log.Add("Shields raised.", MsgInfo)
+		} else {
+			s.Log.Add("Shields lowered.", MsgInfo)
+		}
+
+	case world.EquipLifeSupport:
+		eq.On = !eq.On
+		if eq.On {
+			s.Log.Add("Life support online. Scrubbers and heaters running.", MsgInfo)
+		} else {
+			s.Log.Add("Life support offline. The air will go stale.", MsgWarning)
+		}
+		if s.Skills.AddXP(SkillEngineering, 3.0) {
+			LogLevelUp(s.Log, SkillEngineering, s.Skills.Level(SkillEngineering))
+		}
+
 	default:
 		s.Log.Add("This equipment can't be toggled.", MsgSocial)
 	}
@@ -991,6 +1180,9 @@ func (s *Sim) DockAtStation() *StationData {
 	DockRefill(&s.Resources)
 	s.Log.Add(fmt.Sprintf("Docked at %s. Tanks topped off, energy full.", sd.Name), MsgInfo)
 	s.OnStationDocked(s.Sector.CurrentSystem)
+	s.expireMissions()
+	s.completeStationMissions()
+	s.refreshMissionBoard(sd)
 	return sd
 }
 
@@ -998,6 +1190,9 @@ func (s *Sim) DockAtStation() *StationData {
 // amount = 0 means full repair. Returns credits spent and points repaired.
 func (s *Sim) RepairHull(amount int) (cost int, repaired int) {
 	const costPerPoint = 2
+	if n := s.sealBreaches(); n > 0 {
+		s.Log.Add(fmt.Sprintf("Dock crew sealed %d hull breach(es).", n), MsgInfo)
+	}
 	damage := s.Resources.MaxHull - s.Resources.Hull
 	if damage == 0 {
 		s.Log.Add("Hull integrity at 100%. No repairs needed.", MsgInfo)
@@ -1024,11 +1219,11 @@ func (s *Sim) RepairHull(amount int) (cost int, repaired int) {
 
 // BuyCargo buys one unit of cargo from the station.
 func (s *Sim) BuyCargo(sd *StationData, kind CargoKind) bool {
-	if !sd.Stocked[kind] || sd.Stock[kind] <= 0 {
+	if !sd.Carries(kind) || sd.Stock[kind] <= 0 {
 		s.Log.Add("Station has none of that in stock.", MsgWarning)
 		return false
 	}
-	price := sd.SellPrices[kind]
+	price := s.StationPrice(sd, kind)
 	if s.Resources.Credits < price {
 		s.Log.Add(fmt.Sprintf("Need %dcr. You have %dcr.", price, s.Resources.Credits), MsgWarning)
 		return false
@@ -1058,10 +1253,14 @@ func (s *Sim) SellCargo(sd *StationData, padIdx int) bool {
 		return false
 	}
 	kind := pad.Kind
-	price := sd.BuyPrices[kind]
-	if price <= 0 {
-		price = 1 // station always buys for at least 1
+	if int(kind) >= len(sd.Stock) {
+		s.Log.Add(fmt.Sprintf("The station won't take %s.", CargoName(kind)), MsgWarning)
+		return false // cargo from a content pack that is no longer loaded
+	}
+	if s.contractHeld(kind) {
+		return false
 	}
+	price := s.StationOffer(sd, kind) // station always buys for at least 1
 	s.Resources.Credits += price
 	sd.Stock[kind]++
 	pad.Count--
@@ -1086,6 +1285,9 @@ func (s *Sim) JettisonCargo(padIdx int) bool {
 		s.Log.Add("That pad is empty.", MsgWarning)
 		return false
 	}
+	if s.contractHeld(pad.Kind) {
+		return false
+	}
 	name := CargoName(pad.Kind)
 	pad.Count--
 	if pad.Count == 0 {
@@ -1109,6 +1311,9 @@ func (s *Sim) IncinerateCargo(padIdx int) bool {
 		s.Log.Add("Fuel tank already full.", MsgWarning)
 		return false
 	}
+	if s.contractHeld(pad.Kind) {
+		return false
+	}
 	name := CargoName(pad.Kind)
 	pad.Count--
 	if pad.Count == 0 {
@@ -1126,34 +1331,15 @@ func (s *Sim) IncinerateCargo(padIdx int) bool {
 	return true
 }
 
-// NavigateTo attempts to jump the shuttle to the target star system.
-func (s *Sim) NavigateTo(targetIdx int) bool {
-	cost := s.Sector.EnergyCostTo(targetIdx)
-	if s.Resources.Energy < cost {
-		s.Log.Add(fmt.Sprintf("Not enough energy. Need %d, have %d.", cost, s.Resources.Energy), MsgWarning)
-		return false
-	}
-	s.Resources.Energy -= cost
-	s.Sector.CurrentSystem = targetIdx
-	s.Sector.Systems[targetIdx].Visited = true
-	s.Sector.EnsureSystemMap(targetIdx)
-	star := s.Sector.Systems[targetIdx]
-	s.Log.Add(fmt.Sprintf("Arrived at %s. %s. Energy: -%d.", star.Name, StarTypeName(star.Type), cost), MsgDiscovery)
-	if s.Skills.AddXP(SkillPiloting, 5.0) {
-		LogLevelUp(s.Log, SkillPiloting, s.Skills.Level(SkillPiloting))
-	}
-	s.OnSystemVisited(targetIdx)
-	return true
-}
-
 // OnSystemVisited handles first-visit discovery bonuses for a star system.
 func (s *Sim) OnSystemVisited(sysIdx int) {
 	star := s.Sector.Systems[sysIdx]
-	firstVisit := !s.Discovery.SystemsVisited[sysIdx]
+	sysID := s.Sector.SystemID(sysIdx)
+	firstVisit := !s.Discovery.SystemsVisited[sysID]
 
 	// First time visiting this specific system?
 	if firstVisit {
-		s.Discovery.SystemsVisited[sysIdx] = true
+		s.Discovery.SystemsVisited[sysID] = true
 		s.Discovery.TotalSystemsVisited++
 		s.Resources.Credits += 10
 		s.Log.Add(fmt.Sprintf("New system discovered: %s! +10cr.", star.Name), MsgDiscovery)
@@ -1188,7 +1374,7 @@ func (s *Sim) ScanPlanet(objIdx int) {
 	sm := s.Sector.CurrentSystemMap()
 	obj := &sm.Objects[objIdx]
 	sysIdx := s.Sector.CurrentSystem
-	key := ScanKey(sysIdx, objIdx)
+	key := ScanKey(s.Sector.SystemID(sysIdx), objIdx)
 
 	if _, already := s.Discovery.PlanetsScanned[key]; already {
 		s.Log.Add(fmt.Sprintf("Re-scanning %s. No new data.", obj.Name), MsgInfo)
@@ -1212,6 +1398,7 @@ func (s *Sim) ScanPlanet(objIdx int) {
 		obj.Name, PlanetKindName(obj.PlanetType), scanData.Resources), MsgDiscovery)
 	if scanData.Hazard != "" {
 		s.Log.Add(fmt.Sprintf("Hazard: %s", scanData.Hazard), MsgWarning)
+		s.radiationFromScan(scanData)
 	}
 	if scanData.POI != "" {
 		s.Log.Add(fmt.Sprintf("POI: %s", scanData.POI), MsgDiscovery)
@@ -1223,8 +1410,8 @@ func (s *Sim) ScanPlanet(objIdx int) {
 
 // OnStationDocked handles first-dock discovery bonuses.
 func (s *Sim) OnStationDocked(sysIdx int) {
-	if !s.Discovery.StationsDocked[sysIdx] {
-		s.Discovery.StationsDocked[sysIdx] = true
+	if sysID := s.Sector.SystemID(sysIdx); !s.Discovery.StationsDocked[sysID] {
+		s.Discovery.StationsDocked[sysID] = true
 		s.Discovery.TotalStationsDocked++
 		s.Resources.Credits += 10
 		s.Log.Add("First dock at this station! +10cr.", MsgDiscovery)
@@ -1268,7 +1455,7 @@ func (s *Sim) tickHails() {
 		}
 		dx := obj.X - sx
 		dy := obj.Y - sy
-		if dx*dx+dy*dy <= 64 { // within 8 tiles
+		if dx*dx+dy*dy <= s.hailRange(obj) {
 			obj.Hailed = true
 			s.PendingHail = &HailState{
 				Ship:      obj,
@@ -1288,11 +1475,11 @@ func (s *Sim) handleHailExpiry() {
 	switch ship.AIKind {
 	case AIPirate:
 		// Pirates get aggressive when ignored
-		ship.MoveRate = max(ship.MoveRate-2, 3)
-		ship.dirTimer = 30
+		s.startChase(ship, false)
 		s.Log.Add(fmt.Sprintf("%s: No response. The pirate turns hostile!", ship.Name), MsgCritical)
 	case AIPatrol:
 		s.Log.Add(fmt.Sprintf("%s: Hail expired. Patrol logs you as suspicious.", ship.Name), MsgWarning)
+		s.AdjustRep(ship.Faction, -2)
 	default:
 		s.Log.Add(fmt.Sprintf("%s: Hail expired. The vessel moves on.", ship.Name), MsgInfo)
 	}
@@ -1304,28 +1491,55 @@ func (s *Sim) StartEncounter() {
 		return
 	}
 	s.ActiveEncounter = NewEncounter(s.PendingHail.Ship, s.Sector.Seed, &s.Skills)
+	if s.ActiveEncounter.Kind == EncounterPirate {
+		s.ActiveEncounter.Options[1] = s.bribeOption(s.PendingHail.Ship.Faction)
+		s.ActiveEncounter.Options[4] = s.fightOption()
+	}
 	s.PendingHail = nil
 }
 
 // ResolveEncounterOption processes the player's choice in an active encounter.
+// The result is also stored in the encounter's ResultText for display.
 func (s *Sim) ResolveEncounterOption(optionIdx int) string {
 	if s.ActiveEncounter == nil {
 		return ""
 	}
-	return ResolveEncounter(s, s.ActiveEncounter, optionIdx)
+	enc := s.ActiveEncounter
+	result := ResolveEncounter(s, enc, optionIdx)
+	if result == "TRADE" {
+		// Trader wants to trade — no trade interface outside stations yet
+		enc.ResultText = "The trader opens a trade channel.\n(Space trading not yet available outside stations.)"
+		enc.Resolved = true
+	} else {
+		enc.ResultText = result
+	}
+	return result
 }
 
-// EndEncounter clears the active encounter.
+// EndEncounter clears the active encounter. A pirate left without an answer
+// comes after the shuttle.
 func (s *Sim) EndEncounter() {
+	if enc := s.ActiveEncounter; enc != nil && enc.Kind == EncounterPirate && !enc.Resolved {
+		s.startChase(enc.ShipObj, false)
+		s.Log.Add(fmt.Sprintf("%s won't take silence for an answer.", enc.ShipName), MsgCritical)
+	}
 	s.ActiveEncounter = nil
 }
 
 // ResolveEpisodeOption processes the player's choice in an active episode.
+// The result is also stored in the episode's ResultText for display.
 func (s *Sim) ResolveEpisodeOption(optionIdx int) string {
 	if s.ActiveEpisode == nil {
 		return ""
 	}
-	return ResolveEpisode(s, s.ActiveEpisode, optionIdx)
+	var result string
+	if s.ActiveEpisode.MonkeyLion {
+		result = s.resolveMonkeyLion(s.ActiveEpisode, optionIdx)
+	} else {
+		result = ResolveEpisode(s, s.ActiveEpisode, optionIdx)
+	}
+	s.ActiveEpisode.ResultText = result
+	return result
 }
 
 // EndEpisode clears the active episode.
@@ -1333,13 +1547,19 @@ func (s *Sim) EndEpisode() {
 	s.ActiveEpisode = nil
 }
 
-// EnterOrbit puts the shuttle into orbit around a planet.
-func (s *Sim) EnterOrbit(objIdx int) {
+// EnterOrbit puts the shuttle into orbit around a planet. The shuttle has
+// to be alongside and moving with it.
+func (s *Sim) EnterOrbit(objIdx int) bool {
 	sm := s.Sector.CurrentSystemMap()
 	obj := &sm.Objects[objIdx]
+	if why := s.OrbitMatch(objIdx); why != "" {
+		s.Log.Add(why, MsgWarning)
+		return false
+	}
 	s.OrbitPlanetIdx = objIdx
 	s.Log.Add(fmt.Sprintf("Entering orbit around %s. %s.", obj.Name, PlanetKindName(obj.PlanetType)), MsgDiscovery)
 	s.Log.Add("Use the science station to scan. Pilot station to leave orbit.", MsgInfo)
+	return true
 }
 
 // LeaveOrbit exits orbit and returns to free flight.
@@ -1374,18 +1594,34 @@ func (s *Sim) LandOnPlanet() {
 	obj := &sm.Objects[s.OrbitPlanetIdx]
 
 	// Get scan data for POI
-	scanKey := ScanKey(s.Sector.CurrentSystem, s.OrbitPlanetIdx)
+	scanKey := ScanKey(s.Sector.SystemID(s.Sector.CurrentSystem), s.OrbitPlanetIdx)
 	scan, ok := s.Discovery.PlanetsScanned[scanKey]
 	poi := ""
 	if ok {
 		poi = scan.POI
 	}
 
-	// Generate surface map
+	// Generate surface map. The planet's hazard is there whether it was
+	// scanned or not.
 	seed := s.Sector.Seed*5000 + int64(s.Sector.CurrentSystem)*100 + int64(s.OrbitPlanetIdx)
 	s.ActiveSurface = GenerateSurfaceMap(seed, s.OrbitPlanetIdx, obj.PlanetType, poi)
+	if !ok {
+		scan = GenerateScanData(s.Sector.Seed, s.Sector.CurrentSystem, s.OrbitPlanetIdx, obj, s.Sector.Systems[s.Sector.CurrentSystem].Name)
+	}
+	s.ActiveSurface.Hazard = scan.Hazard
+	if m := s.retrievalFor(s.OrbitPlanetIdx); m != nil {
+		s.ActiveSurface.SetRetrievalObjective(m.Cargo, fmt.Sprintf("Recover the artifact for %s", m.Giver))
+	}
+	life := s.spawnCreatures(obj.PlanetType)
 
 	s.Log.Add("Touchdown. Explore the area and return to the shuttle.", MsgInfo)
+	if ok && life > 0 {
+		// A scan from orbit counted them on the way down
+		s.Log.Add(fmt.Sprintf("Scanners pick up %d hostile life signs.", life), MsgWarning)
+	}
+	if ok {
+		s.adviseGear()
+	}
 	if s.ActiveSurface.Objective != nil {
 		s.Log.Add(fmt.Sprintf("Objective: %s", s.ActiveSurface.Objective.Description), MsgDiscovery)
 	}
@@ -1404,7 +1640,25 @@ func (s *Sim) TrySurfaceMove(dx, dy int) bool {
 	if s.ActiveSurface == nil {
 		return false
 	}
-	return s.ActiveSurface.TryMove(dx, dy)
+	surf := s.ActiveSurface
+	if e, ok := s.creatureAt(surf.PlayerX+dx, surf.PlayerY+dy); ok {
+		s.attackCreature(e, 1)
+		return true
+	}
+	if surf.TryMove(dx, dy) {
+		return true
+	}
+	if surf.GetTile(surf.PlayerX+dx, surf.PlayerY+dy).Kind == world.TileHazard {
+		if s.hasGear(CargoHazardGear) {
+			surf.PlayerX += dx
+			surf.PlayerY += dy
+			surf.UpdateVisibility()
+			s.crossHazard(surf.TerrainType)
+			return true
+		}
+		s.burnFromHazard(surf.TerrainType)
+	}
+	return false
 }
 
 // SurfaceInteract handles E key interactions on the surface.
@@ -1461,6 +1715,11 @@ func (s *Sim) SurfaceInteract() {
 			added := s.Resources.AddCargo(surf.Objective.ItemKind, 1)
 			if added > 0 {
 				s.Log.Add(fmt.Sprintf("Acquired %s.", CargoName(surf.Objective.ItemKind)), MsgInfo)
+				// The unit just beamed aboard is the one the contract wants
+				if m := s.retrievalFor(surf.PlanetIdx); m != nil {
+					m.Retrieved = true
+					s.Log.Add(fmt.Sprintf("%s secured. Return it to %s.", CargoName(m.Cargo), m.Giver), MsgDiscovery)
+				}
 			}
 			if s.Skills.AddXP(SkillScience, 8.0) {
 				LogLevelUp(s.Log, SkillScience, s.Skills.Level(SkillScience))
@@ -1481,7 +1740,9 @@ func (s *Sim) BoardShuttle() {
 	}
 	// Position player at the airlock (entry point)
 	s.SetPlayerPos(s.Layout.AirlockX(), s.Layout.AirlockY())
+	s.Outside = false
 	s.Log.Add("Boarding the shuttle.", MsgInfo)
+	s.rechargeSuit()
 	// ActiveSurface stays set - shuttle is still landed
 }
 
@@ -1495,7 +1756,31 @@ func (s *Sim) ExitShuttle() bool {
 	// Place player at shuttle position on surface
 	s.ActiveSurface.PlayerX = s.ActiveSurface.ShuttleX
 	s.ActiveSurface.PlayerY = s.ActiveSurface.ShuttleY
+	s.ActiveSurface.UpdateVisibility()
+	s.Outside = true
 	s.Log.Add("Exiting shuttle.", MsgInfo)
+	s.reportExposure()
+	return true
+}
+
+// Launch takes the shuttle out to the system map from the pilot console:
+// it completes the prologue, lifts off from a surface or breaks orbit.
+// Returns false if the shuttle isn't ready to leave the prologue yet.
+func (s *Sim) Launch() bool {
+	if s.IsOnSurface() {
+		if s.InPrologue() {
+			if !s.PrologueSurface.CheckPrologueComplete() {
+				s.Log.Add("Shuttle not ready. "+s.PrologueSurface.ObjectiveStatus(), MsgWarning)
+				return false
+			}
+			s.CompletePrologue()
+		} else {
+			s.LiftOff()
+		}
+	} else if s.IsOrbiting() {
+		s.LeaveOrbit()
+	}
+	s.Sector.EnsureSystemMap(s.Sector.CurrentSystem)
 	return true
 }
 
@@ -1513,9 +1798,14 @@ func (s *Sim) LiftOff() {
 	} else if surf.Objective != nil {
 		s.Log.Add("Objective abandoned.", MsgWarning)
 	}
+	if m := s.retrievalFor(surf.PlanetIdx); m != nil && surf.Objective != nil && surf.Objective.Complete {
+		s.Log.Add(fmt.Sprintf("The %s didn't make it aboard. Contract still open.", CargoName(m.Cargo)), MsgWarning)
+	}
+	s.expireMissions()
 
 	// Position player at the airlock
 	s.SetPlayerPos(s.Layout.AirlockX(), s.Layout.AirlockY())
 	s.Log.Add("Lifting off. Returning to orbit.", MsgInfo)
+	s.clearCreatures()
 	s.ActiveSurface = nil
 }
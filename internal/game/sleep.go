@@ -0,0 +1,135 @@
+package game
+
+import "fmt"
+
+// Fatigue rises while the player is awake and falls while they sleep. Sleep
+// starts at a bed (or wherever the player collapses) and lasts until they are
+// rested, or until something needs them: a critical alert, a hail, an
+// episode or the jump drive firing. The windowed game runs SleepSpeed ticks a
+// frame while the player sleeps, so a night passes in under half a minute.
+
+// SleepSpeed is how many ticks the UI runs per frame while the player sleeps.
+const SleepSpeed = 20
+
+const (
+	fatigueInterval = 720 // fatigue rises 1 every 12 sec awake (~100 in a game day)
+	restInterval    = 180 // fatigue falls 1 every 3 sec in bed (~5 game hours from exhausted)
+
+	FatigueTired     = 60 // skill checks at -1
+	FatigueExhausted = 80 // skill checks at -2
+	fatigueCollapse  = 100
+	fatigueRested    = 20 // too awake to sleep below this
+)
+
+// SleepState is the player's sleep. Sleeping on the floor after collapsing
+// rests at half the rate of a bed.
+type SleepState struct {
+	Start uint64 // tick the player fell asleep
+	InBed bool
+}
+
+// Asleep returns true while the player sleeps.
+func (s *Sim) Asleep() bool {
+	return s.Sleep != nil
+}
+
+// GoToSleep puts the player to sleep. Returns false if they can't sleep now.
+func (s *Sim) GoToSleep(inBed bool) bool {
+	switch {
+	case s.Sleep != nil:
+		return false
+	case s.Needs.Fatigue < fatigueRested:
+		s.Log.Add("You're not tired. You lie there staring at the bulkhead.", MsgSocial)
+		return false
+	case s.PendingHail != nil || s.ActiveEncounter != nil || s.ActiveEpisode != nil:
+		s.Log.Add("Can't sleep now - someone's on the comms.", MsgWarning)
+		return false
+	}
+	s.Sleep = &SleepState{Start: s.Ticks, InBed: inBed}
+	if inBed {
+		s.Log.Add("You climb into the berth and close your eyes.", MsgInfo)
+	}
+	return true
+}
+
+// Wake ends the player's sleep and reports how long it lasted.
+func (s *Sim) Wake(reason string) {
+	if s.Sleep == nil {
+		return
+	}
+	hours := float64(s.Ticks-s.Sleep.Start) / TicksPerHour
+	s.Sleep = nil
+	s.Log.Add(fmt.Sprintf("%s Slept %.1f hours.", reason, hours), MsgInfo)
+}
+
+// WakeUp gets the player up early. Someone who collapsed can't be roused
+// until they've had some rest.
+func (s *Sim) WakeUp() bool {
+	if s.Sleep == nil {
+		return false
+	}
+	if !s.Sleep.InBed && s.Needs.Fatigue >= FatigueExhausted {
+		return false
+	}
+	s.Wake("You get up.")
+	return true
+}
+
+// tickFatigue raises fatigue while awake, lowers it while asleep and makes
+// an exhausted player collapse where they stand.
+func (s *Sim) tickFatigue() {
+	n := &s.Needs
+	if s.Sleep == nil {
+		if s.Ticks%fatigueInterval == 0 {
+			n.Fatigue = min(n.Fatigue+1, fatigueCollapse)
+		}
+		if n.Fatigue >= fatigueCollapse {
+			s.Log.Add("You collapse from exhaustion.", MsgCritical)
+			s.Sleep = &SleepState{Start: s.Ticks}
+		}
+		return
+	}
+
+	rate := uint64(restInterval)
+	if !s.Sleep.InBed {
+		rate *= 2
+	}
+	if s.Ticks%rate == 0 {
+		n.Fatigue = max(n.Fatigue-1, 0)
+	}
+	if n.Fatigue == 0 {
+		s.Wake("You wake up rested.")
+	}
+}
+
+// checkSleepInterrupts wakes the player for anything that needs them.
+// criticals is the log's critical count before this tick.
+func (s *Sim) checkSleepInterrupts(criticals uint64) {
+	switch {
+	case s.Sleep == nil:
+	case s.PlayerDead:
+		s.Sleep = nil
+	case s.Log.criticals > criticals && (s.Sleep.InBed || s.Needs.Fatigue < FatigueExhausted):
+		// Someone who collapsed sleeps through alarms until they've had some rest
+		s.Wake("An alarm jolts you awake.")
+	case s.PendingHail != nil:
+		s.Wake("The comms chime wakes you.")
+	case s.JumpCompleted:
+		s.Wake("The jump drive's thud wakes you.")
+	case s.ActiveEpisode != nil:
+		s.Wake("The proximity alert wakes you. Something's out there.")
+	}
+}
+
+// SkillCheck returns the level a skill is rolled at: its level, less a
+// penalty when the player is tired. Never below 1.
+func (s *Sim) SkillCheck(id SkillID) int {
+	level := s.Skills.Level(id)
+	switch {
+	case s.Needs.Fatigue >= FatigueExhausted:
+		level -= 2
+	case s.Needs.Fatigue >= FatigueTired:
+		level--
+	}
+	return max(level, 1)
+}
@@ -22,6 +22,11 @@ const (
 	CargoShuttleFuel   // fuel cells for shuttle tank
 	CargoSpareParts    // engine repair parts
 	CargoShuttlePower  // power pack for battery
+	// Suit gear, drawn on from the cargo bay out on surfaces
+	CargoO2Canisters   // refill the suit's O2
+	CargoThermalLiners // slow the cold and heat getting through the suit
+	CargoRadShielding  // halves the radiation dose outside
+	CargoHazardGear    // crampons and heat boots for crossing crevasses and lava
 	CargoKindCount     // sentinel — not a real cargo type
 )
 
@@ -34,40 +39,27 @@ type CargoPad struct {
 	Count int
 }
 
-// cargoEntry holds static info about a cargo type.
-type cargoEntry struct {
-	Name      string
-	BasePrice int
-}
+// Cargo names and base prices live in the content packs (see content.go).
+// Packs can add cargo types after the built-in ones, so loops over cargo
+// kinds run to NumCargoKinds rather than CargoKindCount.
 
-// cargoTable maps CargoKind to name and base price.
-var cargoTable = [CargoKindCount]cargoEntry{
-	CargoNone:           {"Empty", 0},
-	CargoScrapMetal:     {"Scrap Metal", 3},
-	CargoWaterIce:       {"Water Ice", 5},
-	CargoRationPacks:    {"Ration Packs", 8},
-	CargoPowerCells:     {"Power Cells", 12},
-	CargoMedKits:        {"Med Kits", 18},
-	CargoCircuitry:      {"Circuitry", 25},
-	CargoRareMinerals:   {"Rare Minerals", 35},
-	CargoAlienArtifacts: {"Alien Artifacts", 50},
-	CargoShuttleFuel:    {"Shuttle Fuel", 20},
-	CargoSpareParts:     {"Spare Parts", 25},
-	CargoShuttlePower:   {"Power Pack", 15},
+// NumCargoKinds returns the number of cargo kinds, including CargoNone.
+func NumCargoKinds() int {
+	return len(content.Cargo)
 }
 
 // CargoName returns the display name for a cargo kind.
 func CargoName(k CargoKind) string {
-	if k < CargoKindCount {
-		return cargoTable[k].Name
+	if int(k) < NumCargoKinds() {
+		return content.Cargo[k].Name
 	}
 	return "Unknown"
 }
 
 // CargoBasePrice returns the base price for a cargo kind.
 func CargoBasePrice(k CargoKind) int {
-	if k < CargoKindCount {
-		return cargoTable[k].BasePrice
+	if int(k) < NumCargoKinds() {
+		return content.Cargo[k].Price
 	}
 	return 0
 }
@@ -77,18 +69,43 @@ func CargoBasePrice(k CargoKind) int {
 type StationData struct {
 	Name       string
 	Tagline    string
-	SellPrices [CargoKindCount]int  // what station charges player to buy
-	BuyPrices  [CargoKindCount]int  // what station pays player to sell
-	Stock      [CargoKindCount]int  // units available at station
-	Stocked    [CargoKindCount]bool // which types this station carries
-	BarScene   string               // random bar text (generated on dock)
-	Faction    string               // faction presence at this station
+	SellPrices []int      // what station charges player to buy, by CargoKind
+	BuyPrices  []int      // what station pays player to sell, by CargoKind
+	Stock      []int      // units available at station, by CargoKind
+	Stocked    []bool     // which types this station carries, by CargoKind
+	BarScene   string     // random bar text (generated on dock)
+	FactionID  int        // index into Sector.Factions
+	Board      []Mission  // contracts on offer (see refreshMissionBoard)
+	BoardDay   int        // game day the board was posted, 1-based
+}
+
+// fitCargoKinds grows the per-cargo tables to cover every cargo kind, so
+// stations saved before a pack added cargo can still trade in it.
+func (sd *StationData) fitCargoKinds() {
+	n := NumCargoKinds()
+	for len(sd.SellPrices) < n {
+		sd.SellPrices = append(sd.SellPrices, 0)
+	}
+	for len(sd.BuyPrices) < n {
+		sd.BuyPrices = append(sd.BuyPrices, 0)
+	}
+	for len(sd.Stock) < n {
+		sd.Stock = append(sd.Stock, 0)
+	}
+	for len(sd.Stocked) < n {
+		sd.Stocked = append(sd.Stocked, false)
+	}
+}
+
+// Carries returns true if the station trades in a cargo kind.
+func (sd *StationData) Carries(k CargoKind) bool {
+	return int(k) < len(sd.Stocked) && sd.Stocked[k]
 }
 
 // StockedList returns the cargo kinds this station carries, in order.
 func (sd *StationData) StockedList() []CargoKind {
 	var list []CargoKind
-	for k := CargoKind(1); k < CargoKindCount; k++ {
+	for k := CargoKind(1); int(k) < len(sd.Stocked); k++ {
 		if sd.Stocked[k] {
 			list = append(list, k)
 		}
@@ -100,29 +117,18 @@ func (sd *StationData) StockedList() []CargoKind {
 func GenerateStationData(seed int64, name string) *StationData {
 	rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed>>16|3)))
 
-	sd := &StationData{
-		Name:    name,
-		Faction: "Space Knights", // placeholder — all stations Space Knights for now
-	}
+	sd := &StationData{Name: name}
+	sd.fitCargoKinds()
 
 	// Tagline
-	taglines := []string{
-		"Where the recycled air is almost breathable.",
-		"Fuel up. Stock up. Try not to blow up.",
-		"Now with 40% fewer hull breaches!",
-		"We put the 'station' in 'desperation'.",
-		"Voted 'Adequate' three years running.",
-		"Free docking. Everything else costs extra.",
-		"Home is wherever you can afford to stop.",
-		"Our motto: it could be worse.",
-	}
+	taglines := content.StationTaglines
 	sd.Tagline = taglines[rng.IntN(len(taglines))]
 
 	// Stock 4-6 cargo types (skip CargoNone at index 0)
 	numStocked := 4 + rng.IntN(3)
 	// Shuffle cargo kinds to pick which ones are stocked
-	kinds := make([]CargoKind, 0, int(CargoKindCount)-1)
-	for k := CargoKind(1); k < CargoKindCount; k++ {
+	kinds := make([]CargoKind, 0, NumCargoKinds()-1)
+	for k := CargoKind(1); int(k) < NumCargoKinds(); k++ {
 		kinds = append(kinds, k)
 	}
 	rng.Shuffle(len(kinds), func(i, j int) {
@@ -136,7 +142,7 @@ func GenerateStationData(seed int64, name string) *StationData {
 		sd.Stocked[k] = true
 		// Price modifier: 0.8 to 1.4
 		modifier := 0.8 + rng.Float64()*0.6
-		sellPrice := int(float64(cargoTable[k].BasePrice)*modifier + 0.5)
+		sellPrice := int(float64(CargoBasePrice(k))*modifier + 0.5)
 		if sellPrice < 1 {
 			sellPrice = 1
 		}
@@ -156,31 +162,9 @@ func GenerateStationData(seed int64, name string) *StationData {
 }
 
 func generateBarScene(rng *rand.Rand) string {
-	scenes := []string{
-		"The bartender slides you something luminous.\nIt might be a drink. It might be alive.",
-		"A patron at the end of the bar is arguing\nwith a potted plant. The plant is winning.",
-		"The music is best described as 'aggressive\nambiance.' Nobody seems to mind.",
-		"A trading crew plays cards in the corner.\nOne of them is clearly cheating.\nThe others don't seem to care.",
-		"The bar smells like recycled air and\nbroken promises. The drinks are worse.",
-		"A retired pilot tells you about the time\nshe outran a pirate fleet. You suspect\nshe's lying. She's definitely lying.",
-	}
-
-	rumors := []string{
-		"A patron whispers: \"Heard there's good\nsalvage near the outer systems.\"",
-		"Someone mutters: \"The Space Knights are\nrecruiting. Desperate times.\"",
-		"A trader mentions: \"Circuitry prices are\nthrough the roof at the rim stations.\"",
-		"An old spacer rasps: \"The Monkey Lion.\nThey say she's still out there.\"",
-		"A drunk pilot slurs: \"Don't fly near\nthe red giants. Trust me on this.\"",
-		"A mechanic sighs: \"Parts are getting\nscarce. Stock up while you can.\"",
-	}
-
-	deborahs := []string{
-		"Deborah tries to eat the barstool.\nShe is a zebra.",
-		"Deborah is wearing a tiny party hat.\nNobody knows where she got it.",
-		"Deborah stares at you with an intensity\nthat suggests she thinks you're food.",
-		"Deborah has somehow gotten behind the\nbar. The bartender has given up.",
-		"Deborah is asleep on the pool table.\nThe game continues around her.",
-	}
+	scenes := content.Bar.Scenes
+	rumors := content.Bar.Rumors
+	deborahs := content.Bar.Deborahs
 
 	scene := scenes[rng.IntN(len(scenes))]
 	rumor := rumors[rng.IntN(len(rumors))]
@@ -192,28 +176,14 @@ func generateBarScene(rng *rand.Rand) string {
 // DockRefill performs the auto-refill sequence when docking at a station.
 // Flushes waste, converts dirty to clean, tops off all tanks and energy.
 func DockRefill(r *Resources) {
-	// 1. Flush body waste → dirty pools
-	r.Organic.Dirty += r.WasteOrganic
-	r.Water.Dirty += r.WasteWater
+	// 1. Flush body waste into the station's tanks
 	r.WasteOrganic = 0
 	r.WasteWater = 0
 
-	// 2. Convert all dirty → clean
-	r.Organic.Clean += r.Organic.Dirty
-	r.Organic.Dirty = 0
-	r.Water.Clean += r.Water.Dirty
-	r.Water.Dirty = 0
-
-	// 3. Clear recycler buffer → clean
-	r.Organic.Clean += r.Recycler.OrganicBuffer
-	r.Water.Clean += r.Recycler.WaterBuffer
-	r.Recycler.OrganicBuffer = 0
-	r.Recycler.WaterBuffer = 0
-
-	// 4. Top off tanks to capacity (station provides the extra)
-	r.Organic.Clean = r.Organic.Capacity
-	r.Water.Clean = r.Water.Capacity
+	// 2. Swap every tank and recycler buffer for clean matter, topped off
+	// to capacity (station provides the extra)
+	r.Matter.Refill()
 
-	// 5. Refill energy
+	// 3. Refill energy
 	r.Energy = r.MaxEnergy
 }
@@ -0,0 +1,164 @@
+package game
+
+import "math"
+
+// NPC ships move a tile at a time, but each one steers toward a point that
+// depends on what it is. Pirates lead the shuttle to cut it off, traders run
+// a route between the station and the planets, and patrols circle the
+// station. A ship with nowhere to be wanders.
+
+const (
+	pirateChaseRate   = 5   // ticks per move while chasing: 12 tiles/sec, short of the shuttle flat out
+	pirateLeadTicks   = 300 // furthest ahead a pirate aims along the shuttle's course
+	patrolOrbitRadius = 12  // tiles from the station
+	patrolOrbitSteps  = 16  // waypoints around a patrol orbit
+	waypointReach     = 2   // tiles from a waypoint that count as there
+)
+
+// moveRate returns the ticks between the ship's moves: a pirate in pursuit
+// burns harder than it cruises.
+func (obj *SpaceObject) moveRate() int {
+	if obj.Pursuing {
+		return min(obj.MoveRate, pirateChaseRate)
+	}
+	return obj.MoveRate
+}
+
+// speed returns how far the ship moves per tick.
+func (obj *SpaceObject) speed() float64 {
+	return 1 / float64(max(obj.moveRate(), 1))
+}
+
+// steer points the ship at its destination. Returns false if it has none.
+func (sm *SystemMap) steer(obj *SpaceObject) bool {
+	switch obj.AIKind {
+	case AIPirate:
+		// Once it has had its say, a pirate only comes back for a chase
+		if obj.Hailed && !obj.Pursuing {
+			return false
+		}
+		x, y := sm.clampPoint(sm.interceptPoint(obj))
+		headToward(obj, x, y)
+		return true
+
+	case AITrader:
+		route := sm.tradeRoute()
+		if len(route) == 0 {
+			return false
+		}
+		port := &sm.Objects[route[obj.Waypoint%len(route)]]
+		if near(obj, float64(port.X), float64(port.Y)) {
+			obj.Waypoint = (obj.Waypoint + 1) % len(route)
+			port = &sm.Objects[route[obj.Waypoint]]
+		}
+		headToward(obj, float64(port.X), float64(port.Y))
+		return true
+
+	case AIPatrol:
+		centre := sm.FindStation()
+		if centre == nil {
+			centre = &sm.Objects[0] // the star
+		}
+		cx, cy := float64(centre.X), float64(centre.Y)
+		dx, dy := float64(obj.X)-cx, float64(obj.Y)-cy
+		if math.Hypot(dx, dy) > patrolOrbitRadius+2*waypointReach {
+			// Join the orbit at the next waypoint round from where we are
+			step := math.Round(math.Atan2(dy, dx) / (2 * math.Pi) * patrolOrbitSteps)
+			obj.Waypoint = (int(step) + 1 + patrolOrbitSteps) % patrolOrbitSteps
+		}
+		x, y := sm.clampPoint(orbitPoint(cx, cy, obj.Waypoint))
+		if near(obj, x, y) {
+			obj.Waypoint = (obj.Waypoint + 1) % patrolOrbitSteps
+			x, y = sm.clampPoint(orbitPoint(cx, cy, obj.Waypoint))
+		}
+		headToward(obj, x, y)
+		return true
+	}
+	return false
+}
+
+// interceptPoint returns where the ship meets the shuttle if the shuttle
+// holds its course. If it can't catch the shuttle, it aims ahead of it
+// anyway, as far as it would take to reach where the shuttle is now.
+func (sm *SystemMap) interceptPoint(obj *SpaceObject) (float64, float64) {
+	sh := &sm.Shuttle
+	rx, ry := sh.X-float64(obj.X), sh.Y-float64(obj.Y)
+	v := obj.speed()
+
+	// Solve |r + vel*t| = v*t for the first t > 0
+	a := sh.VX*sh.VX + sh.VY*sh.VY - v*v
+	b := 2 * (rx*sh.VX + ry*sh.VY)
+	c := rx*rx + ry*ry
+	t := math.Sqrt(c) / v
+	if math.Abs(a) < 1e-9 {
+		if b < 0 {
+			t = -c / b
+		}
+	} else if disc := b*b - 4*a*c; disc >= 0 {
+		sq := math.Sqrt(disc)
+		t1, t2 := (-b-sq)/(2*a), (-b+sq)/(2*a)
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		if t1 > 0 {
+			t = t1
+		} else if t2 > 0 {
+			t = t2
+		}
+	}
+	t = min(t, pirateLeadTicks)
+	return sh.X + sh.VX*t, sh.Y + sh.VY*t
+}
+
+// tradeRoute lists the ports a trader calls at in turn: the station between
+// every planet, or just the planets if the system has no station.
+func (sm *SystemMap) tradeRoute() []int {
+	station := -1
+	var planets []int
+	for i := range sm.Objects {
+		switch sm.Objects[i].Kind {
+		case ObjStation:
+			station = i
+		case ObjPlanet:
+			planets = append(planets, i)
+		}
+	}
+	if station < 0 {
+		return planets
+	}
+	route := make([]int, 0, 2*len(planets))
+	for _, p := range planets {
+		route = append(route, station, p)
+	}
+	return route
+}
+
+// clampPoint keeps a destination inside the edges ships bounce off.
+func (sm *SystemMap) clampPoint(x, y float64) (float64, float64) {
+	return math.Max(2, math.Min(x, float64(sm.Width-3))), math.Max(2, math.Min(y, float64(sm.Height-3)))
+}
+
+// orbitPoint returns a waypoint on a patrol orbit.
+func orbitPoint(cx, cy float64, step int) (float64, float64) {
+	angle := float64(step) / patrolOrbitSteps * 2 * math.Pi
+	return cx + math.Cos(angle)*patrolOrbitRadius, cy + math.Sin(angle)*patrolOrbitRadius
+}
+
+// near returns true if the ship is within waypointReach of a point.
+func near(obj *SpaceObject, x, y float64) bool {
+	return math.Hypot(x-float64(obj.X), y-float64(obj.Y)) <= waypointReach
+}
+
+// headToward sets the ship's heading to whichever of the eight directions
+// is closest to the point.
+func headToward(obj *SpaceObject, x, y float64) {
+	dx, dy := x-float64(obj.X), y-float64(obj.Y)
+	const tan22 = 0.4142 // beyond 22.5 degrees off an axis, step along the other too
+	obj.DX, obj.DY = 0, 0
+	if math.Abs(dx) > math.Abs(dy)*tan22 {
+		obj.DX = int(math.Copysign(1, dx))
+	}
+	if math.Abs(dy) > math.Abs(dx)*tan22 {
+		obj.DY = int(math.Copysign(1, dy))
+	}
+}
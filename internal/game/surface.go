@@ -17,10 +17,14 @@ type SurfaceMap struct {
 	Seed      int64  // for deterministic generation
 	PlanetIdx int    // index in SystemMap.Objects
 	POI       string // POI string that triggered this landing
+	Hazard    string // the planet's scan hazard, which brings its weather
 
 	// Fog of war - visibility tracking
-	Visible []bool // tiles currently visible from player position
-	Seen    []bool // tiles player has ever seen (memory)
+	Visible     []bool // tiles currently visible from player position
+	Seen        []bool // tiles player has ever seen (memory)
+	LightRadius int    // how far the player can see, 0 until the sim lights it
+	Night       bool   // on the night side
+	Storm       bool   // a storm is blowing
 }
 
 // ObjectiveKind identifies what the player needs to do.
@@ -41,6 +45,19 @@ type SurfaceObjective struct {
 	Complete    bool
 }
 
+// SetRetrievalObjective turns the objective into a contract pickup:
+// the item waits at the objective location.
+func (sm *SurfaceMap) SetRetrievalObjective(item CargoKind, desc string) {
+	o := sm.Objective
+	if o == nil {
+		return
+	}
+	o.Kind = ObjFindItem
+	o.ItemKind = item
+	o.Description = desc
+	sm.Grid.Set(o.TargetX, o.TargetY, world.TileWithEquipment(world.TileFloor, world.EquipObjective))
+}
+
 // IsWalkable checks if the player can walk to (x, y) on this surface.
 func (sm *SurfaceMap) IsWalkable(x, y int) bool {
 	return sm.Grid.IsWalkable(x, y)
@@ -76,56 +93,19 @@ func (sm *SurfaceMap) InitVisibility() {
 	sm.Seen = make([]bool, size)
 }
 
-// UpdateVisibility recalculates which tiles are visible from the player position.
-// Visibility spreads through walkable tiles but stops at walls and doors.
+// UpdateVisibility recalculates which tiles are visible from the player position:
+// everything in line of sight within the light radius. Walls, rocks and doors
+// can be seen but not seen past.
 func (sm *SurfaceMap) UpdateVisibility() {
 	if sm.Visible == nil {
 		sm.InitVisibility()
 	}
-
-	// Clear current visibility
-	for i := range sm.Visible {
-		sm.Visible[i] = false
-	}
-
-	// Flood fill from player position, stopping at walls and doors
-	visited := make(map[[2]int]bool)
-	queue := [][2]int{{sm.PlayerX, sm.PlayerY}}
-
-	for len(queue) > 0 {
-		pos := queue[0]
-		queue = queue[1:]
-
-		if visited[pos] {
-			continue
-		}
-		visited[pos] = true
-
-		x, y := pos[0], pos[1]
-		if x < 0 || x >= sm.Width || y < 0 || y >= sm.Height {
-			continue
-		}
-
-		tile := sm.Grid.Get(x, y)
-
-		// Mark as visible
-		idx := y*sm.Width + x
-		sm.Visible[idx] = true
-		sm.Seen[idx] = true
-
-		// Stop spreading at walls, rocks, hazards, and doors
-		// (We can see the door/wall itself, but not through it)
-		if tile.Kind == world.TileWall || tile.Kind == world.TileRock ||
-			tile.Kind == world.TileHazard || tile.Kind == world.TileDoor {
-			continue
-		}
-
-		// Spread to neighbors
-		queue = append(queue, [2]int{x - 1, y})
-		queue = append(queue, [2]int{x + 1, y})
-		queue = append(queue, [2]int{x, y - 1})
-		queue = append(queue, [2]int{x, y + 1})
+	clear(sm.Visible)
+	radius := sm.LightRadius
+	if radius <= 0 {
+		radius = daylightSight
 	}
+	castFOV(sm.Grid, sm.PlayerX, sm.PlayerY, radius, sm.Visible, sm.Seen)
 }
 
 // IsVisible returns true if the tile at (x, y) is currently visible.
@@ -7,13 +7,25 @@ import (
 	"github.com/spacehole-rogue/spacehole_rogue/internal/world"
 )
 
-// Surface map dimensions
+// Surface map dimensions. Surfaces are larger than the map viewport, which
+// scrolls to follow the player.
 const (
-	SurfaceWidth  = 40
-	SurfaceHeight = 25
+	SurfaceWidth  = 80
+	SurfaceHeight = 50
 )
 
-// GenerateSurfaceMap creates a surface map from planet and POI data.
+// Surface generation tuning
+const (
+	caveSteps     = 5 // cellular automaton smoothing passes
+	pocketMin     = 8 // cave pockets smaller than this are filled in
+	landingMargin = 6 // structures keep this far from the shuttle pad
+	structSpacing = 3 // and this far from each other
+	structTries   = 200
+)
+
+// GenerateSurfaceMap creates a surface map from planet and POI data: caves
+// grown by cellular automaton, rivers of hazard, the POI's structures and
+// some secondary ruins, all reachable from the shuttle.
 func GenerateSurfaceMap(seed int64, planetIdx int, planetKind PlanetKind, poi string) *SurfaceMap {
 	rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed>>16|3)))
 
@@ -29,25 +41,14 @@ func GenerateSurfaceMap(seed int64, planetIdx int, planetKind PlanetKind, poi st
 		POI:         poi,
 	}
 
-	// Fill with ground
-	for y := 0; y < SurfaceHeight; y++ {
-		for x := 0; x < SurfaceWidth; x++ {
-			sm.Grid.Set(x, y, world.Tile{Kind: world.TileGround})
-		}
-	}
+	// Rock and open ground, smoothed into caves
+	growCaves(sm.Grid, caveFill(planetKind)+rng.Float64()*0.06-0.03, rng)
 
-	// Scatter rocks (15-20%)
-	rockDensity := 0.15 + rng.Float64()*0.05
-	for y := 0; y < SurfaceHeight; y++ {
-		for x := 0; x < SurfaceWidth; x++ {
-			if rng.Float64() < rockDensity {
-				sm.Grid.Set(x, y, world.Tile{Kind: world.TileRock})
-			}
-		}
+	// Rivers of lava, crevasses or fissures, then a few lone hazards (1-3%)
+	for range riverCount(planetKind, rng) {
+		runRiver(sm.Grid, rng)
 	}
-
-	// Scatter some hazards (2-5%)
-	hazardDensity := 0.02 + rng.Float64()*0.03
+	hazardDensity := 0.01 + rng.Float64()*0.02
 	for y := 0; y < SurfaceHeight; y++ {
 		for x := 0; x < SurfaceWidth; x++ {
 			if sm.Grid.Get(x, y).Kind == world.TileGround && rng.Float64() < hazardDensity {
@@ -56,10 +57,10 @@ func GenerateSurfaceMap(seed int64, planetIdx int, planetKind PlanetKind, poi st
 		}
 	}
 
-	// Place shuttle landing pad (bottom center)
+	// Place shuttle landing pad (bottom center) in a cleared landing zone
 	shuttleX := SurfaceWidth / 2
-	shuttleY := SurfaceHeight - 3
-	clearArea(sm.Grid, shuttleX-1, shuttleY-1, 3, 3)
+	shuttleY := SurfaceHeight - 4
+	clearArea(sm.Grid, shuttleX-3, shuttleY-2, 7, 5)
 	sm.Grid.Set(shuttleX, shuttleY, world.Tile{Kind: world.TileShuttlePad})
 	sm.ShuttleX = shuttleX
 	sm.ShuttleY = shuttleY
@@ -68,28 +69,41 @@ func GenerateSurfaceMap(seed int64, planetIdx int, planetKind PlanetKind, poi st
 	sm.PlayerX = shuttleX
 	sm.PlayerY = shuttleY - 1
 
-	// Place structure based on POI
-	structure := poiToStructure(poi)
-	structX := 5 + rng.IntN(SurfaceWidth-15)
-	structY := 3 + rng.IntN(SurfaceHeight-12)
-
-	// Ensure structure doesn't overlap shuttle area
-	if structY+len(structure) > shuttleY-2 {
-		structY = shuttleY - 2 - len(structure)
+	// Place the POI's structures, the first holding the objective, then ruins
+	placed := []footprint{{shuttleX - landingMargin, shuttleY - landingMargin, 2*landingMargin + 1, 2*landingMargin + 1}}
+	structures := poiToStructures(poi)
+	for range 1 + rng.IntN(3) {
+		structures = append(structures, secondaryRuins[rng.IntN(len(secondaryRuins))])
 	}
-	if structY < 2 {
-		structY = 2
+	structX, structY := -1, -1
+	for i, structure := range structures {
+		fp, ok := findStructureSite(placed, structure, rng)
+		if !ok {
+			continue
+		}
+		placed = append(placed, fp)
+		clearArea(sm.Grid, fp.x-1, fp.y-1, fp.w+2, fp.h+2)
+		placeStructure(sm.Grid, fp.x, fp.y, structure)
+		if i == 0 {
+			structX, structY = fp.x, fp.y
+		}
 	}
 
-	placeStructure(sm.Grid, structX, structY, structure)
+	// Everything walkable can be reached from the shuttle
+	ensureDoorsReachable(sm.Grid, sm.PlayerX, sm.PlayerY)
+	connectRegions(sm.Grid, sm.PlayerX, sm.PlayerY)
 
 	// Place objective inside structure (find the terminal or place an objective marker)
-	objX, objY := findEquipmentInStructure(sm.Grid, structX, structY, structure, world.EquipTerminal)
-	if objX < 0 {
-		// No terminal, find a floor tile and place objective marker
-		objX, objY = findFloorInStructure(sm.Grid, structX, structY, structure)
-		if objX >= 0 {
-			sm.Grid.Set(objX, objY, world.TileWithEquipment(world.TileFloor, world.EquipObjective))
+	objX, objY := -1, -1
+	if structX >= 0 {
+		structure := structures[0]
+		objX, objY = findEquipmentInStructure(sm.Grid, structX, structY, structure, world.EquipTerminal)
+		if objX < 0 {
+			// No terminal, find a floor tile and place objective marker
+			objX, objY = findFloorInStructure(sm.Grid, structX, structY, structure)
+			if objX >= 0 {
+				sm.Grid.Set(objX, objY, world.TileWithEquipment(world.TileFloor, world.EquipObjective))
+			}
 		}
 	}
 
@@ -117,6 +131,171 @@ func GenerateSurfaceMap(seed int64, planetIdx int, planetKind PlanetKind, poi st
 	return sm
 }
 
+// caveFill is how much of a planet starts as rock before smoothing.
+// Ice fields are open; volcanic rock is broken up.
+func caveFill(kind PlanetKind) float64 {
+	switch kind {
+	case PlanetIce:
+		return 0.38
+	case PlanetVolcanic:
+		return 0.44
+	default:
+		return 0.41
+	}
+}
+
+// riverCount rolls how many hazard rivers cross a planet's surface.
+func riverCount(kind PlanetKind, rng *rand.Rand) int {
+	switch kind {
+	case PlanetVolcanic:
+		return 2 + rng.IntN(2) // lava flows
+	case PlanetIce:
+		return 1 + rng.IntN(2) // crevasses
+	default:
+		return rng.IntN(2) // fissures
+	}
+}
+
+// growCaves fills the grid with rock at the given density and smooths it
+// with a cellular automaton: rock with 4 or more rock neighbours stays, and
+// ground with 5 or more turns to rock. The map edge counts as rock, so the
+// caves close off at the border.
+func growCaves(grid *world.TileGrid, fill float64, rng *rand.Rand) {
+	w, h := grid.Width, grid.Height
+	rock := make([]bool, w*h)
+	for i := range rock {
+		rock[i] = rng.Float64() < fill
+	}
+	next := make([]bool, w*h)
+	for range caveSteps {
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				n := 0
+				for dy := -1; dy <= 1; dy++ {
+					for dx := -1; dx <= 1; dx++ {
+						nx, ny := x+dx, y+dy
+						if (dx != 0 || dy != 0) && (nx < 0 || nx >= w || ny < 0 || ny >= h || rock[ny*w+nx]) {
+							n++
+						}
+					}
+				}
+				i := y*w + x
+				next[i] = n >= 5 || rock[i] && n >= 4
+			}
+		}
+		rock, next = next, rock
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			kind := world.TileGround
+			if rock[y*w+x] {
+				kind = world.TileRock
+			}
+			grid.Set(x, y, world.Tile{Kind: kind})
+		}
+	}
+}
+
+// runRiver lays a meandering river of hazard 1-2 tiles wide from one edge
+// of the map to the other, across or down. It cuts through rock.
+func runRiver(grid *world.TileGrid, rng *rand.Rand) {
+	across := rng.IntN(2) == 0
+	length, span := grid.Width, grid.Height
+	if !across {
+		length, span = span, length
+	}
+	width := 1 + rng.IntN(2)
+	pos := span/5 + rng.IntN(span*3/5)
+	set := func(along, at int) {
+		for i := range width {
+			if across {
+				grid.Set(along, at+i, world.Tile{Kind: world.TileHazard})
+			} else {
+				grid.Set(at+i, along, world.Tile{Kind: world.TileHazard})
+			}
+		}
+	}
+	for along := range length {
+		set(along, pos)
+		if rng.IntN(3) == 0 {
+			pos = clampInt(pos+rng.IntN(3)-1, 2, span-width-2)
+			set(along, pos) // close the corner so the river has no gaps
+		}
+	}
+}
+
+// footprint is the area a structure takes up on the surface.
+type footprint struct{ x, y, w, h int }
+
+// overlaps returns true if two footprints come within gap tiles of each other.
+func (f footprint) overlaps(o footprint, gap int) bool {
+	return f.x-gap < o.x+o.w && o.x-gap < f.x+f.w && f.y-gap < o.y+o.h && o.y-gap < f.y+f.h
+}
+
+// findStructureSite finds somewhere on the surface for a structure, clear
+// of the landing zone and everything already placed.
+func findStructureSite(placed []footprint, template []string, rng *rand.Rand) (footprint, bool) {
+	fp := footprint{w: templateWidth(template), h: len(template)}
+	for range structTries {
+		fp.x = 2 + rng.IntN(SurfaceWidth-fp.w-4)
+		fp.y = 2 + rng.IntN(SurfaceHeight-fp.h-4)
+		clear := true
+		for _, o := range placed {
+			if fp.overlaps(o, structSpacing) {
+				clear = false
+				break
+			}
+		}
+		if clear {
+			return fp, true
+		}
+	}
+	return fp, false
+}
+
+// templateWidth returns the width of a structure template's widest row.
+func templateWidth(template []string) int {
+	w := 0
+	for _, row := range template {
+		w = max(w, len(row))
+	}
+	return w
+}
+
+// connectRegions makes every walkable region reachable from (playerX,
+// playerY), carving a path from the nearest reachable tile to each one.
+// Pockets too small to be worth the walk are filled in with rock instead.
+func connectRegions(grid *world.TileGrid, playerX, playerY int) {
+	reachable := floodFillReachable(grid, playerX, playerY)
+	tried := make(map[[2]int]bool)
+	for y := 0; y < grid.Height; y++ {
+		for x := 0; x < grid.Width; x++ {
+			pos := [2]int{x, y}
+			if reachable[pos] || tried[pos] || !grid.IsWalkable(x, y) {
+				continue
+			}
+			region := floodFillReachable(grid, x, y)
+			for p := range region {
+				tried[p] = true
+			}
+			if len(region) < pocketMin {
+				for p := range region {
+					if grid.Get(p[0], p[1]).Kind == world.TileGround {
+						grid.Set(p[0], p[1], world.Tile{Kind: world.TileRock})
+					}
+				}
+				continue
+			}
+			nearest := findNearestReachable(grid, x, y, reachable)
+			if nearest[0] == -1 {
+				continue
+			}
+			carvePath(grid, nearest[0], nearest[1], x, y)
+			reachable = floodFillReachable(grid, playerX, playerY)
+		}
+	}
+}
+
 func planetToTerrain(kind PlanetKind) world.TerrainType {
 	switch kind {
 	case PlanetRocky:
@@ -172,7 +351,7 @@ var structureResearchLab = []string{
 var structureCrashSite = []string{
 	"  ###  ",
 	" #L..# ",
-	"#....#+",
+	"#.....+",
 	" #..T# ",
 	"  ###  ",
 }
@@ -185,19 +364,36 @@ var structureOutpost = []string{
 	"#####",
 }
 
-func poiToStructure(poi string) []string {
+// Secondary ruins are scattered over every surface for loot.
+var structureRuinedHut = []string{
+	"#.##",
+	"#L.#",
+	"##+#",
+}
+
+var structureRuinedWall = []string{
+	"##  #",
+	"#L  +",
+	"# ###",
+}
+
+var secondaryRuins = [][]string{structureRuinedHut, structureRuinedWall, structureRuins}
+
+// poiToStructures returns the structures a POI brings. The first holds the
+// landing's objective.
+func poiToStructures(poi string) [][]string {
 	poiLower := strings.ToLower(poi)
 	switch {
 	case strings.Contains(poiLower, "ancient") || strings.Contains(poiLower, "structure"):
-		return structureRuins
+		return [][]string{structureRuins, structureRuins, structureRuins}
 	case strings.Contains(poiLower, "mining") || strings.Contains(poiLower, "rig"):
-		return structureMiningRig
+		return [][]string{structureMiningRig, structureOutpost}
 	case strings.Contains(poiLower, "thermal") || strings.Contains(poiLower, "research"):
-		return structureResearchLab
+		return [][]string{structureResearchLab, structureOutpost}
 	case strings.Contains(poiLower, "debris") || strings.Contains(poiLower, "crash"):
-		return structureCrashSite
+		return [][]string{structureCrashSite, structureCrashSite}
 	default:
-		return structureOutpost
+		return [][]string{structureOutpost}
 	}
 }
 
@@ -16,6 +16,7 @@ const (
 	ObjDerelict
 	ObjAsteroid
 	ObjShip
+	ObjMonkeyLion // the USS Monkey Lion, once the clue trail leads to its system
 )
 
 // PlanetKind determines planet visuals and description.
@@ -47,8 +48,12 @@ type SpaceObject struct {
 	DX, DY     int        // movement direction for ships
 	MoveRate   int        // ticks between moves for ships
 	moveTimer  int
-	dirTimer   int  // ticks until next direction change
-	Hailed     bool // true once this ship has hailed the player (won't hail again)
+	dirTimer   int   // ticks until next direction change
+	Waypoint   int   // trader: leg of its trade route; patrol: step around its orbit
+	Hailed     bool  // true once this ship has hailed the player (won't hail again)
+	Pursuing   bool  // pirate chasing the shuttle after it fled or ignored the hail
+	Faction    int   // index into Sector.Factions, for ships and stations
+	Orbit      Orbit // path around the star or a planet, for planets, moons and stations
 }
 
 // System map dimensions (scrolling space, much larger than screen).
@@ -176,15 +181,18 @@ type SystemMap struct {
 	Shuttle       ShipPhysics  // player shuttle (float position, velocity, physics)
 	Station       *StationData // generated on first dock, nil if no station
 	rng           *rand.Rand
-	seed          int64 // saved for station data generation
+	src           *rand.PCG // rng source, kept so its state can be saved
+	seed          int64     // saved for station data generation
 	starName      string
 }
 
 var romanNumerals = []string{"I", "II", "III", "IV", "V"}
 
 // GenerateSystemMap creates a new system map from a seed.
-func GenerateSystemMap(seed int64, starType StarType, starName string) *SystemMap {
-	rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed>>16|1)))
+// Ships and stations are assigned to the sector's factions.
+func GenerateSystemMap(seed int64, starType StarType, starName string, factions []Faction) *SystemMap {
+	src := rand.NewPCG(uint64(seed), uint64(seed>>16|1))
+	rng := rand.New(src)
 
 	sm := &SystemMap{
 		Width:  SystemMapW,
@@ -194,6 +202,7 @@ func GenerateSystemMap(seed int64, starType StarType, starName string) *SystemMa
 			Accel: ShuttleAccel, MaxSpeed: ShuttleMaxSpeed, Drag: ShuttleDrag,
 		},
 		rng:      rng,
+		src:      src,
 		seed:     seed,
 		starName: starName,
 	}
@@ -225,6 +234,7 @@ func GenerateSystemMap(seed int64, starType StarType, starName string) *SystemMa
 			X:          px,
 			Y:          py,
 			PlanetType: PlanetKind(rng.IntN(4)),
+			Orbit:      Orbit{Radius: dist, Phase: angle, Period: keplerPeriod(orbitBasePeriod, orbitBaseRadius, dist)},
 		})
 	}
 
@@ -237,11 +247,15 @@ func GenerateSystemMap(seed int64, starType StarType, starName string) *SystemMa
 		sx = clampInt(sx, 2, SystemMapW-2)
 		sy = clampInt(sy, 2, SystemMapH-2)
 
+		// It circles its planet where it was placed
+		ox, oy := float64(sx-planet.X), float64(sy-planet.Y)/orbitSquash
+		r := math.Hypot(ox, oy)
 		sm.Objects = append(sm.Objects, SpaceObject{
-			Kind: ObjStation,
-			Name: starName + " Station",
-			X:    sx,
-			Y:    sy,
+			Kind:  ObjStation,
+			Name:  starName + " Station",
+			X:     sx,
+			Y:     sy,
+			Orbit: Orbit{Parent: pidx, Radius: r, Phase: math.Atan2(oy, ox), Period: keplerPeriod(moonBasePeriod, moonBaseRadius, r)},
 		})
 	}
 
@@ -286,9 +300,35 @@ func GenerateSystemMap(seed int64, starType StarType, starName string) *SystemMa
 		})
 	}
 
+	assignFactions(sm.Objects, seed, factions)
+	addMoons(sm, seed)
 	return sm
 }
 
+// assignFactions picks factions for the system's ships and station.
+// It uses its own rng so the rest of the system generates the same as before.
+func assignFactions(objs []SpaceObject, seed int64, factions []Faction) {
+	rng := rand.New(rand.NewPCG(uint64(seed), uint64(seed>>16|9)))
+	for i := range objs {
+		obj := &objs[i]
+		switch {
+		case obj.Kind == ObjStation:
+			// Most stations are run by the authority, the rest by a guild
+			if rng.IntN(5) < 2 {
+				obj.Faction = pickFaction(factions, FactionTraders, rng)
+			} else {
+				obj.Faction = pickFaction(factions, FactionAuthority, rng)
+			}
+		case obj.Kind == ObjShip && obj.AIKind == AIPirate:
+			obj.Faction = pickFaction(factions, FactionPirates, rng)
+		case obj.Kind == ObjShip && obj.AIKind == AITrader:
+			obj.Faction = pickFaction(factions, FactionTraders, rng)
+		case obj.Kind == ObjShip:
+			obj.Faction = pickFaction(factions, FactionAuthority, rng)
+		}
+	}
+}
+
 func shipAIParams(kind ShipAIKind) (moveRate, dirTime int) {
 	switch kind {
 	case AITrader:
@@ -327,10 +367,16 @@ func (sm *SystemMap) TickNPCs() {
 			continue
 		}
 
+		// Steer toward the ship's destination, or wander without one
+		obj.dirTimer--
+		if !sm.steer(obj) && obj.dirTimer <= 0 {
+			sm.changeShipDirection(obj)
+		}
+
 		// Movement tick
 		obj.moveTimer--
 		if obj.moveTimer <= 0 {
-			obj.moveTimer = obj.MoveRate
+			obj.moveTimer = obj.moveRate()
 			obj.X += obj.DX
 			obj.Y += obj.DY
 
@@ -344,50 +390,23 @@ func (sm *SystemMap) TickNPCs() {
 				obj.Y = clampInt(obj.Y, 1, sm.Height-2)
 			}
 		}
-
-		// Direction change tick
-		obj.dirTimer--
-		if obj.dirTimer <= 0 {
-			sm.changeShipDirection(obj)
-		}
 	}
 }
 
+// changeShipDirection picks a random heading for a ship with nowhere to go.
 func (sm *SystemMap) changeShipDirection(obj *SpaceObject) {
-	sx := sm.Shuttle.TileX()
-	sy := sm.Shuttle.TileY()
-
+	obj.DX = sm.rng.IntN(3) - 1
+	obj.DY = sm.rng.IntN(3) - 1
+	if obj.DX == 0 && obj.DY == 0 {
+		obj.DX = 1
+	}
 	switch obj.AIKind {
+	case AITrader:
+		obj.dirTimer = 300 + sm.rng.IntN(60)
 	case AIPirate:
-		// Bias toward shuttle
-		if sx > obj.X {
-			obj.DX = 1
-		} else if sx < obj.X {
-			obj.DX = -1
-		} else {
-			obj.DX = 0
-		}
-		if sy > obj.Y {
-			obj.DY = 1
-		} else if sy < obj.Y {
-			obj.DY = -1
-		} else {
-			obj.DY = 0
-		}
 		obj.dirTimer = 90 + sm.rng.IntN(60)
-
 	default:
-		// Random wander
-		obj.DX = sm.rng.IntN(3) - 1
-		obj.DY = sm.rng.IntN(3) - 1
-		if obj.DX == 0 && obj.DY == 0 {
-			obj.DX = 1
-		}
-		if obj.AIKind == AITrader {
-			obj.dirTimer = 300 + sm.rng.IntN(60)
-		} else {
-			obj.dirTimer = 200 + sm.rng.IntN(40)
-		}
+		obj.dirTimer = 200 + sm.rng.IntN(40)
 	}
 }
 
@@ -418,6 +437,7 @@ func (sm *SystemMap) EnsureStationData() *StationData {
 	for _, obj := range sm.Objects {
 		if obj.Kind == ObjStation {
 			sm.Station = GenerateStationData(sm.seed+999, obj.Name)
+			sm.Station.FactionID = obj.Faction
 			return sm.Station
 		}
 	}
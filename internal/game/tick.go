@@ -0,0 +1,72 @@
+package game
+
+// TickPolicy decides when the simulation advances. The windowed game asks it
+// how many ticks to run each frame and tells it about every action the player
+// applies, so the same Sim runs in real time or as a classic turn-based
+// roguelike. Nothing in the Sim knows which policy is driving it, and a
+// replay is the same stream of ticks and actions either way.
+type TickPolicy interface {
+	// Ticks returns how many ticks to run this frame.
+	Ticks() int
+	// OnAction is called after the player applies an action that took effect.
+	OnAction(a Action)
+	// Wait lets time pass without acting.
+	Wait(ticks int)
+}
+
+// RealTime ticks once a frame, whatever the player does: 60 ticks a second.
+type RealTime struct{}
+
+func (RealTime) Ticks() int      { return 1 }
+func (RealTime) OnAction(Action) {}
+func (RealTime) Wait(int)        {}
+
+// TurnBased only advances when the player acts, by the action's cost in
+// ticks. Waiting passes time on purpose.
+type TurnBased struct {
+	pending int
+}
+
+// Ticks returns the ticks owed since the last frame.
+func (t *TurnBased) Ticks() int {
+	n := t.pending
+	t.pending = 0
+	return n
+}
+
+func (t *TurnBased) OnAction(a Action) { t.pending += ActionTicks(a.Kind) }
+func (t *TurnBased) Wait(ticks int)    { t.pending += ticks }
+
+// WaitTicks is how long one wait passes in turn-based play: one second of
+// real-time play.
+const WaitTicks = 60
+
+// actionTicks is how long an action takes in turn-based play. Menus,
+// dialogue and trading take no time, so briefings can be read at leisure.
+var actionTicks = map[ActionKind]int{
+	ActMove:            20,
+	ActInteract:        60,
+	ActToggle:          30,
+	ActLaunch:          120,
+	ActScan:            120,
+	ActThrust:          1, // held every frame, like real time
+	ActDock:            120,
+	ActEnterOrbit:      120,
+	ActLeaveOrbit:      60,
+	ActRepairHull:      300,
+	ActVisitBar:        600,
+	ActJettison:        30,
+	ActIncinerate:      60,
+	ActSurfaceMove:     20,
+	ActSurfaceInteract: 60,
+	ActBoardShuttle:    60,
+	ActExitShuttle:     60,
+	ActRepairEquipment: 120,
+	ActBoardMonkeyLion: 120,
+	ActFireSidearm:     30,
+}
+
+// ActionTicks returns how many ticks an action takes in turn-based play.
+func ActionTicks(k ActionKind) int {
+	return actionTicks[k]
+}
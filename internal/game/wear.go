@@ -0,0 +1,175 @@
+package game
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/spacehole-rogue/spacehole_rogue/internal/world"
+)
+
+// Wear check interval. Every running-wear rate below is a multiple of it.
+const wearCheckInterval = 1200 // every 20 seconds
+
+// runningWear is how many ticks of running cost one point of condition
+// for constant-draw machinery (~8 game days from new to broken for the generator).
+var runningWear = map[world.EquipmentKind]uint64{
+	world.EquipGenerator:      6000,
+	world.EquipMatterRecycler: 4800,
+	world.EquipEngine:         7200,
+	world.EquipLifeSupport:    9600,
+}
+
+// useWear is how much condition one activation of on-use equipment costs.
+var useWear = map[world.EquipmentKind]int{
+	world.EquipFoodStation:  1,
+	world.EquipDrinkStation: 1,
+	world.EquipShower:       1,
+	world.EquipMedical:      1,
+	world.EquipIncinerator:  1,
+	world.EquipJumpDrive:    5, // jump stress
+	world.EquipWeapon:       1, // per shot
+}
+
+// Repair amounts before the Engineering bonus.
+const (
+	toolKitRepair    = 25 // field repair; can't fix broken gear below Engineering 5
+	sparePartsRepair = 50
+)
+
+// tickWear wears down running constant-draw machinery.
+func (s *Sim) tickWear() {
+	if s.Ticks%wearCheckInterval != 0 {
+		return
+	}
+	for i := range s.Grid.Tiles {
+		eq := s.Grid.Tiles[i].Equipment
+		if eq == nil || !eq.On {
+			continue
+		}
+		if rate, ok := runningWear[eq.Kind]; ok && s.Ticks%rate == 0 {
+			s.wear(eq, 1)
+		}
+	}
+}
+
+// useEquipment deducts on-use power and applies use wear.
+func (s *Sim) useEquipment(eq *world.Equipment) {
+	eq.Use(&s.Resources.Energy)
+	if amount := useWear[eq.Kind]; amount > 0 {
+		s.wear(eq, amount)
+	}
+}
+
+// wear degrades equipment and warns as it wears out or breaks.
+func (s *Sim) wear(eq *world.Equipment, amount int) {
+	if eq.IsBroken() {
+		return
+	}
+	before := eq.Condition
+	eq.Degrade(amount)
+	switch {
+	case eq.IsBroken():
+		s.Log.Add(fmt.Sprintf("%s broke down! Repair it with spare parts.", eq.Name()), MsgCritical)
+	case before > 25 && eq.Condition <= 25:
+		s.Log.Add(fmt.Sprintf("%s is wearing out (%d%%). Repair it soon.", eq.Name(), eq.Condition), MsgWarning)
+	}
+}
+
+// bestRunning returns the running, unbroken equipment of a kind with the
+// highest efficiency, or nil if none is running.
+func (s *Sim) bestRunning(kind world.EquipmentKind) *world.Equipment {
+	var best *world.Equipment
+	for i := range s.Grid.Tiles {
+		eq := s.Grid.Tiles[i].Equipment
+		if eq == nil || eq.Kind != kind || !eq.On || eq.IsBroken() {
+			continue
+		}
+		if best == nil || eq.Efficiency > best.Efficiency {
+			best = eq
+		}
+	}
+	return best
+}
+
+// scaledInterval stretches a production interval for less efficient equipment.
+func scaledInterval(base int, efficiency float64) uint64 {
+	if efficiency <= 0 {
+		efficiency = 0.01
+	}
+	return uint64(max(1, int(math.Round(float64(base)/efficiency))))
+}
+
+// RepairEquipment patches a hull breach next to the player, or else repairs
+// the equipment on the player's tile.
+// A tool kit is used first for working gear; broken gear needs spare parts,
+// from inventory or cargo, unless Engineering is high enough to jury-rig it.
+// Returns true if anything was repaired.
+func (s *Sim) RepairEquipment() bool {
+	px, py := s.PlayerPos()
+	// A hull breach within reach comes before any equipment
+	if room, hole := s.breachInReach(); hole >= 0 {
+		return s.patchBreach(room, hole)
+	}
+	eq := s.Grid.GetEquipment(px, py)
+	if eq == nil {
+		s.Log.Add("Nothing to repair here.", MsgSocial)
+		return false
+	}
+	if eq.Condition >= 100 {
+		s.Log.Add(fmt.Sprintf("%s is in perfect condition.", eq.Name()), MsgInfo)
+		return false
+	}
+
+	r := &s.Resources
+	level := s.SkillCheck(SkillEngineering)
+	juryRig := level >= 5 // "Jury-rig solutions from spare parts"
+
+	var source string
+	var base int
+	var xp float64
+	switch {
+	case r.Inventory.HasItem(ItemToolKit) && (!eq.IsBroken() || juryRig):
+		r.Inventory.RemoveItem(ItemToolKit, 1)
+		source, base, xp = ItemName(ItemToolKit), toolKitRepair, 3.0
+	case r.Inventory.HasItem(ItemSpareParts):
+		r.Inventory.RemoveItem(ItemSpareParts, 1)
+		source, base, xp = ItemName(ItemSpareParts), sparePartsRepair, 5.0
+	case r.RemoveCargo(CargoSpareParts, 1) > 0:
+		source, base, xp = CargoName(CargoSpareParts)+" from cargo", sparePartsRepair, 5.0
+	case eq.IsBroken():
+		s.Log.Add(fmt.Sprintf("%s is broken. Need spare parts to fix it.", eq.Name()), MsgWarning)
+		return false
+	default:
+		s.Log.Add("Need a tool kit or spare parts to repair this.", MsgWarning)
+		return false
+	}
+
+	amount := RepairAmount(base, level)
+	wasBroken := eq.IsBroken()
+	before := eq.Condition
+	eq.Repair(amount)
+	s.Log.Add(fmt.Sprintf("Repaired %s with %s. Condition %d%% -> %d%%.",
+		eq.Name(), source, before, eq.Condition), MsgInfo)
+	if wasBroken {
+		msg := fmt.Sprintf("%s is working again.", eq.Name())
+		if eq.PowerMode == world.PowerConstant {
+			msg += " Press T to power it up."
+		}
+		s.Log.Add(msg, MsgDiscovery)
+	}
+	if s.Skills.AddXP(SkillEngineering, xp) {
+		LogLevelUp(s.Log, SkillEngineering, s.Skills.Level(SkillEngineering))
+	}
+	return true
+}
+
+// RepairAmount returns how much condition a repair restores at an
+// Engineering level: +3 per level past the first, doubled at level 9
+// ("Master engineer").
+func RepairAmount(base, engineeringLevel int) int {
+	amount := base + 3*(engineeringLevel-1)
+	if engineeringLevel >= 9 {
+		amount *= 2
+	}
+	return amount
+}
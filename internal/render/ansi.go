@@ -0,0 +1,173 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+)
+
+// TermScreen draws a CellBuffer to a terminal with ANSI escape codes. Each
+// cell takes two terminal columns, the glyph and a space, so an 80x45 grid
+// needs a 160x45 terminal and keeps the window's square cells. Tight UI
+// text and floating sprites are placed on the same grid: 8 pixels to a
+// column and one cell height to a row.
+type TermScreen struct {
+	Cols      int  // terminal columns
+	Rows      int  // terminal rows
+	TrueColor bool // 24-bit colour; otherwise the 16 ANSI colours
+
+	cur, prev []Cell
+	cellH     int  // pixel height of a row in the layout the UI is drawn to
+	full      bool // redraw everything on the next Flush
+}
+
+// termColor maps the CGA palette order onto the ANSI colour order.
+var termColor = [18]int{
+	ColorBlack:        0,
+	ColorBlue:         4,
+	ColorGreen:        2,
+	ColorCyan:         6,
+	ColorRed:          1,
+	ColorMagenta:      5,
+	ColorBrown:        3,
+	ColorLightGray:    7,
+	ColorDarkGray:     8,
+	ColorLightBlue:    12,
+	ColorLightGreen:   10,
+	ColorLightCyan:    14,
+	ColorLightRed:     9,
+	ColorLightMagenta: 13,
+	ColorYellow:       11,
+	ColorWhite:        15,
+	ColorHUDBG:        0,
+	ColorDarkGold:     3,
+}
+
+// NewTermScreen creates a terminal screen for a grid of cols x rows cells,
+// cellH pixels high in the layout the UI is drawn to.
+func NewTermScreen(cols, rows, cellH int) *TermScreen {
+	n := 2 * cols * rows
+	return &TermScreen{
+		Cols:  2 * cols,
+		Rows:  rows,
+		cur:   make([]Cell, n),
+		prev:  make([]Cell, n),
+		cellH: cellH,
+		full:  true,
+	}
+}
+
+// Draw starts a frame from the cell buffer.
+func (t *TermScreen) Draw(buf *CellBuffer) {
+	for y := 0; y < t.Rows; y++ {
+		for x := 0; x < t.Cols; x++ {
+			c := buf.Get(x/2, y)
+			if x%2 == 1 {
+				c.Glyph = ' '
+			}
+			t.cur[y*t.Cols+x] = c
+		}
+	}
+}
+
+// DrawFloating places a glyph given in pixels on the nearest column and row,
+// over the background already there.
+func (t *TermScreen) DrawFloating(glyph byte, fg uint8, px, py float64) {
+	if glyph == ' ' || glyph == 0 {
+		return
+	}
+	t.put(int(math.Round(px/TextCharWidth)), int(math.Round(py/float64(t.cellH))), glyph, fg)
+}
+
+// DrawText writes tight UI text given in pixels, a character to a column.
+// Spaces leave what is underneath, as they do in the window.
+func (t *TermScreen) DrawText(x, y int, s string, fg uint8) {
+	col, row := x/TextCharWidth, y/t.cellH
+	for _, ch := range s {
+		if ch > 255 {
+			ch = '?'
+		}
+		if ch != ' ' && ch != 0 {
+			t.put(col, row, byte(ch), fg)
+		}
+		col++
+	}
+}
+
+func (t *TermScreen) put(col, row int, glyph byte, fg uint8) {
+	if col < 0 || col >= t.Cols || row < 0 || row >= t.Rows {
+		return
+	}
+	c := &t.cur[row*t.Cols+col]
+	c.Glyph, c.FG = glyph, fg
+}
+
+// Invalidate makes the next Flush redraw the whole screen, after the
+// terminal was resized or scribbled on.
+func (t *TermScreen) Invalidate() {
+	t.full = true
+}
+
+// Flush writes what changed since the last frame.
+func (t *TermScreen) Flush(w io.Writer) error {
+	bw := bufio.NewWriterSize(w, 64*1024)
+	if t.full {
+		bw.WriteString("\x1b[0m\x1b[2J")
+	}
+	fg, bg := -1, -1
+	atX, atY := -1, -1
+	for y := 0; y < t.Rows; y++ {
+		for x := 0; x < t.Cols; x++ {
+			i := y*t.Cols + x
+			c := t.cur[i]
+			if !t.full && c == t.prev[i] {
+				continue
+			}
+			if x != atX || y != atY {
+				fmt.Fprintf(bw, "\x1b[%d;%dH", y+1, x+1)
+			}
+			if int(c.FG) != fg || int(c.BG) != bg {
+				fg, bg = int(c.FG), int(c.BG)
+				t.writeColors(bw, c.FG, c.BG)
+			}
+			r := CP437ToUnicode[c.Glyph]
+			if r < ' ' {
+				r = ' '
+			}
+			bw.WriteRune(r)
+			atX, atY = x+1, y
+		}
+	}
+	if fg >= 0 {
+		bw.WriteString("\x1b[0m")
+	}
+	copy(t.prev, t.cur)
+	t.full = false
+	return bw.Flush()
+}
+
+// writeColors sets the foreground and background for the cells that follow.
+func (t *TermScreen) writeColors(w *bufio.Writer, fg, bg uint8) {
+	if int(fg) >= len(Palette) {
+		fg = ColorWhite
+	}
+	if int(bg) >= len(Palette) {
+		bg = ColorBlack
+	}
+	if t.TrueColor {
+		f, b := Palette[fg], Palette[bg]
+		fmt.Fprintf(w, "\x1b[38;2;%d;%d;%d;48;2;%d;%d;%dm", f.R, f.G, f.B, b.R, b.G, b.B)
+		return
+	}
+	fmt.Fprintf(w, "\x1b[%d;%dm", ansiCode(termColor[fg], 30), ansiCode(termColor[bg], 40))
+}
+
+// ansiCode returns the SGR code for an ANSI colour: base is 30 for
+// foreground and 40 for background, and the bright colours sit 60 above.
+func ansiCode(c, base int) int {
+	if c >= 8 {
+		return base + 60 + c - 8
+	}
+	return base + c
+}
@@ -1,3 +1,5 @@
+//go:build !term
+
 package render
 
 import (
@@ -0,0 +1,75 @@
+package render
+
+// Cell represents a single character cell on screen.
+type Cell struct {
+	Glyph byte  // CP437 code (0-255)
+	FG    uint8 // Foreground color index (0-15)
+	BG    uint8 // Background color index (0-15)
+}
+
+// CellBuffer is a 2D grid of character cells.
+type CellBuffer struct {
+	Cols  int
+	Rows  int
+	Cells []Cell
+}
+
+// NewCellBuffer creates a new cell buffer filled with blank cells.
+func NewCellBuffer(cols, rows int) *CellBuffer {
+	cells := make([]Cell, cols*rows)
+	for i := range cells {
+		cells[i] = Cell{Glyph: ' ', FG: ColorWhite, BG: ColorBlack}
+	}
+	return &CellBuffer{Cols: cols, Rows: rows, Cells: cells}
+}
+
+// Set writes a single cell at (x, y). Out-of-bounds writes are ignored.
+func (b *CellBuffer) Set(x, y int, glyph byte, fg, bg uint8) {
+	if x >= 0 && x < b.Cols && y >= 0 && y < b.Rows {
+		b.Cells[y*b.Cols+x] = Cell{Glyph: glyph, FG: fg, BG: bg}
+	}
+}
+
+// Get reads a single cell at (x, y). Out-of-bounds reads return a blank cell.
+func (b *CellBuffer) Get(x, y int) Cell {
+	if x >= 0 && x < b.Cols && y >= 0 && y < b.Rows {
+		return b.Cells[y*b.Cols+x]
+	}
+	return Cell{}
+}
+
+// Clear resets all cells to blank (space on black).
+func (b *CellBuffer) Clear() {
+	for i := range b.Cells {
+		b.Cells[i] = Cell{Glyph: ' ', FG: ColorWhite, BG: ColorBlack}
+	}
+}
+
+// WriteString writes a string starting at (x, y). Each rune occupies one cell.
+func (b *CellBuffer) WriteString(x, y int, s string, fg, bg uint8) {
+	offset := 0
+	for _, ch := range s {
+		if ch > 255 {
+			ch = '?'
+		}
+		b.Set(x+offset, y, byte(ch), fg, bg)
+		offset++
+	}
+}
+
+// FillRect fills a rectangular area with a background color (space glyphs).
+func (b *CellBuffer) FillRect(x, y, w, h int, bg uint8) {
+	for dy := 0; dy < h; dy++ {
+		for dx := 0; dx < w; dx++ {
+			b.Set(x+dx, y+dy, ' ', ColorWhite, bg)
+		}
+	}
+}
+
+// TextCharWidth is the pixel width for tight text rendering (8px for 7px font + 1px gap).
+const TextCharWidth = 8
+
+// TextWidth returns the pixel width of a string in tight text mode.
+func TextWidth(s string) int {
+	return len(s) * TextCharWidth
+}
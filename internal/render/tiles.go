@@ -4,10 +4,20 @@ import "github.com/spacehole-rogue/spacehole_rogue/internal/world"
 
 // RenderTileGrid writes a TileGrid into a CellBuffer at the given offset.
 func RenderTileGrid(buf *CellBuffer, grid *world.TileGrid, offsetX, offsetY int) {
+	RenderTileGridLit(buf, grid, offsetX, offsetY, nil)
+}
+
+// RenderTileGridLit writes a TileGrid with only the tiles isLit reports
+// lit drawn in full; the rest are remembered in dark gray. A nil isLit
+// lights everything.
+func RenderTileGridLit(buf *CellBuffer, grid *world.TileGrid, offsetX, offsetY int, isLit func(x, y int) bool) {
 	for y := 0; y < grid.Height; y++ {
 		for x := 0; x < grid.Width; x++ {
 			tile := grid.Get(x, y)
 			glyph, fg, bg := tileVisuals(tile)
+			if isLit != nil && !isLit(x, y) && tile.Kind != world.TileVoid {
+				fg, bg = ColorDarkGray, ColorBlack
+			}
 			buf.Set(offsetX+x, offsetY+y, glyph, fg, bg)
 		}
 	}
@@ -15,7 +25,11 @@ func RenderTileGrid(buf *CellBuffer, grid *world.TileGrid, offsetX, offsetY int)
 
 func tileVisuals(t world.Tile) (glyph byte, fg, bg uint8) {
 	if t.Equipment != nil {
-		return equipVisuals(t.Equipment)
+		glyph, fg, bg = equipVisuals(t.Equipment)
+		if t.Equipment.IsBroken() {
+			fg = ColorRed // broken until repaired
+		}
+		return glyph, fg, bg
 	}
 
 	switch t.Kind {
@@ -109,6 +123,25 @@ func equipVisuals(e *world.Equipment) (glyph byte, fg, bg uint8) {
 		return '%', ColorDarkGray, ColorBlack
 	case world.EquipJumpDrive:
 		return 'J', ColorLightMagenta, ColorBlack // jump drive (purple = FTL)
+	// --- combat ---
+	case world.EquipWeapon:
+		// Toggleable - show darker when not armed
+		if e.On {
+			return 237, ColorLightRed, ColorBlack // φ pulse cannon
+		}
+		return 237, ColorDarkGray, ColorBlack
+	case world.EquipShield:
+		if e.On {
+			return 233, ColorLightBlue, ColorBlack // Θ shield emitter
+		}
+		return 233, ColorDarkGray, ColorBlack
+	// --- life support ---
+	case world.EquipLifeSupport:
+		// Toggleable - show darker when OFF
+		if e.On {
+			return 247, ColorLightCyan, ColorBlack // ≈ air scrubber
+		}
+		return 247, ColorDarkGray, ColorBlack
 	// --- cargo ---
 	case world.EquipCargoTile:
 		return 176, ColorDarkGray, ColorBlack // ░ cargo pad
@@ -56,6 +56,11 @@ var EquipmentTemplates = map[EquipmentKind]EquipmentTemplate{
 	EquipGenerator:        {EquipGenerator, PowerConstant, 10, 1.0}, // needs 10 to run, produces 1/sec
 	EquipMatterRecycler:   {EquipMatterRecycler, PowerConstant, 10, 1.0},
 	EquipCargoTransporter: {EquipCargoTransporter, PowerConstant, 10, 1.0},
+	// Combat systems - armed weapons and raised shields hold their charge
+	EquipWeapon: {EquipWeapon, PowerConstant, 10, 1.0},
+	EquipShield: {EquipShield, PowerConstant, 10, 1.0},
+	// Life support - scrubbers and heaters run off the reservation
+	EquipLifeSupport: {EquipLifeSupport, PowerConstant, 5, 1.0},
 	// Bridge stations - need to be ON to use
 	EquipNavConsole:     {EquipNavConsole, PowerConstant, 5, 1.0},
 	EquipPilotConsole:   {EquipPilotConsole, PowerConstant, 5, 1.0},
@@ -99,9 +104,19 @@ func NewEquipment(kind EquipmentKind) *Equipment {
 	}
 }
 
+// IsBroken returns true if the equipment has worn down to zero condition.
+// Broken equipment refuses power until it is repaired.
+func (e *Equipment) IsBroken() bool {
+	return e.Condition <= 0
+}
+
 // TryDrawPower attempts to draw power for this equipment.
-// Returns true if power was drawn (or not needed), false if insufficient power.
+// Returns true if power was drawn (or not needed), false if insufficient power
+// or the equipment is broken.
 func (e *Equipment) TryDrawPower(available *int) bool {
+	if e.IsBroken() {
+		return false
+	}
 	if e.PowerMode != PowerConstant || e.PowerCost == 0 {
 		return true // no constant power needed
 	}
@@ -113,7 +128,11 @@ func (e *Equipment) TryDrawPower(available *int) bool {
 }
 
 // CanUse checks if there's enough power for an on-use action.
+// Broken equipment can't be used.
 func (e *Equipment) CanUse(available int) bool {
+	if e.IsBroken() {
+		return false
+	}
 	if e.PowerMode != PowerOnUse || e.PowerCost == 0 {
 		return true
 	}
@@ -128,11 +147,12 @@ func (e *Equipment) Use(available *int) {
 }
 
 // Degrade reduces condition by amount. Returns new condition.
+// Equipment that hits zero is broken and switches off.
 func (e *Equipment) Degrade(amount int) int {
 	e.Condition = max(0, e.Condition-amount)
-	// Efficiency drops as condition drops
-	if e.Condition < 50 {
-		e.Efficiency = 0.5 + float64(e.Condition)/100.0
+	e.Efficiency = ConditionEfficiency(e.Condition)
+	if e.IsBroken() {
+		e.On = false
 	}
 	return e.Condition
 }
@@ -140,15 +160,20 @@ func (e *Equipment) Degrade(amount int) int {
 // Repair increases condition by amount. Returns new condition.
 func (e *Equipment) Repair(amount int) int {
 	e.Condition = min(100, e.Condition+amount)
-	// Restore efficiency
-	if e.Condition >= 50 {
-		e.Efficiency = 1.0
-	} else {
-		e.Efficiency = 0.5 + float64(e.Condition)/100.0
-	}
+	e.Efficiency = ConditionEfficiency(e.Condition)
 	return e.Condition
 }
 
+// ConditionEfficiency is the efficiency curve for worn equipment:
+// full output down to half condition, then falling off linearly
+// to 50% just before it breaks.
+func ConditionEfficiency(condition int) float64 {
+	if condition >= 50 {
+		return 1.0
+	}
+	return 0.5 + float64(condition)/100.0
+}
+
 // Name returns human-readable name for this equipment.
 func (e *Equipment) Name() string {
 	return equipmentNames[e.Kind]
@@ -178,6 +203,9 @@ var equipmentNames = map[EquipmentKind]string{
 	EquipWaterTank:       "Water Tank",
 	EquipFuelTank:        "Fuel Tank",
 	EquipJumpDrive:       "Jump Drive",
+	EquipWeapon:          "Pulse Cannon",
+	EquipShield:          "Shield Emitter",
+	EquipLifeSupport:     "Life Support",
 	EquipPowerCell:       "Battery",
 	EquipCargoTile:       "Cargo Pad",
 	EquipTerminal:        "Terminal",
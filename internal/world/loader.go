@@ -17,10 +17,12 @@ type ShipLayout struct {
 	Airlock [2]int       `json:"airlock,omitempty"` // optional explicit airlock position
 }
 
-// RoomDef defines a named room in a ship layout.
+// RoomDef defines a named room in a ship layout. At is any floor tile inside
+// the room; FindRooms uses it to put the name on the right region.
 type RoomDef struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
+	At   [2]int `json:"at,omitempty"`
 }
 
 // LoadShipLayout parses a ShipLayout from JSON bytes.
@@ -127,6 +129,13 @@ func charToTile(ch rune) Tile {
 		return Tile{Kind: TileFloor, Equipment: NewEquipment(EquipFuelTank)}
 	case 'J':
 		return Tile{Kind: TileFloor, Equipment: NewEquipment(EquipJumpDrive)}
+	case 'A':
+		return Tile{Kind: TileFloor, Equipment: NewEquipment(EquipLifeSupport)}
+	// --- combat ---
+	case 'T':
+		return Tile{Kind: TileFloor, Equipment: NewEquipment(EquipWeapon)}
+	case 'H':
+		return Tile{Kind: TileFloor, Equipment: NewEquipment(EquipShield)}
 	// --- cargo ---
 	case 'c':
 		return Tile{Kind: TileFloor, Equipment: NewEquipment(EquipCargoTile)}
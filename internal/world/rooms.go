@@ -0,0 +1,227 @@
+package world
+
+import "fmt"
+
+// Room is a region of floor enclosed by walls and doors.
+type Room struct {
+	ID    string
+	Name  string
+	Tiles [][2]int // floor tiles, equipment included
+	Hull  [][2]int // wall tiles between the room and the outside
+}
+
+// Volume returns the room's size in tiles.
+func (r *Room) Volume() int {
+	return len(r.Tiles)
+}
+
+// RoomDoor is a door tile and the two sides it joins. A side is a room index
+// or -1 for the outside.
+type RoomDoor struct {
+	X, Y int
+	A, B int
+}
+
+// Outside reports whether the door leads out of the ship.
+func (d RoomDoor) Outside() bool {
+	return d.A < 0 || d.B < 0
+}
+
+// RoomMap is the rooms of a tile grid and the doors between them.
+type RoomMap struct {
+	Rooms []Room
+	Doors []RoomDoor
+
+	width int
+	index []int // room per tile, -1 for none
+}
+
+// FindRooms flood-fills the floor of a grid into rooms bounded by walls and
+// doors. Void reachable from the grid edge is the outside; walls touching it
+// are hull. Rooms are named from the layout's room definitions: a definition
+// names the room its anchor tile falls in, and definitions sharing a room are
+// joined. Rooms without a definition are numbered compartments.
+func FindRooms(grid *TileGrid, defs []RoomDef) *RoomMap {
+	m := &RoomMap{width: grid.Width, index: make([]int, grid.Width*grid.Height)}
+	for i := range m.index {
+		m.index[i] = -1
+	}
+	outside := findOutside(grid)
+
+	for y := 0; y < grid.Height; y++ {
+		for x := 0; x < grid.Width; x++ {
+			if grid.Get(x, y).Kind != TileFloor || m.index[y*grid.Width+x] >= 0 {
+				continue
+			}
+			m.fillRoom(grid, x, y, outside)
+		}
+	}
+
+	for y := 0; y < grid.Height; y++ {
+		for x := 0; x < grid.Width; x++ {
+			if grid.Get(x, y).Kind != TileDoor {
+				continue
+			}
+			door := RoomDoor{X: x, Y: y, A: -1, B: -1}
+			sides := 0
+			for _, d := range neighbours4 {
+				nx, ny := x+d[0], y+d[1]
+				var side int
+				switch {
+				case grid.Get(nx, ny).Kind == TileFloor:
+					side = m.RoomAt(nx, ny)
+				case nx < 0 || nx >= grid.Width || ny < 0 || ny >= grid.Height || outside[ny*grid.Width+nx]:
+					side = -1
+				default:
+					continue // wall or another door
+				}
+				if sides == 1 && side == door.A {
+					continue
+				}
+				if sides == 0 {
+					door.A = side
+				} else {
+					door.B = side
+				}
+				if sides++; sides == 2 {
+					break
+				}
+			}
+			if sides == 2 {
+				m.index[y*grid.Width+x] = max(door.A, door.B)
+				m.Doors = append(m.Doors, door)
+			}
+		}
+	}
+
+	m.nameRooms(defs)
+	return m
+}
+
+var neighbours4 = [][2]int{{0, -1}, {1, 0}, {0, 1}, {-1, 0}}
+
+// findOutside marks the void tiles reachable from the edge of the grid.
+func findOutside(grid *TileGrid) []bool {
+	outside := make([]bool, grid.Width*grid.Height)
+	var queue [][2]int
+	push := func(x, y int) {
+		i := y*grid.Width + x
+		if x < 0 || x >= grid.Width || y < 0 || y >= grid.Height || outside[i] || grid.Get(x, y).Kind != TileVoid {
+			return
+		}
+		outside[i] = true
+		queue = append(queue, [2]int{x, y})
+	}
+	for x := 0; x < grid.Width; x++ {
+		push(x, 0)
+		push(x, grid.Height-1)
+	}
+	for y := 0; y < grid.Height; y++ {
+		push(0, y)
+		push(grid.Width-1, y)
+	}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		for _, d := range neighbours4 {
+			push(p[0]+d[0], p[1]+d[1])
+		}
+	}
+	return outside
+}
+
+// fillRoom flood-fills one room from a floor tile and finds its hull walls.
+func (m *RoomMap) fillRoom(grid *TileGrid, x, y int, outside []bool) {
+	id := len(m.Rooms)
+	room := Room{}
+	hull := make(map[int]bool)
+	queue := [][2]int{{x, y}}
+	m.index[y*grid.Width+x] = id
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		room.Tiles = append(room.Tiles, p)
+		for _, d := range neighbours4 {
+			nx, ny := p[0]+d[0], p[1]+d[1]
+			if nx < 0 || nx >= grid.Width || ny < 0 || ny >= grid.Height {
+				continue
+			}
+			i := ny*grid.Width + nx
+			switch grid.Get(nx, ny).Kind {
+			case TileFloor:
+				if m.index[i] < 0 {
+					m.index[i] = id
+					queue = append(queue, [2]int{nx, ny})
+				}
+			case TileWall:
+				if !hull[i] && touchesOutside(grid, nx, ny, outside) {
+					hull[i] = true
+					room.Hull = append(room.Hull, [2]int{nx, ny})
+				}
+			}
+		}
+	}
+	m.Rooms = append(m.Rooms, room)
+}
+
+// touchesOutside reports whether a tile has outside void around it,
+// diagonals included.
+func touchesOutside(grid *TileGrid, x, y int, outside []bool) bool {
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			nx, ny := x+dx, y+dy
+			if nx < 0 || nx >= grid.Width || ny < 0 || ny >= grid.Height {
+				return true
+			}
+			if outside[ny*grid.Width+nx] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nameRooms applies the layout's room definitions by anchor.
+func (m *RoomMap) nameRooms(defs []RoomDef) {
+	for _, def := range defs {
+		if def.At == [2]int{} {
+			continue
+		}
+		id := m.RoomAt(def.At[0], def.At[1])
+		if id < 0 || m.isDoor(def.At[0], def.At[1]) {
+			continue
+		}
+		room := &m.Rooms[id]
+		if room.ID == "" {
+			room.ID, room.Name = def.ID, def.Name
+		} else {
+			room.Name += " / " + def.Name
+		}
+	}
+	n := 0
+	for i := range m.Rooms {
+		if m.Rooms[i].ID == "" {
+			n++
+			m.Rooms[i].ID = fmt.Sprintf("compartment_%d", n)
+			m.Rooms[i].Name = fmt.Sprintf("Compartment %d", n)
+		}
+	}
+}
+
+func (m *RoomMap) isDoor(x, y int) bool {
+	for _, d := range m.Doors {
+		if d.X == x && d.Y == y {
+			return true
+		}
+	}
+	return false
+}
+
+// RoomAt returns the index of the room at (x, y), or -1 outside any room.
+// A door counts as part of a room it opens onto.
+func (m *RoomMap) RoomAt(x, y int) int {
+	if x < 0 || x >= m.width || y < 0 || y*m.width+x >= len(m.index) {
+		return -1
+	}
+	return m.index[y*m.width+x]
+}
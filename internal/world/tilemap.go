@@ -21,7 +21,7 @@ const (
 	// Surface terrain tiles
 	TileGround     // walkable terrain (color varies by TerrainType)
 	TileRock       // impassable terrain obstacle
-	TileHazard     // impassable hazard (lava, crevasse)
+	TileHazard     // hazard (lava, crevasse): impassable without hazard gear
 	TileShuttlePad // landing/exit point (walkable)
 )
 
@@ -41,7 +41,7 @@ const (
 	EquipCargoConsole                  // cargo management terminal
 	EquipCargoTransporter              // beams cargo to/from surface
 	EquipIncinerator                   // waste disposal (future)
-	EquipMedical                       // medical station (future)
+	EquipMedical                       // medical station
 	EquipFoodStation                   // food replicator (clean organic → body)
 	EquipDrinkStation                  // drink replicator (clean water → body)
 	EquipToilet                        // waste processing
@@ -63,6 +63,11 @@ const (
 	EquipFuelCell   // fuel cells for shuttle
 	EquipSpareParts // engine parts for repair
 	EquipPowerPack  // power cell for charging
+	// Ship combat
+	EquipWeapon // weapon mount (ship-to-ship combat)
+	EquipShield // shield emitter (absorbs incoming fire)
+	// Life support
+	EquipLifeSupport // scrubs CO2, makes O2 and heats every room
 )
 
 // Tile represents a single map tile.
@@ -113,6 +118,20 @@ func (g *TileGrid) IsWalkable(x, y int) bool {
 	}
 }
 
+// BlocksSight returns true if (x, y) can't be seen past: walls, rocks and
+// shut doors. Out-of-bounds blocks sight too.
+func (g *TileGrid) BlocksSight(x, y int) bool {
+	t := g.Get(x, y)
+	switch t.Kind {
+	case TileWall, TileRock, TileVoid:
+		return true
+	case TileDoor:
+		return t.Equipment == nil || !t.Equipment.Open
+	default:
+		return false
+	}
+}
+
 // SetEquipmentOn sets the on/off state for equipment at (x, y).
 func (g *TileGrid) SetEquipmentOn(x, y int, on bool) {
 	if x >= 0 && x < g.Width && y >= 0 && y < g.Height {
@@ -176,6 +195,7 @@ func (g *TileGrid) SetAllEquipmentState(on bool) {
 		EquipPilotConsole:     true,
 		EquipScienceConsole:   true,
 		EquipCargoConsole:     true,
+		EquipLifeSupport:      true,
 	}
 	for i := range g.Tiles {
 		if eq := g.Tiles[i].Equipment; eq != nil && toggleable[eq.Kind] {
@@ -266,4 +286,7 @@ var equipDescriptions = map[EquipmentKind]string{
 	EquipFuelCell:       "Fuel Cells - shuttle fuel supply",
 	EquipSpareParts:     "Spare Parts - engine components",
 	EquipPowerPack:      "Power Pack - portable battery",
+	EquipWeapon:         "Pulse Cannon - T: arm for combat",
+	EquipShield:         "Shield Emitter - T: raise shields",
+	EquipLifeSupport:    "Life Support - air and heat for every room",
 }